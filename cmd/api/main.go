@@ -1,75 +1,284 @@
 package main
 
 import (
+	"class-management/internal/auth"
+	"class-management/internal/config"
+	"class-management/internal/database"
+	"class-management/internal/deadline"
 	"class-management/internal/handler"
+	"class-management/internal/health"
+	"class-management/internal/logging"
+	"class-management/internal/metrics"
+	"class-management/internal/migrate"
 	"class-management/internal/models"
+	"class-management/internal/notifier"
+	"class-management/internal/service/class"
+	"class-management/internal/service/directory"
+	"class-management/internal/service/notification"
 	"class-management/internal/service/teacher"
+	"class-management/internal/tracing"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
 )
 
 func main() {
-	//connect to db
-	db, err := getDb()
-	if err != nil {
+	if err := run(); err != nil {
 		log.Fatal(err)
 	}
-	models.DB = db
+}
+
+//run serves the api until it is interrupted. Returning instead of exiting lets the deferred cleanup run.
+func run() error {
+	//config comes from the defaults, an optional config file, env variables and flags
+	cfg, args, err := config.Load(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	//structured JSON logs, every request gets a logger carrying its request ID
+	logger, err := logging.New(os.Stdout, cfg.LogLevel)
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+	logger.Info("loaded config", zap.Stringer("config", cfg))
+	if len(args) > 0 && args[0] != "migrate" {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	//SIGINT or SIGTERM cancels ctx and starts the graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	//connect to the db selected by DB_DRIVER
+	db, err := database.Open(cfg.Database)
+	if err != nil {
+		return err
+	}
+	db.Logger = logging.NewGormLogger(logger)
 
 	//close the db connection when application exits
 	dbClose, err := db.DB()
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	defer dbClose.Close()
 
+	//api migrate up|down|status manages the schema and exits
+	if len(args) > 0 {
+		return runMigrate(dbClose, cfg.Database.Driver, args[1:])
+	}
+
+	//bring the schema up to date before serving, unless disabled to migrate separately
+	migrator, err := newMigrator(dbClose, cfg.Database.Driver, migrate.Options{Out: zap.NewStdLog(logger).Writer()})
+	if err != nil {
+		return err
+	}
+	if cfg.MigrateOnStart {
+		if _, err := migrator.Up(ctx); err != nil {
+			return err
+		}
+	}
+
+	//spans of the http, service and db layers, sent to the exporter selected by TRACING_EXPORTER
+	tracerProvider, shutdownTracing, err := tracing.New(ctx, cfg.Tracing, os.Stderr)
+	if err != nil {
+		return err
+	}
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warn("exporting spans failed", zap.Error(err))
+	}))
+	defer func() {
+		//flush the spans still buffered, bounded like the rest of the shutdown
+		flushCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeout))
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			logger.Warn("flushing spans failed", zap.Error(err))
+		}
+	}()
+	if err := db.Use(tracing.GormPlugin(tracerProvider)); err != nil {
+		return err
+	}
+
+	//metrics of the http, service and db layers, served at /metrics
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(dbClose, string(cfg.Database.Driver)),
+	)
+	appMetrics := metrics.New(registry)
+	if err := db.Use(appMetrics.GormPlugin()); err != nil {
+		return err
+	}
+
 	teacherRepo := models.NewTeacherRepo(db)
 	studentRepo := models.NewStudentRepo(db)
 	teacherStudentRepo := models.NewTeacherStudentRepo(db)
-	teacherService := teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo)
+	classRepo := models.NewClassRepo(db)
+	notificationRepo := models.NewNotificationRepo(db)
+	unitOfWork := models.NewUnitOfWork(db)
+	teacherService := teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo, classRepo, unitOfWork, logger)
+	teacherService = tracing.NewTeacherService(metrics.NewTeacherService(teacherService, appMetrics), tracerProvider)
 	teacherHandler := handler.NewTeacherHandler(teacherService)
+	classService := class.NewClassService(classRepo, teacherRepo, unitOfWork, logger)
+	classHandler := handler.NewClassHandler(classService)
+	notificationService := notification.NewNotificationService(teacherRepo, notificationRepo)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	directoryService := directory.NewDirectoryService(teacherRepo, studentRepo, teacherStudentRepo, logger)
+	directoryHandler := handler.NewDirectoryHandler(directoryService)
 
-	router := mux.NewRouter().PathPrefix("/api").Subrouter()
+	//every api call must carry an API key or a signed bearer token
+	apiKeys, err := auth.ParseAPIKeys(cfg.APIKeys)
+	if err != nil {
+		return err
+	}
+	authenticator := auth.NewAuthenticator(apiKeys, cfg.AuthTokenSecret)
+
+	//deliver queued notifications in the background
+	deliveryNotifier, err := notifier.New(cfg.Notifier)
+	if err != nil {
+		return err
+	}
+	//closing workerStop ends the polling, while a send in flight keeps going until workerCtx is cancelled by the shutdown timeout
+	deliveryWorker := notifier.NewWorker(models.NewDeliveryRepo(db), deliveryNotifier, notifier.WorkerOptions{Logger: logger})
+	workerCtx, cancelWorker := context.WithCancel(context.Background())
+	defer cancelWorker()
+	workerStop := make(chan struct{})
+	workerDone := make(chan struct{})
+	go func() {
+		deliveryWorker.Run(workerCtx, workerStop)
+		close(workerDone)
+	}()
+
+	//the app is ready once the db answers, the schema is current and the worker is polling
+	readiness := health.NewChecker(0)
+	readiness.Add("database", dbClose.PingContext)
+	readiness.Add("migrations", migrationsCheck(migrator))
+	readiness.Add("delivery_worker", deliveryWorker.Check)
+	healthHandler := handler.NewHealthHandler(readiness)
+
+	//every request gets a deadline, after which its database work is cancelled
+	routeTimeouts, err := deadline.ParseRouteTimeouts(cfg.RouteTimeouts)
+	if err != nil {
+		return err
+	}
+
+	//health and metrics endpoints sit outside /api and need no credential, so probes and scrapers can call them
+	rootRouter := mux.NewRouter()
+	rootRouter.Use(
+		metrics.Middleware(appMetrics),
+		tracing.Middleware(tracerProvider),
+		deadline.Middleware(time.Duration(cfg.RequestTimeout), routeTimeouts),
+	)
+	//unknown endpoints and methods are answered with problem details like any other error
+	rootRouter.NotFoundHandler = http.HandlerFunc(handler.NotFound)
+	rootRouter.MethodNotAllowedHandler = http.HandlerFunc(handler.MethodNotAllowed)
+	rootRouter.HandleFunc("/healthz", healthHandler.Liveness).Methods(http.MethodGet)
+	rootRouter.HandleFunc("/readyz", healthHandler.Readiness).Methods(http.MethodGet)
+	rootRouter.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{})).Methods(http.MethodGet)
+
+	router := rootRouter.PathPrefix("/api").Subrouter()
+	router.Use(auth.Middleware(authenticator))
+	router.NotFoundHandler = rootRouter.NotFoundHandler
+	router.MethodNotAllowedHandler = rootRouter.MethodNotAllowedHandler
 
 	router.HandleFunc("/", homeHandler).Methods("GET")
 
 	router.HandleFunc("/register", teacherHandler.RegisterStudents).Methods(http.MethodPost)
+	router.HandleFunc("/deregister", teacherHandler.DeregisterStudents).Methods(http.MethodPost)
 	router.HandleFunc("/suspend", teacherHandler.SuspendStudent).Methods(http.MethodPost)
+	router.HandleFunc("/unsuspend", teacherHandler.UnsuspendStudent).Methods(http.MethodPost)
+	router.HandleFunc("/students/{email}/status", teacherHandler.ChangeStudentStatus).Methods(http.MethodPost)
+	router.HandleFunc("/graduate", teacherHandler.GraduateStudents).Methods(http.MethodPost)
 	router.HandleFunc("/commonstudents", teacherHandler.CommonStudentsOfTeachers).Methods(http.MethodGet)
 	router.HandleFunc("/retrievefornotifications", teacherHandler.FetchStudentsForNotification).Methods(http.MethodPost)
 	router.HandleFunc("/registerteachers", teacherHandler.RegisterTeachers).Methods(http.MethodPost)
 
-	log.Println("Application has started. Listening port is 8080")
-	http.ListenAndServe(":8080", router)
+	router.HandleFunc("/teachers", directoryHandler.ListTeachers).Methods(http.MethodGet)
+	router.HandleFunc("/teachers/{email}", directoryHandler.GetTeacher).Methods(http.MethodGet)
+	router.HandleFunc("/teachers/{email}", directoryHandler.UpdateTeacher).Methods(http.MethodPatch)
+	router.HandleFunc("/teachers/{email}/students", directoryHandler.TeacherStudents).Methods(http.MethodGet)
+	router.HandleFunc("/students", directoryHandler.ListStudents).Methods(http.MethodGet)
+	router.HandleFunc("/students/{email}", directoryHandler.GetStudent).Methods(http.MethodGet)
+	router.HandleFunc("/students/{email}", directoryHandler.UpdateStudent).Methods(http.MethodPatch)
+	router.HandleFunc("/students/{email}/enrolments", directoryHandler.StudentEnrolments).Methods(http.MethodGet)
 
-}
+	router.HandleFunc("/classes", classHandler.CreateClass).Methods(http.MethodPost)
+	router.HandleFunc("/classes/{id}/teachers", classHandler.AssignClassTeachers).Methods(http.MethodPost)
+	router.HandleFunc("/classes/{id}/students", classHandler.EnrolClassStudents).Methods(http.MethodPost)
+	router.HandleFunc("/classes/{id}/students", classHandler.ClassRoster).Methods(http.MethodGet)
 
-func homeHandler(w http.ResponseWriter, r *http.Request) {
-	log.Println(os.Getenv("DB_USER"))
-	log.Println(os.Getenv("MYSQL_ROOT_PASSWORD"))
-	fmt.Fprintf(w, "Hello, World!")
-}
+	router.HandleFunc("/teachers/{email}/notifications", notificationHandler.TeacherNotifications).Methods(http.MethodGet)
+	router.HandleFunc("/notifications/{id}", notificationHandler.GetNotification).Methods(http.MethodGet)
 
-func getDb() (*gorm.DB, error) {
+	//errors of the http server itself, such as failed TLS handshakes
+	serverLog, err := zap.NewStdLogAt(logger, zap.WarnLevel)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.HTTPPort),
+		Handler:           logging.RequestID(logger)(logging.AccessLog(rootRouter)),
+		ReadTimeout:       time.Duration(cfg.HTTPReadTimeout),
+		ReadHeaderTimeout: time.Duration(cfg.HTTPReadHeaderTimeout),
+		WriteTimeout:      time.Duration(cfg.HTTPWriteTimeout),
+		IdleTimeout:       time.Duration(cfg.HTTPIdleTimeout),
+		ErrorLog:          serverLog,
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Info("application has started", zap.Int("port", cfg.HTTPPort))
+		serveErr <- server.ListenAndServe()
+	}()
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_NAME"),
-	)
+	select {
+	case err := <-serveErr:
+		//the server failed to start, stop the worker before the db is closed
+		stop()
+		close(workerStop)
+		<-workerDone
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+	close(workerStop)
+	logger.Info("shutting down, waiting for in-flight work", zap.Stringer("timeout", cfg.ShutdownTimeout))
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
-	if err != nil {
-		return nil, err
+	//stop accepting connections and let in-flight requests and the worker finish
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeout))
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down http server: %w", err)
+	}
+	select {
+	case <-workerDone:
+	case <-shutdownCtx.Done():
+		cancelWorker()
+		return errors.New("notification worker did not stop before the shutdown timeout")
 	}
+	logger.Info("application has stopped")
+	return nil
+}
 
-	return db, nil
+func homeHandler(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, "Hello, World!")
 }
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"class-management/db"
+	"class-management/internal/database"
+	"class-management/internal/health"
+	"class-management/internal/migrate"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const migrateUsage = `usage: api migrate [-dry-run] up
+       api migrate [-dry-run] down [steps]
+       api migrate status`
+
+//newMigrator loads the embedded migrations of the driver
+func newMigrator(sqlDB *sql.DB, driver database.Driver, options migrate.Options) (*migrate.Migrator, error) {
+	migrations, err := migrate.Load(db.Migrations, "migrations/"+string(driver))
+	if err != nil {
+		return nil, err
+	}
+	options.Driver = driver
+
+	return migrate.NewMigrator(sqlDB, migrations, options), nil
+}
+
+//migrationsCheck fails while the database is behind the embedded migrations, for example when
+//MIGRATE_ON_START is off and the migrate subcommand hasn't been run yet
+func migrationsCheck(migrator *migrate.Migrator) health.Check {
+	return func(ctx context.Context) error {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			labels := make([]string, len(pending))
+			for i, migration := range pending {
+				labels[i] = migration.Label()
+			}
+			return fmt.Errorf("pending migrations: %s", strings.Join(labels, ", "))
+		}
+		return nil
+	}
+}
+
+//runMigrate implements the migrate subcommand
+func runMigrate(sqlDB *sql.DB, driver database.Driver, args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "print the SQL of the pending migrations without executing it")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	migrator, err := newMigrator(sqlDB, driver, migrate.Options{DryRun: *dryRun, Out: os.Stdout})
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch flags.Arg(0) {
+	case "up":
+		done, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("no pending migrations")
+		}
+	case "down":
+		steps := 1
+		if flags.NArg() > 1 {
+			steps, err = strconv.Atoi(flags.Arg(1))
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid steps %q", flags.Arg(1))
+			}
+		}
+		done, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("no applied migrations")
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%s\t%s\n", status.Label(), appliedAt)
+		}
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	return nil
+}
@@ -0,0 +1,8 @@
+package db
+
+import "embed"
+
+//Versioned schema migrations, one directory per database driver. Files are named <version>_<name>.up.sql and <version>_<name>.down.sql
+//
+//go:embed migrations/*/*.sql
+var Migrations embed.FS
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS student_status_changes
+(
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    student_id INT NOT NULL,
+    from_status ENUM('ACTIVE', 'SUSPENDED', 'GRADUATED') NOT NULL,
+    to_status ENUM('ACTIVE', 'SUSPENDED', 'GRADUATED') NOT NULL,
+    changed_by VARCHAR(255),
+    reason VARCHAR(1024),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE
+);
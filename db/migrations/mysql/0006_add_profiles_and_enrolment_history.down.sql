@@ -0,0 +1,19 @@
+ALTER TABLE teacher_students
+    DROP INDEX index_teacher_students_deleted_at,
+    DROP COLUMN deleted_at,
+    DROP COLUMN archived_at;
+
+ALTER TABLE students
+    DROP INDEX index_students_status_created_at,
+    DROP INDEX index_students_student_number,
+    DROP COLUMN metadata,
+    DROP COLUMN grade_level,
+    DROP COLUMN student_number,
+    DROP COLUMN preferred_name,
+    DROP COLUMN name;
+
+ALTER TABLE teachers
+    DROP COLUMN metadata,
+    DROP COLUMN department,
+    DROP COLUMN preferred_name,
+    DROP COLUMN name;
@@ -0,0 +1,19 @@
+ALTER TABLE teachers
+    ADD COLUMN name VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN preferred_name VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN department VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN metadata JSON NULL;
+
+ALTER TABLE students
+    ADD COLUMN name VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN preferred_name VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN student_number VARCHAR(255) NULL,
+    ADD COLUMN grade_level VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN metadata JSON NULL,
+    ADD UNIQUE INDEX index_students_student_number (student_number),
+    ADD INDEX index_students_status_created_at (status, created_at);
+
+ALTER TABLE teacher_students
+    ADD COLUMN archived_at TIMESTAMP NULL DEFAULT NULL,
+    ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL,
+    ADD INDEX index_teacher_students_deleted_at (deleted_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS class_students;
+DROP TABLE IF EXISTS class_teachers;
+DROP TABLE IF EXISTS classes;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS classes
+(
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS class_teachers
+(
+    id SERIAL PRIMARY KEY,
+    class_id INT NOT NULL REFERENCES classes(id) ON DELETE CASCADE,
+    teacher_id INT NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_class_teacher UNIQUE (class_id, teacher_id)
+);
+
+CREATE TABLE IF NOT EXISTS class_students
+(
+    id SERIAL PRIMARY KEY,
+    class_id INT NOT NULL REFERENCES classes(id) ON DELETE CASCADE,
+    student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_class_student UNIQUE (class_id, student_id)
+);
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS notifications
+(
+    id SERIAL PRIMARY KEY,
+    teacher_id INT NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+    text TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS index_notifications_teacher_id ON notifications (teacher_id);
+
+CREATE TABLE IF NOT EXISTS notification_recipients
+(
+    id SERIAL PRIMARY KEY,
+    notification_id INT NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    registered BOOLEAN NOT NULL DEFAULT FALSE,
+    mentioned BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS index_notification_recipients_notification_id ON notification_recipients (notification_id);
+
+CREATE TABLE IF NOT EXISTS notification_deliveries
+(
+    id SERIAL PRIMARY KEY,
+    notification_id INT NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    recipient_id INT NOT NULL REFERENCES notification_recipients(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SENT', 'FAILED')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error VARCHAR(1024),
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMPTZ NULL DEFAULT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS index_notification_deliveries_due ON notification_deliveries (status, next_attempt_at);
@@ -0,0 +1,2 @@
+ALTER TABLE notification_deliveries DROP COLUMN locked_until;
+ALTER TABLE notification_deliveries DROP COLUMN claim_token;
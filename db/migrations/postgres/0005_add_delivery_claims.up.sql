@@ -0,0 +1,2 @@
+ALTER TABLE notification_deliveries ADD COLUMN claim_token VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE notification_deliveries ADD COLUMN locked_until TIMESTAMPTZ NULL DEFAULT NULL;
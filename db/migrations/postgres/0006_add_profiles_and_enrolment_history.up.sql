@@ -0,0 +1,18 @@
+ALTER TABLE teachers ADD COLUMN name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE teachers ADD COLUMN preferred_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE teachers ADD COLUMN department VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE teachers ADD COLUMN metadata JSON NULL;
+
+ALTER TABLE students ADD COLUMN name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE students ADD COLUMN preferred_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE students ADD COLUMN student_number VARCHAR(255) NULL;
+ALTER TABLE students ADD COLUMN grade_level VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE students ADD COLUMN metadata JSON NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS index_students_student_number ON students (student_number);
+CREATE INDEX IF NOT EXISTS index_students_status_created_at ON students (status, created_at);
+
+ALTER TABLE teacher_students ADD COLUMN archived_at TIMESTAMPTZ NULL DEFAULT NULL;
+ALTER TABLE teacher_students ADD COLUMN deleted_at TIMESTAMPTZ NULL DEFAULT NULL;
+
+CREATE INDEX IF NOT EXISTS index_teacher_students_deleted_at ON teacher_students (deleted_at);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS student_status_changes
+(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    student_id INTEGER NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    from_status VARCHAR(16) NOT NULL CHECK (from_status IN ('ACTIVE', 'SUSPENDED', 'GRADUATED')),
+    to_status VARCHAR(16) NOT NULL CHECK (to_status IN ('ACTIVE', 'SUSPENDED', 'GRADUATED')),
+    changed_by VARCHAR(255),
+    reason VARCHAR(1024),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS index_student_status_changes_student_id ON student_status_changes (student_id);
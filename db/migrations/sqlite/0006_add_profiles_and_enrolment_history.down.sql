@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS index_teacher_students_deleted_at;
+ALTER TABLE teacher_students DROP COLUMN deleted_at;
+ALTER TABLE teacher_students DROP COLUMN archived_at;
+
+DROP INDEX IF EXISTS index_students_status_created_at;
+DROP INDEX IF EXISTS index_students_student_number;
+ALTER TABLE students DROP COLUMN metadata;
+ALTER TABLE students DROP COLUMN grade_level;
+ALTER TABLE students DROP COLUMN student_number;
+ALTER TABLE students DROP COLUMN preferred_name;
+ALTER TABLE students DROP COLUMN name;
+
+ALTER TABLE teachers DROP COLUMN metadata;
+ALTER TABLE teachers DROP COLUMN department;
+ALTER TABLE teachers DROP COLUMN preferred_name;
+ALTER TABLE teachers DROP COLUMN name;
@@ -1,42 +1,91 @@
 package errors
 
 import (
-	"encoding/json"
+	"context"
+	stderrors "errors"
 	"fmt"
 	"net/http"
 )
 
+//ApiError is an error that can be shown to the client. Status is the HTTP status of the response
+//and Code a stable, machine readable name of the error that clients can match on.
 type ApiError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Status  int
+	Code    string
+	Message string
 }
 
 func (err ApiError) Error() string {
 	return fmt.Sprintf("error_code = %v, error_message = %v", err.Code, err.Message)
 }
 
-func CreateError(code int, message string) ApiError {
-	return ApiError{Code: code, Message: message}
+func CreateError(status int, code string, message string) ApiError {
+	return ApiError{Status: status, Code: code, Message: message}
 }
 
-func JSONError(w http.ResponseWriter, err interface{}, code int) {
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.Header().Set("X-Content-Type-Options", "nosniff")
-	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(err)
+//From returns the ApiError to show the client for err. A context that ran out of time or was cancelled
+//has its own error, and any other error is internal, so its details never reach the client.
+func From(err error) ApiError {
+	var apiErr ApiError
+	switch {
+	case stderrors.As(err, &apiErr):
+		return apiErr
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return ErrRequestTimeout
+	case stderrors.Is(err, context.Canceled):
+		return ErrRequestCancelled
+	default:
+		return ErrInternal
+	}
 }
 
-var Success = ApiError{Code: 200, Message: "success"}
-
-var ErrAccountNotFound = ApiError{Code: 422, Message: "Invalid account!"}
-var ErrDecodingRequest = ApiError{Code: 422, Message: "Please pass valid parameters!"}
-var ErrTeacherRequired = ApiError{Code: 422, Message: "A valid teacher email is required!"}
-var ErrTeachersRequired = ApiError{Code: 422, Message: "No teachers provided for registration!"}
-var ErrStudentRequired = ApiError{Code: 422, Message: "A valid student email is required!"}
-var ErrStudentsRequired = ApiError{Code: 422, Message: "No students provided for registration!"}
-var ErrTeacherNotExists = ApiError{Code: 422, Message: "Teacher's email you provided doesn't exists!"}
-var ErrStudentNotExists = ApiError{Code: 422, Message: "Student's email you provided doesn't exists!"}
-var ErrMissingTeacherParam = ApiError{Code: 422, Message: "Teacher parameter is missing in the request!"}
-var ErrNotificationRequired = ApiError{Code: 422, Message: "Please enter notification text!"}
-var ErrInvalidTeacherEmail = ApiError{Code: 422, Message: "Please enter valid teacher's email!"}
-var ErrInvalidStudentEmail = ApiError{Code: 422, Message: "Please enter valid student's email!"}
+//StatusClientClosedRequest is the non standard status of a request whose client went away before the response
+const StatusClientClosedRequest = 499
+
+var Success = ApiError{Status: http.StatusOK, Code: "SUCCESS", Message: "success"}
+
+var ErrAccountNotFound = ApiError{Status: http.StatusNotFound, Code: "ACCOUNT_NOT_FOUND", Message: "Invalid account!"}
+var ErrDecodingRequest = ApiError{Status: http.StatusBadRequest, Code: "INVALID_REQUEST_BODY", Message: "Please pass valid parameters!"}
+var ErrTeacherRequired = ApiError{Status: http.StatusUnprocessableEntity, Code: "TEACHER_REQUIRED", Message: "A valid teacher email is required!"}
+var ErrTeachersRequired = ApiError{Status: http.StatusUnprocessableEntity, Code: "TEACHERS_REQUIRED", Message: "No teachers provided for registration!"}
+var ErrStudentRequired = ApiError{Status: http.StatusUnprocessableEntity, Code: "STUDENT_REQUIRED", Message: "A valid student email is required!"}
+var ErrStudentsRequired = ApiError{Status: http.StatusUnprocessableEntity, Code: "STUDENTS_REQUIRED", Message: "No students provided for registration!"}
+var ErrTeacherNotExists = ApiError{Status: http.StatusNotFound, Code: "TEACHER_NOT_FOUND", Message: "Teacher's email you provided doesn't exists!"}
+var ErrStudentNotExists = ApiError{Status: http.StatusNotFound, Code: "STUDENT_NOT_FOUND", Message: "Student's email you provided doesn't exists!"}
+var ErrMissingTeacherParam = ApiError{Status: http.StatusUnprocessableEntity, Code: "TEACHER_PARAM_REQUIRED", Message: "Teacher parameter is missing in the request!"}
+var ErrNotificationRequired = ApiError{Status: http.StatusUnprocessableEntity, Code: "NOTIFICATION_REQUIRED", Message: "Please enter notification text!"}
+var ErrInvalidTeacherEmail = ApiError{Status: http.StatusUnprocessableEntity, Code: "INVALID_TEACHER_EMAIL", Message: "Please enter valid teacher's email!"}
+var ErrInvalidStudentEmail = ApiError{Status: http.StatusUnprocessableEntity, Code: "INVALID_STUDENT_EMAIL", Message: "Please enter valid student's email!"}
+var ErrStatusRequired = ApiError{Status: http.StatusUnprocessableEntity, Code: "STATUS_REQUIRED", Message: "A student status is required!"}
+var ErrInvalidStudentStatus = ApiError{Status: http.StatusUnprocessableEntity, Code: "INVALID_STUDENT_STATUS", Message: "Please enter valid student status!"}
+var ErrInvalidStatusTransition = ApiError{Status: http.StatusConflict, Code: "INVALID_STATUS_TRANSITION", Message: "Student's status cannot be changed to the requested status!"}
+var ErrStatusReasonTooLong = ApiError{Status: http.StatusUnprocessableEntity, Code: "STATUS_REASON_TOO_LONG", Message: "Reason must be at most 1024 characters long!"}
+var ErrStudentStatusChanged = ApiError{Status: http.StatusConflict, Code: "STUDENT_STATUS_CHANGED", Message: "Student's status was changed by another request, please try again!"}
+var ErrGraduationTargetRequired = ApiError{Status: http.StatusUnprocessableEntity, Code: "GRADUATION_TARGET_REQUIRED", Message: "Please provide students, or a teacher or grade level whose students should graduate!"}
+var ErrInvalidIncludeGraduated = ApiError{Status: http.StatusUnprocessableEntity, Code: "INVALID_INCLUDE_GRADUATED", Message: "include_graduated must be true or false!"}
+var ErrStudentsRequiredForDeregistration = ApiError{Status: http.StatusUnprocessableEntity, Code: "STUDENTS_REQUIRED_FOR_DEREGISTRATION", Message: "No students provided for deregistration!"}
+var ErrInvalidEmailsInBatch = ApiError{Status: http.StatusUnprocessableEntity, Code: "INVALID_EMAILS_IN_BATCH", Message: "Registration rejected because some emails are invalid!"}
+var ErrRegistrationRolledBack = ApiError{Status: http.StatusInternalServerError, Code: "REGISTRATION_ROLLED_BACK", Message: "Registration failed, no students were registered!"}
+var ErrClassNameRequired = ApiError{Status: http.StatusUnprocessableEntity, Code: "CLASS_NAME_REQUIRED", Message: "A class name is required!"}
+var ErrClassAlreadyExists = ApiError{Status: http.StatusConflict, Code: "CLASS_ALREADY_EXISTS", Message: "A class with this name already exists!"}
+var ErrClassNotExists = ApiError{Status: http.StatusNotFound, Code: "CLASS_NOT_FOUND", Message: "Class you provided doesn't exists!"}
+var ErrInvalidClassID = ApiError{Status: http.StatusUnprocessableEntity, Code: "INVALID_CLASS_ID", Message: "Please enter valid class id!"}
+var ErrTeacherNotInClass = ApiError{Status: http.StatusUnprocessableEntity, Code: "TEACHER_NOT_IN_CLASS", Message: "Teacher is not assigned to this class!"}
+var ErrUnauthorized = ApiError{Status: http.StatusUnauthorized, Code: "UNAUTHORIZED", Message: "A valid API key or bearer token is required!"}
+var ErrForbidden = ApiError{Status: http.StatusForbidden, Code: "FORBIDDEN", Message: "You are not allowed to act on behalf of this teacher!"}
+var ErrAdminRequired = ApiError{Status: http.StatusForbidden, Code: "ADMIN_REQUIRED", Message: "Only admins are allowed to do this!"}
+var ErrNotStudentsTeacher = ApiError{Status: http.StatusForbidden, Code: "NOT_STUDENTS_TEACHER", Message: "You are not allowed to act on a student who isn't registered with you!"}
+var ErrNotClassTeacher = ApiError{Status: http.StatusForbidden, Code: "NOT_CLASS_TEACHER", Message: "You are not allowed to act on a class you aren't assigned to!"}
+var ErrNotificationNotExists = ApiError{Status: http.StatusNotFound, Code: "NOTIFICATION_NOT_FOUND", Message: "Notification you requested doesn't exists!"}
+var ErrInvalidNotificationID = ApiError{Status: http.StatusUnprocessableEntity, Code: "INVALID_NOTIFICATION_ID", Message: "Please enter valid notification id!"}
+var ErrInvalidPagination = ApiError{Status: http.StatusUnprocessableEntity, Code: "INVALID_PAGINATION", Message: "limit must be between 1 and 1000 and offset must not be negative!"}
+var ErrInvalidCommonStudentsMode = ApiError{Status: http.StatusUnprocessableEntity, Code: "INVALID_COMMON_STUDENTS_MODE", Message: "mode must be all, any or at_least!"}
+var ErrInvalidMinTeachers = ApiError{Status: http.StatusUnprocessableEntity, Code: "INVALID_MIN_TEACHERS", Message: "min must be between 1 and the number of teachers!"}
+var ErrStudentNumberTaken = ApiError{Status: http.StatusConflict, Code: "STUDENT_NUMBER_TAKEN", Message: "Student number you provided belongs to another student!"}
+var ErrInvalidProfile = ApiError{Status: http.StatusUnprocessableEntity, Code: "INVALID_PROFILE", Message: "Profile fields must be at most 255 characters long!"}
+var ErrInvalidSort = ApiError{Status: http.StatusUnprocessableEntity, Code: "INVALID_SORT", Message: "sort must be email or created_at and order must be asc or desc!"}
+var ErrRouteNotFound = ApiError{Status: http.StatusNotFound, Code: "ROUTE_NOT_FOUND", Message: "The requested endpoint doesn't exists!"}
+var ErrMethodNotAllowed = ApiError{Status: http.StatusMethodNotAllowed, Code: "METHOD_NOT_ALLOWED", Message: "The endpoint doesn't support this method!"}
+var ErrRequestTimeout = ApiError{Status: http.StatusGatewayTimeout, Code: "REQUEST_TIMEOUT", Message: "The request took too long, please try again later!"}
+var ErrRequestCancelled = ApiError{Status: StatusClientClosedRequest, Code: "REQUEST_CANCELLED", Message: "The request was cancelled before it finished!"}
+var ErrInternal = ApiError{Status: http.StatusInternalServerError, Code: "INTERNAL_ERROR", Message: "Something went wrong, please try again later!"}
@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+//ProblemContentType is the media type of RFC 7807 problem details
+const ProblemContentType = "application/problem+json"
+
+//Problem is an RFC 7807 problem details body. The error code and the request ID are extension members.
+//The type is always about:blank, so clients tell problems apart by their code.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+//NewProblem describes err, as shown to the client by From, for the request. When the request ID middleware
+//has set the X-Request-ID response header, the ID is added as request_id.
+func NewProblem(w http.ResponseWriter, r *http.Request, err error) Problem {
+	apiErr := From(err)
+	title := http.StatusText(apiErr.Status)
+	if apiErr.Status == StatusClientClosedRequest {
+		title = "Client Closed Request"
+	}
+
+	problem := Problem{
+		Type:      "about:blank",
+		Title:     title,
+		Status:    apiErr.Status,
+		Detail:    apiErr.Message,
+		Code:      apiErr.Code,
+		RequestID: w.Header().Get("X-Request-ID"),
+	}
+	if r != nil && r.URL != nil {
+		problem.Instance = r.URL.Path
+	}
+	return problem
+}
+
+//WriteError writes err as a problem details response with the status of its ApiError
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	problem := NewProblem(w, r, err)
+	WriteProblem(w, problem, problem.Status)
+}
+
+//WriteProblem writes body, a Problem or a struct embedding one, as a problem details response
+func WriteProblem(w http.ResponseWriter, body interface{}, status int) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrMissingCredential = errors.New("missing credential")
+var ErrInvalidCredential = errors.New("invalid credential")
+
+//Authenticator resolves the identity of the caller from the request credentials.
+//Callers can send an API key in the X-API-Key header or a signed token in the Authorization header.
+type Authenticator interface {
+	Authenticate(request *http.Request) (Identity, error)
+}
+
+type authenticator struct {
+	apiKeys     map[[sha256.Size]byte]Identity
+	tokenSecret []byte
+}
+
+//NewAuthenticator builds an authenticator from API keys and the secret used to sign bearer tokens.
+//Token authentication is disabled when the secret is empty.
+func NewAuthenticator(apiKeys map[string]Identity, tokenSecret string) Authenticator {
+	hashedKeys := make(map[[sha256.Size]byte]Identity, len(apiKeys))
+	for key, identity := range apiKeys {
+		hashedKeys[sha256.Sum256([]byte(key))] = identity
+	}
+	return &authenticator{
+		apiKeys:     hashedKeys,
+		tokenSecret: []byte(tokenSecret),
+	}
+}
+
+func (a *authenticator) Authenticate(request *http.Request) (Identity, error) {
+	if key := request.Header.Get("X-API-Key"); key != "" {
+		//keys are looked up by hash so the lookup time doesn't depend on the secret
+		identity, ok := a.apiKeys[sha256.Sum256([]byte(key))]
+		if !ok {
+			return Identity{}, ErrInvalidCredential
+		}
+		return identity, nil
+	}
+
+	header := request.Header.Get("Authorization")
+	if header == "" {
+		return Identity{}, ErrMissingCredential
+	}
+	if !strings.HasPrefix(header, "Bearer ") || len(a.tokenSecret) == 0 {
+		return Identity{}, ErrInvalidCredential
+	}
+	return a.verifyToken(strings.TrimPrefix(header, "Bearer "))
+}
+
+//claims carried by a bearer token
+type tokenClaims struct {
+	Role Role `json:"role"`
+	jwt.RegisteredClaims
+}
+
+//Verify the token signature and expiry and read the identity from its claims
+func (a *authenticator) verifyToken(token string) (Identity, error) {
+	var claims tokenClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return a.tokenSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return Identity{}, ErrInvalidCredential
+	}
+
+	identity := Identity{Email: claims.Subject, Role: claims.Role}
+	if !identity.isValid() {
+		return Identity{}, ErrInvalidCredential
+	}
+	return identity, nil
+}
+
+//IssueToken signs a bearer token for the identity that expires after ttl
+func IssueToken(secret string, identity Identity, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := tokenClaims{
+		Role: identity.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   identity.Email,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+}
+
+//ParseAPIKeys reads API keys in the form "key:role:email" separated by commas.
+//The email may be left out for admin keys.
+func ParseAPIKeys(value string) (map[string]Identity, error) {
+	apiKeys := make(map[string]Identity)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) < 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid API key entry %q, expected key:role:email", entry)
+		}
+		identity := Identity{Role: Role(parts[1])}
+		if len(parts) == 3 {
+			identity.Email = parts[2]
+		}
+		if !identity.isValid() {
+			return nil, fmt.Errorf("invalid API key entry %q, teacher keys need an email and role must be teacher or admin", entry)
+		}
+		apiKeys[parts[0]] = identity
+	}
+	return apiKeys, nil
+}
+
+//Teachers must be identified by email, admins may act on behalf of anyone
+func (identity Identity) isValid() bool {
+	switch identity.Role {
+	case RoleAdmin:
+		return true
+	case RoleTeacher:
+		return identity.Email != ""
+	}
+	return false
+}
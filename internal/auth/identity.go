@@ -0,0 +1,46 @@
+package auth
+
+import "context"
+
+//role of an authenticated caller
+type Role string
+
+const (
+	RoleTeacher Role = "teacher"
+	RoleAdmin   Role = "admin"
+)
+
+//Identity of the caller resolved from its credential
+type Identity struct {
+	Email string
+	Role  Role
+}
+
+//Check if the caller is allowed to act on behalf of the given teacher
+func (identity Identity) CanActAs(teacher string) bool {
+	if identity.Role == RoleAdmin {
+		return true
+	}
+	return identity.Role == RoleTeacher && identity.Email == teacher
+}
+
+//Name recorded as the author of the caller's changes, its email or its role when the credential has none
+func (identity Identity) Name() string {
+	if identity.Email != "" {
+		return identity.Email
+	}
+	return string(identity.Role)
+}
+
+type contextKey struct{}
+
+//Return a copy of ctx carrying the identity
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, identity)
+}
+
+//Get the identity stored by the auth middleware
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(contextKey{}).(Identity)
+	return identity, ok
+}
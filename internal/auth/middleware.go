@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"class-management/errors"
+	"net/http"
+)
+
+//Middleware rejects requests without a valid credential and stores the caller identity in the request context
+func Middleware(authenticator Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			identity, err := authenticator.Authenticate(request)
+			if err != nil {
+				errors.WriteError(writer, request, errors.ErrUnauthorized)
+				return
+			}
+			next.ServeHTTP(writer, request.WithContext(WithIdentity(request.Context(), identity)))
+		})
+	}
+}
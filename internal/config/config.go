@@ -0,0 +1,413 @@
+package config
+
+import (
+	"bytes"
+	"class-management/internal/auth"
+	"class-management/internal/database"
+	"class-management/internal/deadline"
+	"class-management/internal/logging"
+	"class-management/internal/notifier"
+	"class-management/internal/tracing"
+	"class-management/internal/utils"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Shown instead of the value of a secret setting
+const redacted = "[REDACTED]"
+
+//Config of the api. Values are read from, in increasing order of precedence, the defaults, an optional JSON
+//config file, environment variables and command line flags.
+type Config struct {
+	HTTPPort int `json:"http_port"`
+
+	//Limits of the http.Server, see its fields of the same name. Zero means no timeout.
+	HTTPReadTimeout       utils.Duration `json:"http_read_timeout"`
+	HTTPReadHeaderTimeout utils.Duration `json:"http_read_header_timeout"`
+	HTTPWriteTimeout      utils.Duration `json:"http_write_timeout"`
+	HTTPIdleTimeout       utils.Duration `json:"http_idle_timeout"`
+
+	//Deadline of a request, after which its database work is cancelled. RouteTimeouts overrides it for some
+	//routes as route=timeout pairs, see deadline.ParseRouteTimeouts. Zero means no deadline.
+	RequestTimeout utils.Duration `json:"request_timeout"`
+	RouteTimeouts  string         `json:"route_timeouts"`
+
+	//How long in-flight requests and background workers get to finish after SIGINT or SIGTERM
+	ShutdownTimeout utils.Duration `json:"shutdown_timeout"`
+
+	Database        database.Config `json:"database"`
+	Notifier        notifier.Config `json:"notifier"`
+	APIKeys         string          `json:"api_keys"`
+	AuthTokenSecret string          `json:"auth_token_secret"`
+	MigrateOnStart  bool            `json:"migrate_on_start"`
+
+	//Lowest level written to the log: debug, info, warn or error
+	LogLevel string `json:"log_level"`
+
+	Tracing tracing.Config `json:"tracing"`
+}
+
+//setting binds one config field to its environment variable and flag.
+//Secrets have no flag, since flags show up in process listings, but can be read from the file named by <env>_FILE.
+type setting struct {
+	env    string
+	flag   string
+	usage  string
+	secret bool
+	field  func(c *Config) interface{}
+}
+
+var settings = []setting{
+	{env: "HTTP_PORT", flag: "http-port", usage: "port the api listens on", field: func(c *Config) interface{} { return &c.HTTPPort }},
+	{env: "HTTP_READ_TIMEOUT", flag: "http-read-timeout", usage: "maximum duration for reading a request", field: func(c *Config) interface{} { return &c.HTTPReadTimeout }},
+	{env: "HTTP_READ_HEADER_TIMEOUT", flag: "http-read-header-timeout", usage: "maximum duration for reading request headers", field: func(c *Config) interface{} { return &c.HTTPReadHeaderTimeout }},
+	{env: "HTTP_WRITE_TIMEOUT", flag: "http-write-timeout", usage: "maximum duration for writing a response", field: func(c *Config) interface{} { return &c.HTTPWriteTimeout }},
+	{env: "HTTP_IDLE_TIMEOUT", flag: "http-idle-timeout", usage: "maximum time a keep-alive connection waits for the next request", field: func(c *Config) interface{} { return &c.HTTPIdleTimeout }},
+	{env: "REQUEST_TIMEOUT", flag: "request-timeout", usage: "deadline of a request", field: func(c *Config) interface{} { return &c.RequestTimeout }},
+	{env: "ROUTE_TIMEOUTS", flag: "route-timeouts", usage: "deadlines of single routes as route=timeout pairs", field: func(c *Config) interface{} { return &c.RouteTimeouts }},
+	{env: "SHUTDOWN_TIMEOUT", flag: "shutdown-timeout", usage: "time given to in-flight work on shutdown", field: func(c *Config) interface{} { return &c.ShutdownTimeout }},
+	{env: "DB_DRIVER", flag: "db-driver", usage: "database driver: mysql, postgres or sqlite", field: func(c *Config) interface{} { return &c.Database.Driver }},
+	{env: "DB_HOST", flag: "db-host", usage: "database host", field: func(c *Config) interface{} { return &c.Database.Host }},
+	{env: "DB_PORT", flag: "db-port", usage: "database port", field: func(c *Config) interface{} { return &c.Database.Port }},
+	{env: "DB_NAME", flag: "db-name", usage: "database name", field: func(c *Config) interface{} { return &c.Database.Name }},
+	{env: "DB_USER", flag: "db-user", usage: "database user", field: func(c *Config) interface{} { return &c.Database.User }},
+	{env: "DB_PASSWORD", secret: true, field: func(c *Config) interface{} { return &c.Database.Password }},
+	{env: "DB_SSLMODE", flag: "db-sslmode", usage: "postgres ssl mode", field: func(c *Config) interface{} { return &c.Database.SSLMode }},
+	{env: "DB_PATH", flag: "db-path", usage: "sqlite database file, or :memory:", field: func(c *Config) interface{} { return &c.Database.Path }},
+	{env: "DB_MAX_OPEN_CONNS", flag: "db-max-open-conns", usage: "maximum open database connections", field: func(c *Config) interface{} { return &c.Database.MaxOpenConns }},
+	{env: "DB_MAX_IDLE_CONNS", flag: "db-max-idle-conns", usage: "maximum idle database connections", field: func(c *Config) interface{} { return &c.Database.MaxIdleConns }},
+	{env: "DB_CONN_MAX_LIFETIME", flag: "db-conn-max-lifetime", usage: "maximum lifetime of a database connection", field: func(c *Config) interface{} { return &c.Database.ConnMaxLifetime }},
+	{env: "DB_CONN_MAX_IDLE_TIME", flag: "db-conn-max-idle-time", usage: "maximum idle time of a database connection", field: func(c *Config) interface{} { return &c.Database.ConnMaxIdleTime }},
+	{env: "MIGRATE_ON_START", flag: "migrate-on-start", usage: "apply pending migrations before serving", field: func(c *Config) interface{} { return &c.MigrateOnStart }},
+	{env: "LOG_LEVEL", flag: "log-level", usage: "lowest level logged: debug, info, warn or error", field: func(c *Config) interface{} { return &c.LogLevel }},
+	{env: "TRACING_EXPORTER", flag: "tracing-exporter", usage: "exporter spans are sent to: none, stdout or otlp", field: func(c *Config) interface{} { return &c.Tracing.Exporter }},
+	{env: "TRACING_SERVICE_NAME", flag: "tracing-service-name", usage: "service name of the spans", field: func(c *Config) interface{} { return &c.Tracing.ServiceName }},
+	{env: "TRACING_SAMPLE_RATIO", flag: "tracing-sample-ratio", usage: "share of new traces recorded, from 0 to 1", field: func(c *Config) interface{} { return &c.Tracing.SampleRatio }},
+	{env: "TRACING_OTLP_ENDPOINT", flag: "tracing-otlp-endpoint", usage: "host:port of the OTLP/HTTP collector", field: func(c *Config) interface{} { return &c.Tracing.OTLPEndpoint }},
+	{env: "TRACING_OTLP_INSECURE", flag: "tracing-otlp-insecure", usage: "send spans to the collector over plain http", field: func(c *Config) interface{} { return &c.Tracing.OTLPInsecure }},
+	{env: "API_KEYS", secret: true, field: func(c *Config) interface{} { return &c.APIKeys }},
+	{env: "AUTH_TOKEN_SECRET", secret: true, field: func(c *Config) interface{} { return &c.AuthTokenSecret }},
+	{env: "NOTIFIER", flag: "notifier", usage: "notifier: log, smtp or webhook", field: func(c *Config) interface{} { return &c.Notifier.Kind }},
+	{env: "NOTIFIER_LOG_FILE", flag: "notifier-log-file", usage: "file the log notifier writes to", field: func(c *Config) interface{} { return &c.Notifier.LogFile }},
+	{env: "SMTP_HOST", flag: "smtp-host", usage: "smtp server host", field: func(c *Config) interface{} { return &c.Notifier.SMTPHost }},
+	{env: "SMTP_PORT", flag: "smtp-port", usage: "smtp server port", field: func(c *Config) interface{} { return &c.Notifier.SMTPPort }},
+	{env: "SMTP_USERNAME", flag: "smtp-username", usage: "smtp username", field: func(c *Config) interface{} { return &c.Notifier.SMTPUsername }},
+	{env: "SMTP_PASSWORD", secret: true, field: func(c *Config) interface{} { return &c.Notifier.SMTPPassword }},
+	{env: "SMTP_FROM", flag: "smtp-from", usage: "sender address of notification emails", field: func(c *Config) interface{} { return &c.Notifier.SMTPFrom }},
+	{env: "WEBHOOK_URL", flag: "webhook-url", usage: "url the webhook notifier posts to", field: func(c *Config) interface{} { return &c.Notifier.WebhookURL }},
+}
+
+//Default returns the config used when nothing else is set
+func Default() Config {
+	return Config{
+		HTTPPort:              8080,
+		HTTPReadTimeout:       utils.Duration(15 * time.Second),
+		HTTPReadHeaderTimeout: utils.Duration(5 * time.Second),
+		HTTPWriteTimeout:      utils.Duration(30 * time.Second),
+		HTTPIdleTimeout:       utils.Duration(60 * time.Second),
+		RequestTimeout:        utils.Duration(10 * time.Second),
+		ShutdownTimeout:       utils.Duration(30 * time.Second),
+		Database: database.Config{
+			Driver:          database.MySQL,
+			MaxOpenConns:    25,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: utils.Duration(30 * time.Minute),
+			ConnMaxIdleTime: utils.Duration(5 * time.Minute),
+		},
+		Notifier:       notifier.Config{Kind: "log"},
+		MigrateOnStart: true,
+		LogLevel:       "info",
+		Tracing: tracing.Config{
+			Exporter:    "none",
+			ServiceName: "class-management",
+			SampleRatio: 1,
+		},
+	}
+}
+
+//Load reads and validates the config. args are the command line arguments without the program name.
+//The arguments left after the flags, such as a subcommand, are returned.
+func Load(args []string) (*Config, []string, error) {
+	flags := flag.NewFlagSet("api", flag.ContinueOnError)
+	configFile := flags.String("config", os.Getenv("CONFIG_FILE"), "JSON config file")
+	flagValues := make(map[string]*string)
+	for _, s := range settings {
+		if s.flag != "" {
+			flagValues[s.flag] = flags.String(s.flag, "", s.usage+" (env "+s.env+")")
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	config := Default()
+	if *configFile != "" {
+		if err := config.loadFile(*configFile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var problems []string
+	for _, s := range settings {
+		value, ok, err := s.lookupEnv()
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if ok {
+			if err := s.set(&config, value); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+	}
+
+	//only flags given on the command line override
+	flags.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.flag == f.Name {
+				if err := s.set(&config, *flagValues[f.Name]); err != nil {
+					problems = append(problems, err.Error())
+				}
+			}
+		}
+	})
+
+	problems = append(problems, config.validate()...)
+	if len(problems) > 0 {
+		return nil, nil, fmt.Errorf("invalid configuration:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+
+	return &config, flags.Args(), nil
+}
+
+//loadFile merges a JSON config file. Unknown keys are rejected so typos don't go unnoticed.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(c); err != nil && err != io.EOF {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+//lookupEnv reads the setting from its environment variable, or for secrets from the file named by <env>_FILE.
+//Empty variables count as unset.
+func (s setting) lookupEnv() (string, bool, error) {
+	value := os.Getenv(s.env)
+	if !s.secret {
+		return value, value != "", nil
+	}
+
+	file := os.Getenv(s.env + "_FILE")
+	if file == "" {
+		return value, value != "", nil
+	}
+	if value != "" {
+		return "", false, fmt.Errorf("set either %s or %s_FILE, not both", s.env, s.env)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", false, fmt.Errorf("%s_FILE: %v", s.env, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), true, nil
+}
+
+func (s setting) set(c *Config, value string) error {
+	switch field := s.field(c).(type) {
+	case *string:
+		*field = value
+	case *database.Driver:
+		*field = database.Driver(value)
+	case *int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s must be a number, got %q", s.env, value)
+		}
+		*field = n
+	case *bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false, got %q", s.env, value)
+		}
+		*field = b
+	case *float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s must be a number, got %q", s.env, value)
+		}
+		*field = f
+	case *utils.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s must be a duration such as 30s, got %q", s.env, value)
+		}
+		*field = utils.Duration(d)
+	}
+	return nil
+}
+
+func (s setting) get(c *Config) string {
+	switch field := s.field(c).(type) {
+	case *string:
+		return *field
+	case *database.Driver:
+		return string(*field)
+	case *int:
+		return strconv.Itoa(*field)
+	case *bool:
+		return strconv.FormatBool(*field)
+	case *float64:
+		return strconv.FormatFloat(*field, 'g', -1, 64)
+	case *utils.Duration:
+		return field.String()
+	}
+	return ""
+}
+
+//validate lists every missing or invalid value
+func (c *Config) validate() []string {
+	var problems []string
+
+	if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+		problems = append(problems, fmt.Sprintf("LOG_LEVEL must be debug, info, warn or error, got %q", c.LogLevel))
+	}
+	if c.HTTPPort < 1 || c.HTTPPort > 65535 {
+		problems = append(problems, fmt.Sprintf("HTTP_PORT must be between 1 and 65535, got %d", c.HTTPPort))
+	}
+
+	durations := map[string]utils.Duration{
+		"HTTP_READ_TIMEOUT":        c.HTTPReadTimeout,
+		"HTTP_READ_HEADER_TIMEOUT": c.HTTPReadHeaderTimeout,
+		"HTTP_WRITE_TIMEOUT":       c.HTTPWriteTimeout,
+		"HTTP_IDLE_TIMEOUT":        c.HTTPIdleTimeout,
+		"REQUEST_TIMEOUT":          c.RequestTimeout,
+		"DB_CONN_MAX_LIFETIME":     c.Database.ConnMaxLifetime,
+		"DB_CONN_MAX_IDLE_TIME":    c.Database.ConnMaxIdleTime,
+	}
+	for _, name := range []string{"HTTP_READ_TIMEOUT", "HTTP_READ_HEADER_TIMEOUT", "HTTP_WRITE_TIMEOUT", "HTTP_IDLE_TIMEOUT", "REQUEST_TIMEOUT", "DB_CONN_MAX_LIFETIME", "DB_CONN_MAX_IDLE_TIME"} {
+		if durations[name] < 0 {
+			problems = append(problems, fmt.Sprintf("%s must not be negative, got %s", name, durations[name]))
+		}
+	}
+	//a request running past the write timeout can't send its response anyway
+	if c.HTTPWriteTimeout > 0 && c.RequestTimeout > c.HTTPWriteTimeout {
+		problems = append(problems, fmt.Sprintf("REQUEST_TIMEOUT must not exceed HTTP_WRITE_TIMEOUT, got %s and %s", c.RequestTimeout, c.HTTPWriteTimeout))
+	}
+	routeTimeouts, err := deadline.ParseRouteTimeouts(c.RouteTimeouts)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("ROUTE_TIMEOUTS is invalid: %v", err))
+	}
+	for route, timeout := range routeTimeouts {
+		if c.HTTPWriteTimeout > 0 && utils.Duration(timeout) > c.HTTPWriteTimeout {
+			problems = append(problems, fmt.Sprintf("ROUTE_TIMEOUTS of %s must not exceed HTTP_WRITE_TIMEOUT, got %s and %s", route, timeout, c.HTTPWriteTimeout))
+		}
+	}
+	if c.ShutdownTimeout <= 0 {
+		problems = append(problems, fmt.Sprintf("SHUTDOWN_TIMEOUT must be positive, got %s", c.ShutdownTimeout))
+	}
+	if c.Database.MaxOpenConns < 0 {
+		problems = append(problems, fmt.Sprintf("DB_MAX_OPEN_CONNS must not be negative, got %d", c.Database.MaxOpenConns))
+	}
+	if c.Database.MaxIdleConns < 0 {
+		problems = append(problems, fmt.Sprintf("DB_MAX_IDLE_CONNS must not be negative, got %d", c.Database.MaxIdleConns))
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		problems = append(problems, fmt.Sprintf("DB_MAX_IDLE_CONNS must not exceed DB_MAX_OPEN_CONNS, got %d and %d", c.Database.MaxIdleConns, c.Database.MaxOpenConns))
+	}
+
+	driver, err := database.ParseDriver(string(c.Database.Driver))
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("DB_DRIVER must be mysql, postgres or sqlite, got %q", c.Database.Driver))
+	}
+	switch driver {
+	case database.MySQL, database.Postgres:
+		required := map[string]string{
+			"DB_HOST": c.Database.Host,
+			"DB_PORT": c.Database.Port,
+			"DB_NAME": c.Database.Name,
+			"DB_USER": c.Database.User,
+		}
+		for _, name := range []string{"DB_HOST", "DB_PORT", "DB_NAME", "DB_USER"} {
+			if required[name] == "" {
+				problems = append(problems, fmt.Sprintf("%s is required for DB_DRIVER %s", name, driver))
+			}
+		}
+	case database.SQLite:
+		if c.Database.Path == "" {
+			problems = append(problems, "DB_PATH is required for DB_DRIVER sqlite")
+		}
+	}
+
+	switch strings.ToLower(c.Notifier.Kind) {
+	case "", "log":
+	case "smtp":
+		if c.Notifier.SMTPHost == "" {
+			problems = append(problems, "SMTP_HOST is required for NOTIFIER smtp")
+		}
+		if c.Notifier.SMTPFrom == "" {
+			problems = append(problems, "SMTP_FROM is required for NOTIFIER smtp")
+		}
+	case "webhook":
+		if u, err := url.Parse(c.Notifier.WebhookURL); err != nil || u.Scheme == "" || u.Host == "" {
+			problems = append(problems, "WEBHOOK_URL must be an absolute url for NOTIFIER webhook")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("NOTIFIER must be log, smtp or webhook, got %q", c.Notifier.Kind))
+	}
+
+	switch strings.ToLower(c.Tracing.Exporter) {
+	case "", "none", "stdout", "otlp":
+	default:
+		problems = append(problems, fmt.Sprintf("TRACING_EXPORTER must be none, stdout or otlp, got %q", c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		problems = append(problems, fmt.Sprintf("TRACING_SAMPLE_RATIO must be between 0 and 1, got %g", c.Tracing.SampleRatio))
+	}
+	if strings.Contains(c.Tracing.OTLPEndpoint, "://") {
+		problems = append(problems, fmt.Sprintf("TRACING_OTLP_ENDPOINT must be host:port without a scheme, got %q", c.Tracing.OTLPEndpoint))
+	}
+
+	if c.APIKeys == "" && c.AuthTokenSecret == "" {
+		problems = append(problems, "API_KEYS or AUTH_TOKEN_SECRET is required, otherwise every request is rejected")
+	}
+	//the parse error quotes the key, so it is not repeated here
+	if _, err := auth.ParseAPIKeys(c.APIKeys); err != nil {
+		problems = append(problems, "API_KEYS has an invalid entry, expected key:role:email with role teacher or admin")
+	}
+
+	return problems
+}
+
+//String lists every setting by its environment variable with secrets redacted, so the config is safe to log
+func (c Config) String() string {
+	var b strings.Builder
+	for i, s := range settings {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		value := s.get(&c)
+		if s.secret && value != "" {
+			value = redacted
+		}
+		fmt.Fprintf(&b, "%s=%s", s.env, value)
+	}
+	return b.String()
+}
+
+//GoString keeps secrets out of %#v as well
+func (c Config) GoString() string {
+	return c.String()
+}
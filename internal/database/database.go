@@ -0,0 +1,133 @@
+package database
+
+import (
+	"class-management/internal/utils"
+	"fmt"
+	"net/url"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+//Driver names the storage backend
+type Driver string
+
+const (
+	MySQL    Driver = "mysql"
+	Postgres Driver = "postgres"
+	SQLite   Driver = "sqlite"
+)
+
+//SQLite database kept in memory, for local runs and tests
+const SQLiteMemory = ":memory:"
+
+//Config selects and addresses the database. Host, Port, Name, User and Password are used by MySQL and PostgreSQL,
+//SSLMode only by PostgreSQL and Path only by SQLite. The pool settings apply to every driver.
+type Config struct {
+	Driver   Driver `json:"driver"`
+	Host     string `json:"host"`
+	Port     string `json:"port"`
+	Name     string `json:"name"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+	SSLMode  string `json:"ssl_mode"`
+	Path     string `json:"path"`
+
+	//Connection pool of the underlying sql.DB. Zero keeps the database/sql default.
+	MaxOpenConns    int            `json:"max_open_conns"`
+	MaxIdleConns    int            `json:"max_idle_conns"`
+	ConnMaxLifetime utils.Duration `json:"conn_max_lifetime"`
+	ConnMaxIdleTime utils.Duration `json:"conn_max_idle_time"`
+}
+
+//ParseDriver validates a driver name. An empty name selects MySQL.
+func ParseDriver(name string) (Driver, error) {
+	switch Driver(name) {
+	case "", MySQL:
+		return MySQL, nil
+	case Postgres, SQLite:
+		return Driver(name), nil
+	default:
+		return "", fmt.Errorf("database: unknown driver %q", name)
+	}
+}
+
+//Open connects to the database selected by config and sizes its connection pool
+func Open(config Config) (*gorm.DB, error) {
+	driver, err := ParseDriver(string(config.Driver))
+	if err != nil {
+		return nil, err
+	}
+
+	var dialector gorm.Dialector
+	switch driver {
+	case Postgres:
+		sslMode := config.SSLMode
+		if sslMode == "" {
+			sslMode = "disable"
+		}
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(config.User, config.Password),
+			Host:     config.Host + ":" + config.Port,
+			Path:     "/" + config.Name,
+			RawQuery: url.Values{"sslmode": {sslMode}}.Encode(),
+		}
+		dialector = postgres.Open(dsn.String())
+
+	case SQLite:
+		if config.Path == "" {
+			return nil, fmt.Errorf("database: sqlite needs a path")
+		}
+
+		//foreign keys are off by default in SQLite and writers wait for each other instead of failing
+		dsn := "file:" + config.Path + "?_foreign_keys=on&_busy_timeout=5000"
+		if config.Path == SQLiteMemory {
+			dsn = "file::memory:?_foreign_keys=on"
+
+			//every connection to an in-memory database opens a new empty one, so keep a single connection for good
+			config.MaxOpenConns = 1
+			config.MaxIdleConns = 1
+			config.ConnMaxLifetime = 0
+			config.ConnMaxIdleTime = 0
+		}
+		dialector = sqlite.Open(dsn)
+
+	default:
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			config.User,
+			config.Password,
+			config.Host,
+			config.Port,
+			config.Name,
+		)
+		dialector = mysql.Open(dsn)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if config.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+	}
+	if config.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+	}
+	if config.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(time.Duration(config.ConnMaxLifetime))
+	}
+	if config.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(time.Duration(config.ConnMaxIdleTime))
+	}
+
+	return db, nil
+}
@@ -0,0 +1,59 @@
+package deadline
+
+import (
+	"class-management/internal/utils"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+//ParseRouteTimeouts reads comma separated route=timeout pairs, where the route is a path template as registered
+//on the router, for example "/api/retrievefornotifications=30s,/api/students/{email}=2s"
+func ParseRouteTimeouts(value string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		separator := strings.LastIndex(entry, "=")
+		if separator <= 0 {
+			return nil, fmt.Errorf("route timeout %q must be route=timeout", entry)
+		}
+		route := strings.TrimSpace(entry[:separator])
+		timeout, err := time.ParseDuration(strings.TrimSpace(entry[separator+1:]))
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("route timeout %q must end in a duration such as 30s", entry)
+		}
+		if _, ok := timeouts[route]; ok {
+			return nil, fmt.Errorf("route %s has more than one timeout", route)
+		}
+		timeouts[route] = timeout
+	}
+	return timeouts, nil
+}
+
+//Middleware gives the context of every request a deadline, the timeout of its route template or defaultTimeout
+//for other routes. Repositories stop their queries once it passes, as they do when the client disconnects.
+//A zero timeout leaves the request without a deadline.
+func Middleware(defaultTimeout time.Duration, routes map[string]time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			timeout, ok := routes[utils.RouteTemplate(request)]
+			if !ok {
+				timeout = defaultTimeout
+			}
+			if timeout <= 0 {
+				next.ServeHTTP(writer, request)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(request.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(writer, request.WithContext(ctx))
+		})
+	}
+}
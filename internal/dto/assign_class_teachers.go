@@ -0,0 +1,6 @@
+package dto
+
+type AssignClassTeachersRequest struct {
+	ClassID  uint     `json:"-"`
+	Teachers []string `json:"teachers"`
+}
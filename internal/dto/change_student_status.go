@@ -0,0 +1,13 @@
+package dto
+
+type ChangeStudentStatusRequest struct {
+	Student string `json:"-"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason"`
+
+	//Caller recorded as the author of the change
+	ChangedBy string `json:"-"`
+
+	//Teacher the caller acts as, empty for an admin
+	ActingTeacher string `json:"-"`
+}
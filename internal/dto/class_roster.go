@@ -0,0 +1,13 @@
+package dto
+
+type ClassRosterRequest struct {
+	ClassID          uint
+	IncludeGraduated bool
+}
+
+type ClassRosterResponse struct {
+	ID       uint     `json:"id"`
+	Name     string   `json:"name"`
+	Teachers []string `json:"teachers"`
+	Students []string `json:"students"`
+}
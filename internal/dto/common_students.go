@@ -1,5 +1,29 @@
 package dto
 
+//How the students of the given teachers are combined
+const (
+	CommonStudentsModeAll     = "all"
+	CommonStudentsModeAny     = "any"
+	CommonStudentsModeAtLeast = "at_least"
+)
+
+type CommonStudentsRequest struct {
+	Teachers         []string
+	Mode             string
+	MinTeachers      int
+	ExcludeTeachers  []string
+	IncludeGraduated bool
+	ClassID          uint
+	Statuses         []string
+	Sort             string
+	Descending       bool
+	Limit            int
+	Offset           int
+}
+
 type CommonStudentsResponse struct {
 	Students []string `json:"students"`
+	Total    int64    `json:"total"`
+	Limit    int      `json:"limit,omitempty"`
+	Offset   int      `json:"offset"`
 }
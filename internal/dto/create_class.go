@@ -0,0 +1,12 @@
+package dto
+
+type CreateClassRequest struct {
+	Name     string   `json:"name"`
+	Teachers []string `json:"teachers"`
+}
+
+type ClassResponse struct {
+	ID       uint     `json:"id"`
+	Name     string   `json:"name"`
+	Teachers []string `json:"teachers"`
+}
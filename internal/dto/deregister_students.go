@@ -0,0 +1,6 @@
+package dto
+
+type DeregisterStudentsRequest struct {
+	Teacher  string   `json:"teacher"`
+	Students []string `json:"students"`
+}
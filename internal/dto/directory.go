@@ -0,0 +1,76 @@
+package dto
+
+import "time"
+
+//Requested page of a listing
+type PageRequest struct {
+	Limit  int
+	Offset int
+}
+
+//Position of a listing page, with the total number of matching entries across all pages
+type PageResponse struct {
+	Total  int64 `json:"total"`
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+}
+
+type TeacherResponse struct {
+	Email         string                 `json:"email"`
+	Name          string                 `json:"name"`
+	PreferredName string                 `json:"preferred_name"`
+	Department    string                 `json:"department"`
+	Metadata      map[string]interface{} `json:"metadata"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+type TeacherListResponse struct {
+	Teachers []TeacherResponse `json:"teachers"`
+	PageResponse
+}
+
+type ListStudentsRequest struct {
+	Statuses []string
+	PageRequest
+}
+
+type StudentSummary struct {
+	Email         string                 `json:"email"`
+	Status        string                 `json:"status"`
+	Name          string                 `json:"name"`
+	PreferredName string                 `json:"preferred_name"`
+	StudentNumber *string                `json:"student_number"`
+	GradeLevel    string                 `json:"grade_level"`
+	Metadata      map[string]interface{} `json:"metadata"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+type StudentListResponse struct {
+	Students []StudentSummary `json:"students"`
+	PageResponse
+}
+
+type StudentResponse struct {
+	StudentSummary
+	Teachers []string `json:"teachers"`
+}
+
+type TeacherStudentsResponse struct {
+	Teacher  string           `json:"teacher"`
+	Students []StudentSummary `json:"students"`
+	PageResponse
+}
+
+//A past or current registration of a student. archived_at is set once the student graduated and
+//deregistered_at once the student was deregistered from the teacher.
+type EnrolmentResponse struct {
+	Teacher        string     `json:"teacher"`
+	RegisteredAt   time.Time  `json:"registered_at"`
+	ArchivedAt     *time.Time `json:"archived_at"`
+	DeregisteredAt *time.Time `json:"deregistered_at"`
+}
+
+type StudentEnrolmentsResponse struct {
+	Student    string              `json:"student"`
+	Enrolments []EnrolmentResponse `json:"enrolments"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+type EnrolClassStudentsRequest struct {
+	ClassID  uint     `json:"-"`
+	Students []string `json:"students"`
+
+	//Teacher the caller acts as, empty for an admin
+	ActingTeacher string `json:"-"`
+}
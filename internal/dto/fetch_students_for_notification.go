@@ -1,6 +1,28 @@
 package dto
 
 type FetchStudentsForNotificationRequest struct {
-	Teacher      string `json:"teacher"`
-	Notification string `json:"notification"`
+	Teacher          string `json:"teacher"`
+	Notification     string `json:"notification"`
+	IncludeGraduated bool   `json:"include_graduated"`
+	ClassID          uint   `json:"class_id"`
+}
+
+type FetchStudentsForNotificationResponse struct {
+	NotificationID  uint             `json:"notification_id"`
+	Recipients      []string         `json:"recipients"`
+	DroppedMentions []DroppedMention `json:"dropped_mentions"`
+}
+
+//Why an @mention did not make the student a recipient
+type MentionDropReason string
+
+const (
+	MentionNotFound  MentionDropReason = "not_found"
+	MentionSuspended MentionDropReason = "suspended"
+	MentionGraduated MentionDropReason = "graduated"
+)
+
+type DroppedMention struct {
+	Email  string            `json:"email"`
+	Reason MentionDropReason `json:"reason"`
 }
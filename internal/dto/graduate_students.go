@@ -0,0 +1,24 @@
+package dto
+
+type GraduateStudentsRequest struct {
+	Students   []string `json:"students"`
+	Teacher    string   `json:"teacher"`
+	GradeLevel string   `json:"grade_level"`
+	Reason     string   `json:"reason"`
+
+	//Caller recorded as the author of the change
+	ChangedBy string `json:"-"`
+
+	//Teacher the caller acts as, empty for an admin
+	ActingTeacher string `json:"-"`
+}
+
+type GraduateStudentsResponse struct {
+	Graduated []string            `json:"graduated"`
+	Skipped   []SkippedGraduation `json:"skipped"`
+}
+
+type SkippedGraduation struct {
+	Student string `json:"student"`
+	Reason  string `json:"reason"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type TeacherNotificationsResponse struct {
+	Teacher       string                `json:"teacher"`
+	Notifications []NotificationSummary `json:"notifications"`
+	PageResponse
+}
+
+type NotificationSummary struct {
+	ID             uint      `json:"id"`
+	Notification   string    `json:"notification"`
+	RecipientCount int       `json:"recipient_count"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type NotificationResponse struct {
+	ID           uint                    `json:"id"`
+	Teacher      string                  `json:"teacher"`
+	Notification string                  `json:"notification"`
+	CreatedAt    time.Time               `json:"created_at"`
+	Recipients   []NotificationRecipient `json:"recipients"`
+}
+
+type NotificationRecipient struct {
+	Email          string `json:"email"`
+	Registered     bool   `json:"registered"`
+	Mentioned      bool   `json:"mentioned"`
+	DeliveryStatus string `json:"delivery_status,omitempty"`
+}
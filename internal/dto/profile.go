@@ -0,0 +1,72 @@
+package dto
+
+import "class-management/internal/models"
+
+//Profile fields of a teacher. Fields left out are not changed.
+type TeacherProfile struct {
+	Name          *string                `json:"name"`
+	PreferredName *string                `json:"preferred_name"`
+	Department    *string                `json:"department"`
+	Metadata      map[string]interface{} `json:"metadata"`
+}
+
+//Copy the given fields to the teacher
+func (p TeacherProfile) ApplyTo(teacher *models.Teacher) {
+	if p.Name != nil {
+		teacher.Name = *p.Name
+	}
+	if p.PreferredName != nil {
+		teacher.PreferredName = *p.PreferredName
+	}
+	if p.Department != nil {
+		teacher.Department = *p.Department
+	}
+	if p.Metadata != nil {
+		teacher.Metadata = p.Metadata
+	}
+}
+
+//Profile fields of a student. Fields left out are not changed, an empty student_number removes the number.
+type StudentProfile struct {
+	Name          *string                `json:"name"`
+	PreferredName *string                `json:"preferred_name"`
+	StudentNumber *string                `json:"student_number"`
+	GradeLevel    *string                `json:"grade_level"`
+	Metadata      map[string]interface{} `json:"metadata"`
+}
+
+//Copy the given fields to the student
+func (p StudentProfile) ApplyTo(student *models.Student) {
+	if p.Name != nil {
+		student.Name = *p.Name
+	}
+	if p.PreferredName != nil {
+		student.PreferredName = *p.PreferredName
+	}
+	if p.StudentNumber != nil {
+		student.StudentNumber = nil
+		if *p.StudentNumber != "" {
+			number := *p.StudentNumber
+			student.StudentNumber = &number
+		}
+	}
+	if p.GradeLevel != nil {
+		student.GradeLevel = *p.GradeLevel
+	}
+	if p.Metadata != nil {
+		student.Metadata = p.Metadata
+	}
+}
+
+type UpdateTeacherRequest struct {
+	Email string
+	TeacherProfile
+}
+
+type UpdateStudentRequest struct {
+	Email string
+	StudentProfile
+
+	//Teacher the caller acts as, empty for an admin
+	ActingTeacher string `json:"-"`
+}
@@ -3,4 +3,9 @@ package dto
 type RegisterStudentsRequest struct {
 	Teacher  string   `json:"teacher"`
 	Students []string `json:"students"`
+	Strict   bool     `json:"strict"`
+	Atomic   bool     `json:"atomic"`
+
+	//Profiles of students created by the registration, keyed by email. Existing students are not changed.
+	Profiles map[string]StudentProfile `json:"profiles"`
 }
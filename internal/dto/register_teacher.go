@@ -2,4 +2,8 @@ package dto
 
 type RegisterTeachersRequest struct {
 	Teachers []string `json:"teachers"`
+	Strict   bool     `json:"strict"`
+
+	//Profiles of teachers created by the registration, keyed by email. Existing teachers are not changed.
+	Profiles map[string]TeacherProfile `json:"profiles"`
 }
@@ -0,0 +1,30 @@
+package dto
+
+import "class-management/errors"
+
+//outcome of registering a single email
+type RegistrationStatus string
+
+const (
+	RegistrationCreated           RegistrationStatus = "created"
+	RegistrationLinked            RegistrationStatus = "linked"
+	RegistrationAlreadyRegistered RegistrationStatus = "already_registered"
+	RegistrationInvalid           RegistrationStatus = "invalid"
+	RegistrationFailed            RegistrationStatus = "failed"
+)
+
+type RegistrationResult struct {
+	Email  string             `json:"email"`
+	Status RegistrationStatus `json:"status"`
+	Reason string             `json:"reason,omitempty"`
+}
+
+type RegistrationResponse struct {
+	Results []RegistrationResult `json:"results"`
+}
+
+//returned instead of RegistrationResponse when a strict batch is rejected
+type RegistrationRejectedResponse struct {
+	errors.Problem
+	Results []RegistrationResult `json:"results"`
+}
@@ -2,4 +2,11 @@ package dto
 
 type SuspendRequest struct {
 	Student string `json:"student"`
+	Reason  string `json:"reason"`
+
+	//Caller recorded as the author of the change
+	ChangedBy string `json:"-"`
+
+	//Teacher the caller acts as, empty for an admin
+	ActingTeacher string `json:"-"`
 }
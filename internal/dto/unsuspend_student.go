@@ -0,0 +1,12 @@
+package dto
+
+type UnsuspendRequest struct {
+	Student string `json:"student"`
+	Reason  string `json:"reason"`
+
+	//Caller recorded as the author of the change
+	ChangedBy string `json:"-"`
+
+	//Teacher the caller acts as, empty for an admin
+	ActingTeacher string `json:"-"`
+}
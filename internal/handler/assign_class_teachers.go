@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/dto"
+	"class-management/internal/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+//AssignClassTeachers handler assigns single or multiple teachers to a class.
+//It expects the class id as path param and teacher(s) email as input and return error if any.
+func (ch classHandler) AssignClassTeachers(writer http.ResponseWriter, request *http.Request) {
+
+	//only admins can assign teachers, so a teacher cannot join a class to notify its students
+	if !authorizeAdmin(writer, request) {
+		return
+	}
+
+	//validate params
+	assignReq, err := processAssignClassTeachersParams(request)
+	if err != nil {
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//assign teachers
+	err = ch.service.AssignTeachers(request.Context(), assignReq)
+	if err != nil {
+		logServiceError(request, "teacher assignment failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+//validate input parameters
+func processAssignClassTeachersParams(request *http.Request) (dto.AssignClassTeachersRequest, error) {
+	var params dto.AssignClassTeachersRequest
+
+	classID, err := classIDFromPath(request)
+	if err != nil {
+		return params, err
+	}
+
+	decoder := json.NewDecoder(request.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		return params, errors.ErrDecodingRequest
+	}
+	params.ClassID = classID
+
+	if len(params.Teachers) == 0 {
+		return params, errors.ErrTeachersRequired
+	}
+
+	for _, teacher := range params.Teachers {
+		if !utils.IsEmailValid(teacher) {
+			return params, errors.ErrInvalidTeacherEmail
+		}
+	}
+
+	return params, nil
+}
+
+//read class id from the url path
+func classIDFromPath(request *http.Request) (uint, error) {
+	return parseClassID(mux.Vars(request)["id"])
+}
+
+//parse a class id given as string
+func parseClassID(value string) (uint, error) {
+	classID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || classID == 0 {
+		return 0, errors.ErrInvalidClassID
+	}
+	return uint(classID), nil
+}
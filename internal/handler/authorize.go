@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/auth"
+	"net/http"
+)
+
+//get the authenticated caller, writing the error response if there is none
+func callerIdentity(writer http.ResponseWriter, request *http.Request) (auth.Identity, bool) {
+	identity, ok := auth.IdentityFromContext(request.Context())
+	if !ok {
+		errors.WriteError(writer, request, errors.ErrUnauthorized)
+	}
+	return identity, ok
+}
+
+//check that the authenticated caller may act on behalf of the teacher, writing the error response if not
+func authorizeTeacher(writer http.ResponseWriter, request *http.Request, teacher string) bool {
+	identity, ok := callerIdentity(writer, request)
+	if !ok {
+		return false
+	}
+	if !identity.CanActAs(teacher) {
+		errors.WriteError(writer, request, errors.ErrForbidden)
+		return false
+	}
+	return true
+}
+
+//check that the authenticated caller is an admin, writing the error response if not
+func authorizeAdmin(writer http.ResponseWriter, request *http.Request) bool {
+	identity, ok := callerIdentity(writer, request)
+	if !ok {
+		return false
+	}
+	if identity.Role != auth.RoleAdmin {
+		errors.WriteError(writer, request, errors.ErrAdminRequired)
+		return false
+	}
+	return true
+}
+
+//get the teacher the caller acts as. It is empty for an admin, who may act on any student or class,
+//otherwise the service only lets the teacher act on their own students and classes.
+func actingTeacher(identity auth.Identity) string {
+	if identity.Role == auth.RoleAdmin {
+		return ""
+	}
+	return identity.Email
+}
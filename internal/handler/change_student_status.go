@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/dto"
+	"class-management/internal/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+//ChangeStudentStatus handler moves a student to a new status (ACTIVE, SUSPENDED or GRADUATED).
+//It expects the student email as path param and the target status in the body, and return error if the change is not allowed.
+func (th teacherHandler) ChangeStudentStatus(writer http.ResponseWriter, request *http.Request) {
+
+	//validate params
+	statusReq, err := processChangeStudentStatusParams(request)
+	if err != nil {
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//only an admin or a teacher the student is registered with can change the student's status, and the caller is recorded with the change
+	identity, ok := callerIdentity(writer, request)
+	if !ok {
+		return
+	}
+	statusReq.ActingTeacher = actingTeacher(identity)
+	statusReq.ChangedBy = identity.Name()
+
+	//Change student's status
+	err = th.service.ChangeStudentStatus(request.Context(), statusReq)
+	if err != nil {
+		logServiceError(request, "status change failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+//validate input parameters
+func processChangeStudentStatusParams(request *http.Request) (dto.ChangeStudentStatusRequest, error) {
+	var params dto.ChangeStudentStatusRequest
+
+	decoder := json.NewDecoder(request.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		return params, errors.ErrDecodingRequest
+	}
+
+	params.Student = mux.Vars(request)["email"]
+	if params.Student == "" {
+		return params, errors.ErrStudentRequired
+	}
+
+	if !utils.IsEmailValid(params.Student) {
+		return params, errors.ErrInvalidStudentEmail
+	}
+
+	if params.Status == "" {
+		return params, errors.ErrStatusRequired
+	}
+
+	if err := validateStatusReason(params.Reason); err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/dto"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+//ClassRoster handler retrieves the teachers and students of a class.
+//It expects the class id as path param and returns the class roster or error if any.
+func (ch classHandler) ClassRoster(writer http.ResponseWriter, request *http.Request) {
+
+	//validate params
+	rosterReq, err := processClassRosterParams(request)
+	if err != nil {
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//fetch class roster
+	response, err := ch.service.ClassRoster(request.Context(), rosterReq)
+	if err != nil {
+		logServiceError(request, "getting class roster failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(response)
+}
+
+//validate input parameters
+func processClassRosterParams(request *http.Request) (dto.ClassRosterRequest, error) {
+	var params dto.ClassRosterRequest
+
+	classID, err := classIDFromPath(request)
+	if err != nil {
+		return params, err
+	}
+	params.ClassID = classID
+
+	//graduated students are only returned on request
+	if value := request.URL.Query().Get("include_graduated"); value != "" {
+		includeGraduated, err := strconv.ParseBool(value)
+		if err != nil {
+			return params, errors.ErrInvalidIncludeGraduated
+		}
+		params.IncludeGraduated = includeGraduated
+	}
+
+	return params, nil
+}
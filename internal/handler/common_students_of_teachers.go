@@ -3,49 +3,140 @@ package handler
 import (
 	"class-management/errors"
 	"class-management/internal/dto"
+	"class-management/internal/models"
 	"class-management/internal/utils"
 	"encoding/json"
-	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
+
 // CommonStudentsOfTeachers handler retrieves a list of students common to a given list of teachers.
-//It expects the teacher email query param containing the email address(es) and return error if any or returns the list of common students if successful.
+//It expects the teacher email query param containing the email address(es) and return error if any or returns a page of common students if successful.
 func (th teacherHandler) CommonStudentsOfTeachers(writer http.ResponseWriter, request *http.Request) {
 
 	//validate params
-	allTeachers, isOk := request.URL.Query()["teacher"]
-	if !isOk || len(allTeachers) == 0 {
-		errors.JSONError(writer, errors.ErrMissingTeacherParam, http.StatusUnprocessableEntity)
+	commonReq, err := processCommonStudentsParams(request)
+	if err != nil {
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//fetch common students of given teachers
+	response, err := th.service.CommonStudentsOfTeachers(request.Context(), commonReq)
+	if err != nil {
+		logServiceError(request, "getting common students failed", err)
+		errors.WriteError(writer, request, err)
 		return
 	}
 
-	//validate given teachers
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(response)
+}
+
+//validate query parameters
+func processCommonStudentsParams(request *http.Request) (dto.CommonStudentsRequest, error) {
+	var params dto.CommonStudentsRequest
+	query := request.URL.Query()
+
+	allTeachers, isOk := query["teacher"]
+	if !isOk || len(allTeachers) == 0 {
+		return params, errors.ErrMissingTeacherParam
+	}
+
+	//validate given teachers, a teacher given more than once only counts once
+	found := make(map[string]bool)
 	for _, teacher := range allTeachers {
 		if !utils.IsEmailValid(teacher) {
-			errors.JSONError(writer, errors.ErrInvalidTeacherEmail, http.StatusUnprocessableEntity)
-			return
+			return params, errors.ErrInvalidTeacherEmail
+		}
+		if found[strings.ToLower(teacher)] {
+			continue
 		}
+		found[strings.ToLower(teacher)] = true
+		params.Teachers = append(params.Teachers, teacher)
 	}
 
-	//fetch common students of given teachers
-	students, err := th.service.CommonStudentsOfTeachers(allTeachers)
+	//students of these teachers are left out
+	for _, teacher := range query["exclude_teacher"] {
+		if !utils.IsEmailValid(teacher) {
+			return params, errors.ErrInvalidTeacherEmail
+		}
+		params.ExcludeTeachers = append(params.ExcludeTeachers, teacher)
+	}
+
+	//combine the students of all teachers by default
+	params.Mode = dto.CommonStudentsModeAll
+	switch mode := strings.ToLower(query.Get("mode")); mode {
+	case "", dto.CommonStudentsModeAll:
+	case dto.CommonStudentsModeAny:
+		params.Mode = mode
+	case dto.CommonStudentsModeAtLeast:
+		params.Mode = mode
+		minTeachers, err := strconv.Atoi(query.Get("min"))
+		if err != nil || minTeachers < 1 || minTeachers > len(params.Teachers) {
+			return params, errors.ErrInvalidMinTeachers
+		}
+		params.MinTeachers = minTeachers
+	default:
+		return params, errors.ErrInvalidCommonStudentsMode
+	}
+
+	//graduated students are only returned on request
+	if value := query.Get("include_graduated"); value != "" {
+		includeGraduated, err := strconv.ParseBool(value)
+		if err != nil {
+			return params, errors.ErrInvalidIncludeGraduated
+		}
+		params.IncludeGraduated = includeGraduated
+	}
+
+	//limit the students to a class if given
+	if value := query.Get("class_id"); value != "" {
+		classID, err := parseClassID(value)
+		if err != nil {
+			return params, err
+		}
+		params.ClassID = classID
+	}
+
+	//only return students with the given statuses
+	statuses, err := processStatusParams(query)
 	if err != nil {
-		fmt.Println("err in getting common students", err)
-		errors.JSONError(writer, err, http.StatusUnprocessableEntity)
-		return
+		return params, err
+	}
+	params.Statuses = statuses
+
+	//sort by email unless created_at is requested, ascending unless desc is requested
+	params.Sort = models.CommonStudentsSortEmail
+	if value := query.Get("sort"); value != "" {
+		if value != models.CommonStudentsSortEmail && value != models.CommonStudentsSortCreatedAt {
+			return params, errors.ErrInvalidSort
+		}
+		params.Sort = value
 	}
-	if students == nil {
-		students = []string{}
+	switch strings.ToLower(query.Get("order")) {
+	case "", "asc":
+	case "desc":
+		params.Descending = true
+	default:
+		return params, errors.ErrInvalidSort
 	}
 
-	response := dto.CommonStudentsResponse{
-		Students: students,
+	//paginate with limit and offset, without either of them every student is returned
+	if query.Get("limit") == "" && query.Get("offset") == "" {
+		return params, nil
+	}
+	page, err := processPageParams(query)
+	if err != nil {
+		return params, err
 	}
+	params.Limit = page.Limit
+	params.Offset = page.Offset
 
-	//prepare output
-	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
-	writer.Header().Set("X-Content-Type-Options", "nosniff")
-	writer.WriteHeader(http.StatusOK)
-	json.NewEncoder(writer).Encode(response)
+	return params, nil
 }
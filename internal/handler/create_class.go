@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/dto"
+	"class-management/internal/utils"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+//CreateClass handler creates a new class, optionally with the teachers who own it.
+//It expects the class name and teacher(s) email as input and returns the created class or error if any.
+func (ch classHandler) CreateClass(writer http.ResponseWriter, request *http.Request) {
+
+	//only admins can create classes
+	if !authorizeAdmin(writer, request) {
+		return
+	}
+
+	//validate params
+	createReq, err := processCreateClassParams(request)
+	if err != nil {
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//create class
+	response, err := ch.service.CreateClass(request.Context(), createReq)
+	if err != nil {
+		logServiceError(request, "class creation failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusCreated)
+	json.NewEncoder(writer).Encode(response)
+}
+
+//validate input parameters
+func processCreateClassParams(request *http.Request) (dto.CreateClassRequest, error) {
+	var params dto.CreateClassRequest
+
+	decoder := json.NewDecoder(request.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		return params, errors.ErrDecodingRequest
+	}
+
+	params.Name = strings.TrimSpace(params.Name)
+	if params.Name == "" {
+		return params, errors.ErrClassNameRequired
+	}
+
+	for _, teacher := range params.Teachers {
+		if !utils.IsEmailValid(teacher) {
+			return params, errors.ErrInvalidTeacherEmail
+		}
+	}
+
+	return params, nil
+}
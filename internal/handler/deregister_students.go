@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/dto"
+	"class-management/internal/utils"
+	"encoding/json"
+	"net/http"
+)
+
+//DeregisterStudents handler removes single or multiple students from a teacher.
+//It expects the teacher email and student(s) email as input and return error if any.
+func (th teacherHandler) DeregisterStudents(writer http.ResponseWriter, request *http.Request) {
+
+	//validate params
+	deregisterReq, err := processDeregisterParams(request)
+	if err != nil {
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//only the teacher or an admin can deregister students on behalf of the teacher
+	if !authorizeTeacher(writer, request, deregisterReq.Teacher) {
+		return
+	}
+
+	//deregister all students
+	err = th.service.DeregisterStudents(request.Context(), deregisterReq)
+	if err != nil {
+		logServiceError(request, "deregistration failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+//validate input parameters
+func processDeregisterParams(request *http.Request) (dto.DeregisterStudentsRequest, error) {
+	var params dto.DeregisterStudentsRequest
+
+	decoder := json.NewDecoder(request.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		return params, errors.ErrDecodingRequest
+	}
+
+	if params.Teacher == "" {
+		return params, errors.ErrTeacherRequired
+	}
+
+	if !utils.IsEmailValid(params.Teacher) {
+		return params, errors.ErrInvalidTeacherEmail
+	}
+
+	if len(params.Students) == 0 {
+		return params, errors.ErrStudentsRequiredForDeregistration
+	}
+
+	return params, nil
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/dto"
+	"encoding/json"
+	"net/http"
+)
+
+//EnrolClassStudents handler enrols single or multiple students in a class.
+//It expects the class id as path param and student(s) email as input and returns the enrolment result of every student or error if any.
+func (ch classHandler) EnrolClassStudents(writer http.ResponseWriter, request *http.Request) {
+
+	//validate params
+	enrolReq, err := processEnrolClassStudentsParams(request)
+	if err != nil {
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//only an admin or a teacher assigned to the class can enrol students in it
+	identity, ok := callerIdentity(writer, request)
+	if !ok {
+		return
+	}
+	enrolReq.ActingTeacher = actingTeacher(identity)
+
+	//enrol all students
+	response, err := ch.service.EnrolStudents(request.Context(), enrolReq)
+	if err != nil {
+		logServiceError(request, "enrolment failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(response)
+}
+
+//validate input parameters
+func processEnrolClassStudentsParams(request *http.Request) (dto.EnrolClassStudentsRequest, error) {
+	var params dto.EnrolClassStudentsRequest
+
+	classID, err := classIDFromPath(request)
+	if err != nil {
+		return params, err
+	}
+
+	decoder := json.NewDecoder(request.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		return params, errors.ErrDecodingRequest
+	}
+	params.ClassID = classID
+
+	if len(params.Students) == 0 {
+		return params, errors.ErrStudentsRequired
+	}
+
+	return params, nil
+}
@@ -5,7 +5,6 @@ import (
 	"class-management/internal/dto"
 	"class-management/internal/utils"
 	"encoding/json"
-	"fmt"
 	"net/http"
 )
 
@@ -15,22 +14,21 @@ func (th teacherHandler) FetchStudentsForNotification(writer http.ResponseWriter
 	//validate params
 	reqData, err := processStudentsForNotificationsParams(request)
 	if err != nil {
-		errors.JSONError(writer, err, http.StatusUnprocessableEntity)
+		errors.WriteError(writer, request, err)
 		return
 	}
 
-	//Fetch students for notification
-	students, err := th.service.FetchStudentsForNotification(reqData)
-	if err != nil {
-		fmt.Println("err in getting common students", err)
-		errors.JSONError(writer, err, http.StatusUnprocessableEntity)
+	//only the teacher or an admin can send notifications on behalf of the teacher
+	if !authorizeTeacher(writer, request, reqData.Teacher) {
 		return
 	}
 
-	response := struct {
-		Recipients []string `json:"recipients"`
-	}{
-		Recipients: students,
+	//Fetch students for notification
+	response, err := th.service.FetchStudentsForNotification(request.Context(), reqData)
+	if err != nil {
+		logServiceError(request, "fetching students for notification failed", err)
+		errors.WriteError(writer, request, err)
+		return
 	}
 
 	//prepare output
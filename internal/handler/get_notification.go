@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"class-management/errors"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+//GetNotification handler retrieves a notification with its recipients.
+//It expects the notification id as path param and returns the notification or error if any.
+func (nh notificationHandler) GetNotification(writer http.ResponseWriter, request *http.Request) {
+
+	//validate params
+	id, err := strconv.ParseUint(mux.Vars(request)["id"], 10, 64)
+	if err != nil || id == 0 {
+		errors.WriteError(writer, request, errors.ErrInvalidNotificationID)
+		return
+	}
+
+	//only the teacher who sent it or an admin can see the notification
+	identity, ok := callerIdentity(writer, request)
+	if !ok {
+		return
+	}
+
+	//fetch notification
+	response, err := nh.service.GetNotification(request.Context(), uint(id), actingTeacher(identity))
+	if err != nil {
+		logServiceError(request, "getting notification failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(response)
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+//GetStudent handler retrieves a student with the teachers the student is registered with.
+//It expects the student email as path param and returns the student or error if any.
+func (dh directoryHandler) GetStudent(writer http.ResponseWriter, request *http.Request) {
+
+	//validate params
+	student := mux.Vars(request)["email"]
+	if !utils.IsEmailValid(student) {
+		errors.WriteError(writer, request, errors.ErrInvalidStudentEmail)
+		return
+	}
+
+	//fetch student
+	response, err := dh.service.GetStudent(request.Context(), student)
+	if err != nil {
+		logServiceError(request, "getting student failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(response)
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+//GetTeacher handler retrieves a teacher.
+//It expects the teacher email as path param and returns the teacher or error if any.
+func (dh directoryHandler) GetTeacher(writer http.ResponseWriter, request *http.Request) {
+
+	//validate params
+	teacher := mux.Vars(request)["email"]
+	if !utils.IsEmailValid(teacher) {
+		errors.WriteError(writer, request, errors.ErrInvalidTeacherEmail)
+		return
+	}
+
+	//fetch teacher
+	response, err := dh.service.GetTeacher(request.Context(), teacher)
+	if err != nil {
+		logServiceError(request, "getting teacher failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(response)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/dto"
+	"class-management/internal/utils"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+//GraduateStudents handler graduates single or multiple students, all active students of a teacher or a whole grade level.
+//It expects student(s) email, a teacher email and/or a grade level as input and returns which students were graduated and which were skipped.
+func (th teacherHandler) GraduateStudents(writer http.ResponseWriter, request *http.Request) {
+
+	//validate params
+	graduateReq, err := processGraduateParams(request)
+	if err != nil {
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//only the teacher or an admin can graduate all students of a teacher, and only an admin a grade level
+	if graduateReq.Teacher != "" && !authorizeTeacher(writer, request, graduateReq.Teacher) {
+		return
+	}
+	if graduateReq.GradeLevel != "" && !authorizeAdmin(writer, request) {
+		return
+	}
+
+	//a teacher can only graduate students registered with them, and the caller is recorded with the changes
+	identity, ok := callerIdentity(writer, request)
+	if !ok {
+		return
+	}
+	graduateReq.ActingTeacher = actingTeacher(identity)
+	graduateReq.ChangedBy = identity.Name()
+
+	//Graduate students
+	response, err := th.service.GraduateStudents(request.Context(), graduateReq)
+	if err != nil {
+		logServiceError(request, "graduation failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(response)
+}
+
+//validate input parameters
+func processGraduateParams(request *http.Request) (dto.GraduateStudentsRequest, error) {
+	var params dto.GraduateStudentsRequest
+
+	decoder := json.NewDecoder(request.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		return params, errors.ErrDecodingRequest
+	}
+
+	params.GradeLevel = strings.TrimSpace(params.GradeLevel)
+	if len(params.Students) == 0 && params.Teacher == "" && params.GradeLevel == "" {
+		return params, errors.ErrGraduationTargetRequired
+	}
+
+	if params.Teacher != "" && !utils.IsEmailValid(params.Teacher) {
+		return params, errors.ErrInvalidTeacherEmail
+	}
+
+	if err := validateStatusReason(params.Reason); err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
@@ -1,7 +1,16 @@
 package handler
 
 import (
+	"class-management/errors"
+	"class-management/internal/health"
+	"class-management/internal/logging"
+	"class-management/internal/service/class"
+	"class-management/internal/service/directory"
+	"class-management/internal/service/notification"
 	"class-management/internal/service/teacher"
+	"net/http"
+
+	"go.uber.org/zap"
 )
 
 type teacherHandler struct {
@@ -13,3 +22,54 @@ func NewTeacherHandler(s teacher.TeacherService) *teacherHandler {
 		service: s,
 	}
 }
+
+type classHandler struct {
+	service class.ClassService
+}
+
+func NewClassHandler(s class.ClassService) *classHandler {
+	return &classHandler{
+		service: s,
+	}
+}
+
+type notificationHandler struct {
+	service notification.NotificationService
+}
+
+func NewNotificationHandler(s notification.NotificationService) *notificationHandler {
+	return &notificationHandler{
+		service: s,
+	}
+}
+
+type directoryHandler struct {
+	service directory.DirectoryService
+}
+
+func NewDirectoryHandler(s directory.DirectoryService) *directoryHandler {
+	return &directoryHandler{
+		service: s,
+	}
+}
+
+type healthHandler struct {
+	readiness *health.Checker
+}
+
+func NewHealthHandler(readiness *health.Checker) *healthHandler {
+	return &healthHandler{
+		readiness: readiness,
+	}
+}
+
+//Log an error returned by a service with the request logger. Rejections of invalid input are expected
+//and logged as info, anything that ends in a 5xx response is an error.
+func logServiceError(request *http.Request, message string, err error) {
+	logger := logging.FromContext(request.Context())
+	if errors.From(err).Status < http.StatusInternalServerError {
+		logger.Info(message, zap.Error(err))
+		return
+	}
+	logger.Error(message, zap.Error(err))
+}
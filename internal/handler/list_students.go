@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/dto"
+	"encoding/json"
+	"net/http"
+)
+
+//ListStudents handler lists the students ordered by email.
+//It expects optional status, limit and offset query params and returns a page of students or error if any.
+func (dh directoryHandler) ListStudents(writer http.ResponseWriter, request *http.Request) {
+
+	//validate params
+	listReq, err := processListStudentsParams(request)
+	if err != nil {
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//fetch students
+	response, err := dh.service.ListStudents(request.Context(), listReq)
+	if err != nil {
+		logServiceError(request, "listing students failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(response)
+}
+
+//validate query parameters
+func processListStudentsParams(request *http.Request) (dto.ListStudentsRequest, error) {
+	var params dto.ListStudentsRequest
+	query := request.URL.Query()
+
+	statuses, err := processStatusParams(query)
+	if err != nil {
+		return params, err
+	}
+	params.Statuses = statuses
+
+	page, err := processPageParams(query)
+	if err != nil {
+		return params, err
+	}
+	params.PageRequest = page
+
+	return params, nil
+}
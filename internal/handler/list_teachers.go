@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"class-management/errors"
+	"encoding/json"
+	"net/http"
+)
+
+//ListTeachers handler lists the teachers ordered by email.
+//It expects optional limit and offset query params and returns a page of teachers or error if any.
+func (dh directoryHandler) ListTeachers(writer http.ResponseWriter, request *http.Request) {
+
+	//validate params
+	page, err := processPageParams(request.URL.Query())
+	if err != nil {
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//fetch teachers
+	response, err := dh.service.ListTeachers(request.Context(), page)
+	if err != nil {
+		logServiceError(request, "listing teachers failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(response)
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"class-management/internal/health"
+	"encoding/json"
+	"net/http"
+)
+
+//Liveness handler reports that the process is up and serving requests.
+//It checks no dependencies, so a database outage doesn't get the app restarted.
+func (hh healthHandler) Liveness(writer http.ResponseWriter, request *http.Request) {
+	writeHealthReport(writer, health.Report{Status: health.StatusOK})
+}
+
+//Write the report as JSON, with HTTP 503 when a check failed so probes don't need to parse the body
+func writeHealthReport(writer http.ResponseWriter, report health.Report) {
+	status := http.StatusOK
+	if !report.OK() {
+		status = http.StatusServiceUnavailable
+	}
+
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.Header().Set("Cache-Control", "no-store")
+	writer.WriteHeader(status)
+	json.NewEncoder(writer).Encode(report)
+}
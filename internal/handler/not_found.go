@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"class-management/errors"
+	"net/http"
+)
+
+//NotFound handler answers requests to unknown endpoints with a problem details body
+func NotFound(writer http.ResponseWriter, request *http.Request) {
+	errors.WriteError(writer, request, errors.ErrRouteNotFound)
+}
+
+//MethodNotAllowed handler answers requests with a method the endpoint doesn't support with a problem details body
+func MethodNotAllowed(writer http.ResponseWriter, request *http.Request) {
+	errors.WriteError(writer, request, errors.ErrMethodNotAllowed)
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/dto"
+	"class-management/internal/models"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+)
+
+//validate limit and offset query parameters of a listing
+func processPageParams(query url.Values) (dto.PageRequest, error) {
+	page := dto.PageRequest{Limit: defaultPageLimit}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return page, errors.ErrInvalidPagination
+		}
+		page.Limit = limit
+	}
+	if value := query.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return page, errors.ErrInvalidPagination
+		}
+		page.Offset = offset
+	}
+
+	return page, nil
+}
+
+//validate the repeatable status query parameter of a listing
+func processStatusParams(query url.Values) ([]string, error) {
+	var statuses []string
+	for _, status := range query["status"] {
+		status = strings.ToUpper(status)
+		if !models.StatusStudent(status).IsValid() {
+			return nil, errors.ErrInvalidStudentStatus
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/dto"
+	"unicode/utf8"
+)
+
+//Longest value accepted for a text profile field
+const maxProfileFieldLength = 255
+
+//validate the text fields of a teacher profile
+func validateTeacherProfile(profile dto.TeacherProfile) error {
+	return validateProfileFields(profile.Name, profile.PreferredName, profile.Department)
+}
+
+//validate the text fields of a student profile
+func validateStudentProfile(profile dto.StudentProfile) error {
+	return validateProfileFields(profile.Name, profile.PreferredName, profile.StudentNumber, profile.GradeLevel)
+}
+
+func validateProfileFields(fields ...*string) error {
+	for _, field := range fields {
+		if field != nil && utf8.RuneCountInString(*field) > maxProfileFieldLength {
+			return errors.ErrInvalidProfile
+		}
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package handler
+
+import (
+	"net/http"
+)
+
+//Readiness handler runs the readiness checks, such as the database ping, and reports the status and latency of each.
+//It returns HTTP 503 when any check fails, so traffic is held back until the app can serve it.
+func (hh healthHandler) Readiness(writer http.ResponseWriter, request *http.Request) {
+	writeHealthReport(writer, hh.readiness.Run(request.Context()))
+}
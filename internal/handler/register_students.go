@@ -5,34 +5,51 @@ import (
 	"class-management/internal/dto"
 	"class-management/internal/utils"
 	"encoding/json"
-	"fmt"
 	"net/http"
 )
 
 //RegisterStudents handler registers single or multiple students with a teacher.A student can also be registered to multiple teachers.
-//It expects the teacher email and student(s) email as input and return error if any.
+//It expects the teacher email and student(s) email as input and returns the registration result of every student or error if any.
 func (th teacherHandler) RegisterStudents(writer http.ResponseWriter, request *http.Request) {
 
 	//validate params
 	registerReq, err := processRegisterParams(request)
 	if err != nil {
-		errors.JSONError(writer, err, http.StatusUnprocessableEntity)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//only the teacher or an admin can register students on behalf of the teacher
+	if !authorizeTeacher(writer, request, registerReq.Teacher) {
 		return
 	}
 
 	//register all students
-	err = th.service.RegisterStudents(registerReq)
+	response, err := th.service.RegisterStudents(request.Context(), registerReq)
 	if err != nil {
-		fmt.Println("err in registration process", err)
-		errors.JSONError(writer, err, http.StatusUnprocessableEntity)
+		logServiceError(request, "registration failed", err)
+		registrationError(writer, request, response, err)
 		return
 	}
 
 	//prepare output
 	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
 	writer.Header().Set("X-Content-Type-Options", "nosniff")
-	writer.WriteHeader(http.StatusNoContent)
-	json.NewEncoder(writer).Encode("Students has been registered successfully.")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(response)
+}
+
+//write registration error, listing the offending emails when a strict batch was rejected
+func registrationError(writer http.ResponseWriter, request *http.Request, response dto.RegistrationResponse, err error) {
+	if err == errors.ErrInvalidEmailsInBatch {
+		problem := errors.NewProblem(writer, request, err)
+		errors.WriteProblem(writer, dto.RegistrationRejectedResponse{
+			Problem: problem,
+			Results: response.Results,
+		}, problem.Status)
+		return
+	}
+	errors.WriteError(writer, request, err)
 }
 
 //validate input parameters
@@ -57,5 +74,11 @@ func processRegisterParams(request *http.Request) (dto.RegisterStudentsRequest,
 		return params, errors.ErrStudentsRequired
 	}
 
+	for _, profile := range params.Profiles {
+		if err := validateStudentProfile(profile); err != nil {
+			return params, err
+		}
+	}
+
 	return params, nil
 }
@@ -4,33 +4,38 @@ import (
 	"class-management/errors"
 	"class-management/internal/dto"
 	"encoding/json"
-	"fmt"
 	"net/http"
 )
 
 //RegisterTeachers handler registers single or multiple teachers.
+//It returns the registration result of every teacher or error if any.
 func (th teacherHandler) RegisterTeachers(writer http.ResponseWriter, request *http.Request) {
 
+	//only admins can register teachers
+	if !authorizeAdmin(writer, request) {
+		return
+	}
+
 	//validate params
 	registerReq, err := processRegisterTeachersParams(request)
 	if err != nil {
-		errors.JSONError(writer, err, http.StatusUnprocessableEntity)
+		errors.WriteError(writer, request, err)
 		return
 	}
 
 	//register all teachers
-	err = th.service.RegisterTeachers(registerReq)
+	response, err := th.service.RegisterTeachers(request.Context(), registerReq)
 	if err != nil {
-		fmt.Println("err in registration process")
-		errors.JSONError(writer, err, http.StatusUnprocessableEntity)
+		logServiceError(request, "teacher registration failed", err)
+		registrationError(writer, request, response, err)
 		return
 	}
 
 	//prepare output
 	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
 	writer.Header().Set("X-Content-Type-Options", "nosniff")
-	writer.WriteHeader(http.StatusNoContent)
-	json.NewEncoder(writer).Encode("Students has been registered successfully.")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(response)
 }
 
 func processRegisterTeachersParams(request *http.Request) (dto.RegisterTeachersRequest, error) {
@@ -46,5 +51,11 @@ func processRegisterTeachersParams(request *http.Request) (dto.RegisterTeachersR
 		return params, errors.ErrTeachersRequired
 	}
 
+	for _, profile := range params.Profiles {
+		if err := validateTeacherProfile(profile); err != nil {
+			return params, err
+		}
+	}
+
 	return params, nil
 }
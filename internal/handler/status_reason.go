@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"class-management/errors"
+	"unicode/utf8"
+)
+
+//Longest reason accepted for a status change, the size of the reason column of student_status_changes
+const maxStatusReasonLength = 1024
+
+//validate the free text reason given for a status change
+func validateStatusReason(reason string) error {
+	if utf8.RuneCountInString(reason) > maxStatusReasonLength {
+		return errors.ErrStatusReasonTooLong
+	}
+	return nil
+}
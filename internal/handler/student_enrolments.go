@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+//StudentEnrolments handler retrieves the enrolment history of a student, including deregistered registrations.
+//It expects the student email as path param and returns the registrations or error if any.
+func (dh directoryHandler) StudentEnrolments(writer http.ResponseWriter, request *http.Request) {
+
+	//validate params
+	student := mux.Vars(request)["email"]
+	if !utils.IsEmailValid(student) {
+		errors.WriteError(writer, request, errors.ErrInvalidStudentEmail)
+		return
+	}
+
+	//fetch enrolment history
+	response, err := dh.service.GetStudentEnrolments(request.Context(), student)
+	if err != nil {
+		logServiceError(request, "getting enrolment history failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(response)
+}
@@ -5,7 +5,6 @@ import (
 	"class-management/internal/dto"
 	"class-management/internal/utils"
 	"encoding/json"
-	"fmt"
 	"net/http"
 )
 
@@ -16,15 +15,23 @@ func (th teacherHandler) SuspendStudent(writer http.ResponseWriter, request *htt
 	//validate params
 	suspendReq, err := processSuspendParams(request)
 	if err != nil {
-		errors.JSONError(writer, err, http.StatusUnprocessableEntity)
+		errors.WriteError(writer, request, err)
 		return
 	}
 
+	//only an admin or a teacher the student is registered with can suspend the student, and the caller is recorded with the change
+	identity, ok := callerIdentity(writer, request)
+	if !ok {
+		return
+	}
+	suspendReq.ActingTeacher = actingTeacher(identity)
+	suspendReq.ChangedBy = identity.Name()
+
 	//Suspend Student
-	err = th.service.SuspendStudent(suspendReq)
+	err = th.service.SuspendStudent(request.Context(), suspendReq)
 	if err != nil {
-		fmt.Println("err in suspension process", err)
-		errors.JSONError(writer, err, http.StatusUnprocessableEntity)
+		logServiceError(request, "suspension failed", err)
+		errors.WriteError(writer, request, err)
 		return
 	}
 
@@ -53,5 +60,9 @@ func processSuspendParams(request *http.Request) (dto.SuspendRequest, error) {
 		return params, errors.ErrInvalidStudentEmail
 	}
 
+	if err := validateStatusReason(params.Reason); err != nil {
+		return params, err
+	}
+
 	return params, nil
 }
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+//TeacherNotifications handler lists the notifications sent by a teacher.
+//It expects the teacher email as path param and optional limit and offset query params, and returns a page of notifications or error if any.
+func (nh notificationHandler) TeacherNotifications(writer http.ResponseWriter, request *http.Request) {
+
+	//validate params
+	teacher := mux.Vars(request)["email"]
+	if !utils.IsEmailValid(teacher) {
+		errors.WriteError(writer, request, errors.ErrInvalidTeacherEmail)
+		return
+	}
+	page, err := processPageParams(request.URL.Query())
+	if err != nil {
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//only the teacher or an admin can see the teacher's notifications
+	if !authorizeTeacher(writer, request, teacher) {
+		return
+	}
+
+	//fetch notifications
+	response, err := nh.service.TeacherNotifications(request.Context(), teacher, page)
+	if err != nil {
+		logServiceError(request, "getting teacher notifications failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(response)
+}
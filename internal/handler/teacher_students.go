@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+//TeacherStudents handler lists the students registered with a teacher ordered by email.
+//It expects the teacher email as path param, optional limit and offset query params and returns a page of students or error if any.
+func (dh directoryHandler) TeacherStudents(writer http.ResponseWriter, request *http.Request) {
+
+	//validate params
+	teacher := mux.Vars(request)["email"]
+	if !utils.IsEmailValid(teacher) {
+		errors.WriteError(writer, request, errors.ErrInvalidTeacherEmail)
+		return
+	}
+	page, err := processPageParams(request.URL.Query())
+	if err != nil {
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//fetch students of the teacher
+	response, err := dh.service.ListTeacherStudents(request.Context(), teacher, page)
+	if err != nil {
+		logServiceError(request, "listing students of teacher failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(response)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/dto"
+	"class-management/internal/utils"
+	"encoding/json"
+	"net/http"
+)
+
+//UnsuspendStudent handler reinstates a suspended student.
+//It expects the student email as input param and return error if any
+func (th teacherHandler) UnsuspendStudent(writer http.ResponseWriter, request *http.Request) {
+
+	//validate params
+	unsuspendReq, err := processUnsuspendParams(request)
+	if err != nil {
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//only an admin or a teacher the student is registered with can reinstate the student, and the caller is recorded with the change
+	identity, ok := callerIdentity(writer, request)
+	if !ok {
+		return
+	}
+	unsuspendReq.ActingTeacher = actingTeacher(identity)
+	unsuspendReq.ChangedBy = identity.Name()
+
+	//Reinstate Student
+	err = th.service.UnsuspendStudent(request.Context(), unsuspendReq)
+	if err != nil {
+		logServiceError(request, "unsuspension failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+//validate input parameters
+func processUnsuspendParams(request *http.Request) (dto.UnsuspendRequest, error) {
+	var params dto.UnsuspendRequest
+
+	decoder := json.NewDecoder(request.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		return params, errors.ErrDecodingRequest
+	}
+
+	if params.Student == "" {
+		return params, errors.ErrStudentRequired
+	}
+
+	if !utils.IsEmailValid(params.Student) {
+		return params, errors.ErrInvalidStudentEmail
+	}
+
+	if err := validateStatusReason(params.Reason); err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
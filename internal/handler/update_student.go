@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/dto"
+	"class-management/internal/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+//UpdateStudent handler changes the profile fields of a student.
+//It expects the student email as path param and the fields to change in the body, and returns the updated student or error if any.
+func (dh directoryHandler) UpdateStudent(writer http.ResponseWriter, request *http.Request) {
+
+	//validate params
+	updateReq, err := processUpdateStudentParams(request)
+	if err != nil {
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//only an admin or a teacher the student is registered with can change the student's profile
+	identity, ok := callerIdentity(writer, request)
+	if !ok {
+		return
+	}
+	updateReq.ActingTeacher = actingTeacher(identity)
+
+	//update student
+	response, err := dh.service.UpdateStudent(request.Context(), updateReq)
+	if err != nil {
+		logServiceError(request, "updating student failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(response)
+}
+
+//validate input parameters
+func processUpdateStudentParams(request *http.Request) (dto.UpdateStudentRequest, error) {
+	var params dto.UpdateStudentRequest
+
+	params.Email = mux.Vars(request)["email"]
+	if !utils.IsEmailValid(params.Email) {
+		return params, errors.ErrInvalidStudentEmail
+	}
+
+	decoder := json.NewDecoder(request.Body)
+	err := decoder.Decode(&params.StudentProfile)
+	if err != nil {
+		return params, errors.ErrDecodingRequest
+	}
+
+	if err := validateStudentProfile(params.StudentProfile); err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
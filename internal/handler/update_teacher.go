@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/dto"
+	"class-management/internal/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+//UpdateTeacher handler changes the profile fields of a teacher.
+//It expects the teacher email as path param and the fields to change in the body, and returns the updated teacher or error if any.
+func (dh directoryHandler) UpdateTeacher(writer http.ResponseWriter, request *http.Request) {
+
+	//validate params
+	updateReq, err := processUpdateTeacherParams(request)
+	if err != nil {
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//only the teacher or an admin can change the teacher's profile
+	if !authorizeTeacher(writer, request, updateReq.Email) {
+		return
+	}
+
+	//update teacher
+	response, err := dh.service.UpdateTeacher(request.Context(), updateReq)
+	if err != nil {
+		logServiceError(request, "updating teacher failed", err)
+		errors.WriteError(writer, request, err)
+		return
+	}
+
+	//prepare output
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(response)
+}
+
+//validate input parameters
+func processUpdateTeacherParams(request *http.Request) (dto.UpdateTeacherRequest, error) {
+	var params dto.UpdateTeacherRequest
+
+	params.Email = mux.Vars(request)["email"]
+	if !utils.IsEmailValid(params.Email) {
+		return params, errors.ErrInvalidTeacherEmail
+	}
+
+	decoder := json.NewDecoder(request.Body)
+	err := decoder.Decode(&params.TeacherProfile)
+	if err != nil {
+		return params, errors.ErrDecodingRequest
+	}
+
+	if err := validateTeacherProfile(params.TeacherProfile); err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
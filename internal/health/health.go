@@ -0,0 +1,118 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+//Check reports whether one dependency is healthy. It should return once ctx is done.
+type Check func(ctx context.Context) error
+
+//Result of one check
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+//Report is ok only when every check passed
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+//Checker runs a set of named checks concurrently, each bounded by a timeout
+type Checker struct {
+	checks  []namedCheck
+	timeout time.Duration
+}
+
+//NewChecker builds a checker whose checks fail after timeout. Defaults to 2 seconds.
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &Checker{
+		timeout: timeout,
+	}
+}
+
+//Add registers a check under name. Checks must be added before the checker is used.
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+//Run executes every check and reports their status and latency
+func (c *Checker) Run(ctx context.Context) Report {
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(c.checks))}
+
+	var mutex sync.Mutex
+	var wait sync.WaitGroup
+	for _, named := range c.checks {
+		wait.Add(1)
+		go func(named namedCheck) {
+			defer wait.Done()
+			result := c.run(ctx, named.check)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			report.Checks[named.name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusFail
+			}
+		}(named)
+	}
+	wait.Wait()
+
+	return report
+}
+
+//Run one check, turning a panic or a check that ignores its deadline into a failure
+func (c *Checker) run(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- fmt.Errorf("check panicked: %v", recovered)
+			}
+		}()
+		done <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := CheckResult{
+		Status:    StatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	gormutils "gorm.io/gorm/utils"
+)
+
+//Queries slower than this are logged as warnings
+const slowQueryThreshold = 200 * time.Millisecond
+
+//gormLogger writes GORM messages and queries through zap. It logs with the logger of the query context
+//when there is one, so repository lines carry the request ID. The caller is the repository line that ran
+//the query rather than the GORM internals.
+type gormLogger struct {
+	logger *zap.Logger
+	level  gormlogger.LogLevel
+}
+
+//NewGormLogger returns a GORM logger that logs failed queries as errors, slow queries as warnings and
+//every other query at debug level
+func NewGormLogger(logger *zap.Logger) gormlogger.Interface {
+	return &gormLogger{
+		logger: logger.WithOptions(zap.WithCaller(false)),
+		level:  gormlogger.Info,
+	}
+}
+
+func (l *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+//Logger of ctx when it has one, the injected logger otherwise
+func (l *gormLogger) from(ctx context.Context) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey).(*zap.Logger); ok {
+		return logger.WithOptions(zap.WithCaller(false))
+	}
+	return l.logger
+}
+
+func (l *gormLogger) Info(ctx context.Context, message string, args ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.from(ctx).Info(fmt.Sprintf(message, args...))
+	}
+}
+
+func (l *gormLogger) Warn(ctx context.Context, message string, args ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.from(ctx).Warn(fmt.Sprintf(message, args...))
+	}
+}
+
+func (l *gormLogger) Error(ctx context.Context, message string, args ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.from(ctx).Error(fmt.Sprintf(message, args...))
+	}
+}
+
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	logger := l.from(ctx)
+	elapsed := time.Since(begin)
+	fields := func() []zap.Field {
+		sql, rows := fc()
+		return []zap.Field{
+			zap.String("caller", gormutils.FileWithLineNum()),
+			zap.String("sql", sql),
+			zap.Int64("rows", rows),
+			zap.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+		}
+	}
+
+	switch {
+	//a lookup finding nothing is an expected result that the repos turn into nil
+	case err != nil && l.level >= gormlogger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		logger.Error("query failed", append(fields(), zap.Error(err))...)
+	case elapsed > slowQueryThreshold && l.level >= gormlogger.Warn:
+		logger.Warn("slow query", fields()...)
+	case l.level >= gormlogger.Info:
+		if entry := logger.Check(zap.DebugLevel, "query"); entry != nil {
+			entry.Write(fields()...)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"class-management/internal/utils"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+//Header carrying the request ID in both directions
+const RequestIDHeader = "X-Request-ID"
+
+//IDs accepted from callers, anything else is replaced so it can't forge log lines
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+//RequestID middleware takes the caller's X-Request-ID, or generates one, and echoes it in the response.
+//The request context carries the ID and a logger that adds it to every line.
+func RequestID(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			requestID := request.Header.Get(RequestIDHeader)
+			if !validRequestID.MatchString(requestID) {
+				requestID = newRequestID()
+			}
+			writer.Header().Set(RequestIDHeader, requestID)
+
+			ctx := WithRequestID(request.Context(), requestID)
+			ctx = WithLogger(ctx, logger.With(zap.String("request_id", requestID)))
+			next.ServeHTTP(writer, request.WithContext(ctx))
+		})
+	}
+}
+
+//Random 128 bit ID in hex
+func newRequestID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(id)
+}
+
+//AccessLog middleware writes one line per request once it is served.
+//It must run inside RequestID to log with the request ID.
+func AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		start := time.Now()
+		recorder := utils.NewStatusRecorder(writer)
+		next.ServeHTTP(recorder, request)
+
+		level := zapcore.InfoLevel
+		if recorder.Status >= http.StatusInternalServerError {
+			level = zapcore.ErrorLevel
+		}
+		FromContext(request.Context()).Check(level, "request served").Write(
+			zap.String("method", request.Method),
+			zap.String("path", request.URL.Path),
+			zap.Int("status", recorder.Status),
+			zap.Int("bytes", recorder.Bytes),
+			zap.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			zap.String("remote_addr", request.RemoteAddr),
+			zap.String("user_agent", request.UserAgent()),
+		)
+	})
+}
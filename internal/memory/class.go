@@ -0,0 +1,195 @@
+package memory
+
+import (
+	"class-management/internal/models"
+	"context"
+	"time"
+)
+
+type classRepo struct {
+	store *Store
+}
+
+func NewClassRepo(store *Store) models.ClassRepo {
+	return &classRepo{store}
+}
+
+//Create a new class. Names are unique.
+func (c *classRepo) CreateClass(ctx context.Context, class *models.Class) (*models.Class, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	for _, other := range c.store.classes {
+		if other.Name == class.Name {
+			return nil, duplicateKey("classes.name")
+		}
+	}
+
+	c.store.lastClassID++
+	class.ID = c.store.lastClassID
+	if class.CreatedAt.IsZero() {
+		class.CreatedAt = time.Now()
+	}
+	class.UpdatedAT = class.CreatedAt
+	c.store.classes[class.ID] = *class
+
+	return class, nil
+}
+
+//Get class detail by its id
+func (c *classRepo) GetClassByID(ctx context.Context, id uint) (*models.Class, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	class, ok := c.store.classes[id]
+	if !ok {
+		return nil, nil
+	}
+	return &class, nil
+}
+
+//Get class detail by its name
+func (c *classRepo) GetClassByName(ctx context.Context, name string) (*models.Class, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	for _, class := range c.store.classes {
+		if class.Name == name {
+			return &class, nil
+		}
+	}
+	return nil, nil
+}
+
+//Assign a teacher to a class. A teacher is assigned to a class only once.
+func (c *classRepo) AssignTeacher(ctx context.Context, classTeacher *models.ClassTeacher) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	if _, ok := c.store.classTeacher(classTeacher.ClassID, classTeacher.TeacherID); ok {
+		return duplicateKey("class_teachers.class_id, class_teachers.teacher_id")
+	}
+
+	c.store.lastClassTeacherID++
+	classTeacher.ID = c.store.lastClassTeacherID
+	if classTeacher.CreatedAt.IsZero() {
+		classTeacher.CreatedAt = time.Now()
+	}
+	c.store.classTeachers[classTeacher.ID] = *classTeacher
+
+	return nil
+}
+
+//Check if given teacher is assigned to given class
+func (c *classRepo) IsTeacherAssignedToClass(ctx context.Context, classID uint, teacherID uint) (*models.ClassTeacher, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	classTeacher, ok := c.store.classTeacher(classID, teacherID)
+	if !ok {
+		return nil, nil
+	}
+	return &classTeacher, nil
+}
+
+//Enrol a student in a class. A student is enrolled in a class only once.
+func (c *classRepo) EnrolStudent(ctx context.Context, classStudent *models.ClassStudent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	if _, ok := c.store.classStudent(classStudent.ClassID, classStudent.StudentID); ok {
+		return duplicateKey("class_students.class_id, class_students.student_id")
+	}
+
+	c.store.lastClassStudentID++
+	classStudent.ID = c.store.lastClassStudentID
+	if classStudent.CreatedAt.IsZero() {
+		classStudent.CreatedAt = time.Now()
+	}
+	c.store.classStudents[classStudent.ID] = *classStudent
+
+	return nil
+}
+
+//Check if given student is enrolled in given class
+func (c *classRepo) IsStudentEnrolledInClass(ctx context.Context, classID uint, studentID uint) (*models.ClassStudent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	classStudent, ok := c.store.classStudent(classID, studentID)
+	if !ok {
+		return nil, nil
+	}
+	return &classStudent, nil
+}
+
+//Get all teachers assigned to a class, ordered by email
+func (c *classRepo) GetClassTeachers(ctx context.Context, classID uint) ([]models.Teacher, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	teachers := make([]models.Teacher, 0)
+	for _, classTeacher := range c.store.classTeachers {
+		if classTeacher.ClassID != classID {
+			continue
+		}
+		if teacher, ok := c.store.teachers[classTeacher.TeacherID]; ok {
+			teachers = append(teachers, cloneTeacher(teacher))
+		}
+	}
+	sortTeachers(teachers)
+	return teachers, nil
+}
+
+//Get all students enrolled in a class, ordered by email. Graduated students are left out unless includeGraduated is set.
+func (c *classRepo) GetClassStudents(ctx context.Context, classID uint, includeGraduated bool) ([]models.Student, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	students := make([]models.Student, 0)
+	for studentID := range c.store.classStudentIDs(classID) {
+		student, ok := c.store.students[studentID]
+		if !ok || (!includeGraduated && student.Status == models.StatusGraduated) {
+			continue
+		}
+		students = append(students, cloneStudent(student))
+	}
+	sortStudents(students)
+	return students, nil
+}
@@ -0,0 +1,177 @@
+package memory
+
+import (
+	"class-management/internal/models"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+//Store holds the rows shared by the in-memory repositories. It is safe for concurrent use.
+//Every repository built on the same store sees the same data, like repositories sharing a database.
+//Like database calls, repository calls fail with the context error once their context is done.
+type Store struct {
+	mu sync.RWMutex
+
+	teachers        map[uint]models.Teacher
+	students        map[uint]models.Student
+	teacherStudents map[uint]models.TeacherStudent
+	statusChanges   []models.StudentStatusChange
+	classes         map[uint]models.Class
+	classTeachers   map[uint]models.ClassTeacher
+	classStudents   map[uint]models.ClassStudent
+
+	lastTeacherID        uint
+	lastStudentID        uint
+	lastTeacherStudentID uint
+	lastStatusChangeID   uint
+	lastClassID          uint
+	lastClassTeacherID   uint
+	lastClassStudentID   uint
+}
+
+func NewStore() *Store {
+	return &Store{
+		teachers:        make(map[uint]models.Teacher),
+		students:        make(map[uint]models.Student),
+		teacherStudents: make(map[uint]models.TeacherStudent),
+		classes:         make(map[uint]models.Class),
+		classTeachers:   make(map[uint]models.ClassTeacher),
+		classStudents:   make(map[uint]models.ClassStudent),
+	}
+}
+
+//StatusChanges returns the recorded student status changes in the order they were made
+func (s *Store) StatusChanges() []models.StudentStatusChange {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]models.StudentStatusChange(nil), s.statusChanges...)
+}
+
+//duplicateKey reports a violated unique column the way the database would
+func duplicateKey(column string) error {
+	return fmt.Errorf("%w: %s", gorm.ErrDuplicatedKey, column)
+}
+
+//paginate returns the page of n rows starting at offset. A negative limit means no limit, as in GORM.
+func paginate(n int, page models.Page) (int, int) {
+	start := page.Offset
+	if start < 0 {
+		start = 0
+	}
+	if start > n {
+		start = n
+	}
+	end := n
+	if page.Limit >= 0 && start+page.Limit < end {
+		end = start + page.Limit
+	}
+	return start, end
+}
+
+//cloneMetadata copies metadata through JSON, so callers never share maps with the store
+//and values read back have the types a JSON column would give them
+func cloneMetadata(metadata models.Metadata) models.Metadata {
+	if metadata == nil {
+		return nil
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil
+	}
+	var clone models.Metadata
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil
+	}
+	return clone
+}
+
+func cloneTeacher(teacher models.Teacher) models.Teacher {
+	teacher.Metadata = cloneMetadata(teacher.Metadata)
+	return teacher
+}
+
+func cloneStudent(student models.Student) models.Student {
+	if student.StudentNumber != nil {
+		number := *student.StudentNumber
+		student.StudentNumber = &number
+	}
+	student.Metadata = cloneMetadata(student.Metadata)
+	return student
+}
+
+func cloneTeacherStudent(teacherStudent models.TeacherStudent) models.TeacherStudent {
+	if teacherStudent.ArchivedAt != nil {
+		archivedAt := *teacherStudent.ArchivedAt
+		teacherStudent.ArchivedAt = &archivedAt
+	}
+	return teacherStudent
+}
+
+//teacherByEmail looks up a teacher. Emails match regardless of case, like in MySQL. The caller holds the lock.
+func (s *Store) teacherByEmail(email string) (models.Teacher, bool) {
+	for _, teacher := range s.teachers {
+		if strings.EqualFold(teacher.Email, email) {
+			return teacher, true
+		}
+	}
+	return models.Teacher{}, false
+}
+
+//activeRegistrations returns the registrations that are not deregistered. The caller holds the lock.
+func (s *Store) activeRegistrations() []models.TeacherStudent {
+	registrations := make([]models.TeacherStudent, 0, len(s.teacherStudents))
+	for _, registration := range s.teacherStudents {
+		if !registration.DeletedAt.Valid {
+			registrations = append(registrations, registration)
+		}
+	}
+	return registrations
+}
+
+//currentRegistrations returns the registrations that are neither deregistered nor archived by graduation.
+//The caller holds the lock.
+func (s *Store) currentRegistrations() []models.TeacherStudent {
+	registrations := make([]models.TeacherStudent, 0, len(s.teacherStudents))
+	for _, registration := range s.activeRegistrations() {
+		if registration.ArchivedAt == nil {
+			registrations = append(registrations, registration)
+		}
+	}
+	return registrations
+}
+
+//classTeacher looks up the assignment of a teacher to a class. The caller holds the lock.
+func (s *Store) classTeacher(classID uint, teacherID uint) (models.ClassTeacher, bool) {
+	for _, classTeacher := range s.classTeachers {
+		if classTeacher.ClassID == classID && classTeacher.TeacherID == teacherID {
+			return classTeacher, true
+		}
+	}
+	return models.ClassTeacher{}, false
+}
+
+//classStudent looks up the enrolment of a student in a class. The caller holds the lock.
+func (s *Store) classStudent(classID uint, studentID uint) (models.ClassStudent, bool) {
+	for _, classStudent := range s.classStudents {
+		if classStudent.ClassID == classID && classStudent.StudentID == studentID {
+			return classStudent, true
+		}
+	}
+	return models.ClassStudent{}, false
+}
+
+//classStudentIDs returns the ids of the students enrolled in a class. The caller holds the lock.
+func (s *Store) classStudentIDs(classID uint) map[uint]bool {
+	ids := make(map[uint]bool)
+	for _, classStudent := range s.classStudents {
+		if classStudent.ClassID == classID {
+			ids[classStudent.StudentID] = true
+		}
+	}
+	return ids
+}
@@ -0,0 +1,277 @@
+package memory
+
+import (
+	"class-management/internal/models"
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+type studentRepo struct {
+	store *Store
+}
+
+func NewStudentRepo(store *Store) models.StudentRepo {
+	return &studentRepo{store}
+}
+
+//Get student detail by its email id
+func (s *studentRepo) GetStudentByEmail(ctx context.Context, email string) (*models.Student, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	for _, student := range s.store.students {
+		if strings.EqualFold(student.Email, email) {
+			student = cloneStudent(student)
+			return &student, nil
+		}
+	}
+	return nil, nil
+}
+
+//Get student detail by its school issued student number
+func (s *studentRepo) GetStudentByNumber(ctx context.Context, studentNumber string) (*models.Student, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	for _, student := range s.store.students {
+		if student.StudentNumber != nil && *student.StudentNumber == studentNumber {
+			student = cloneStudent(student)
+			return &student, nil
+		}
+	}
+	return nil, nil
+}
+
+//create a new student. Emails and student numbers are unique and the status defaults to ACTIVE.
+func (s *studentRepo) CreateStudent(ctx context.Context, student *models.Student) (*models.Student, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if err := s.checkUnique(*student); err != nil {
+		return nil, err
+	}
+
+	s.store.lastStudentID++
+	student.ID = s.store.lastStudentID
+	if student.CreatedAt.IsZero() {
+		student.CreatedAt = time.Now()
+	}
+	student.UpdatedAT = student.CreatedAt
+
+	stored := cloneStudent(*student)
+	if stored.Status == "" {
+		stored.Status = models.StatusActive
+	}
+	s.store.students[student.ID] = stored
+
+	return student, nil
+}
+
+//Move student's status from change.FromStatus to change.ToStatus and record the change
+func (s *studentRepo) UpdateStudentStatus(ctx context.Context, student *models.Student, change *models.StudentStatusChange) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	return s.changeStatus(student, change)
+}
+
+//Mark student as graduated, record the change and archive its teacher registrations
+func (s *studentRepo) GraduateStudent(ctx context.Context, student *models.Student, change *models.StudentStatusChange) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if err := s.changeStatus(student, change); err != nil {
+		return err
+	}
+
+	archivedAt := time.Now()
+	for id, registration := range s.store.teacherStudents {
+		if registration.StudentID != student.ID || registration.ArchivedAt != nil || registration.DeletedAt.Valid {
+			continue
+		}
+		registration.ArchivedAt = &archivedAt
+		registration.UpdatedAT = archivedAt
+		s.store.teacherStudents[id] = registration
+	}
+
+	return nil
+}
+
+//Get a page of students ordered by email, with the total number of matching students.
+//All students are listed when no statuses are given.
+func (s *studentRepo) ListStudents(ctx context.Context, statuses []models.StatusStudent, page models.Page) ([]models.Student, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	var students []models.Student
+	for _, student := range s.store.students {
+		if len(statuses) > 0 && !hasStatus(statuses, student.Status) {
+			continue
+		}
+		students = append(students, cloneStudent(student))
+	}
+	sortStudents(students)
+
+	start, end := paginate(len(students), page)
+	return students[start:end], int64(len(students)), nil
+}
+
+//Get a page of the students currently registered with a teacher ordered by email, with the total number of them.
+//Registrations archived by graduation are left out.
+func (s *studentRepo) ListStudentsByTeacher(ctx context.Context, teacherID uint, page models.Page) ([]models.Student, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	var students []models.Student
+	for _, registration := range s.store.currentRegistrations() {
+		if registration.TeacherID != teacherID {
+			continue
+		}
+		if student, ok := s.store.students[registration.StudentID]; ok {
+			students = append(students, cloneStudent(student))
+		}
+	}
+	sortStudents(students)
+
+	start, end := paginate(len(students), page)
+	return students[start:end], int64(len(students)), nil
+}
+
+//Get the students of a grade level ordered by email. Graduated students are left out unless includeGraduated is set.
+func (s *studentRepo) GetStudentsByGradeLevel(ctx context.Context, gradeLevel string, includeGraduated bool) ([]models.Student, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	var students []models.Student
+	for _, student := range s.store.students {
+		if student.GradeLevel != gradeLevel {
+			continue
+		}
+		if !includeGraduated && student.Status == models.StatusGraduated {
+			continue
+		}
+		students = append(students, cloneStudent(student))
+	}
+	sortStudents(students)
+
+	return students, nil
+}
+
+//Save the profile fields of a student. Student numbers stay unique.
+func (s *studentRepo) UpdateStudentProfile(ctx context.Context, student *models.Student) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	stored, ok := s.store.students[student.ID]
+	if !ok {
+		return nil
+	}
+	stored.Name = student.Name
+	stored.PreferredName = student.PreferredName
+	stored.StudentNumber = student.StudentNumber
+	stored.GradeLevel = student.GradeLevel
+	stored.Metadata = student.Metadata
+	if err := s.checkUnique(stored); err != nil {
+		return err
+	}
+	stored.UpdatedAT = time.Now()
+	s.store.students[student.ID] = cloneStudent(stored)
+
+	return nil
+}
+
+//checkUnique reports an email or student number already used by another student. The caller holds the lock.
+func (s *studentRepo) checkUnique(student models.Student) error {
+	for id, other := range s.store.students {
+		if id == student.ID {
+			continue
+		}
+		if strings.EqualFold(other.Email, student.Email) {
+			return duplicateKey("students.email")
+		}
+		if student.StudentNumber != nil && other.StudentNumber != nil && *other.StudentNumber == *student.StudentNumber {
+			return duplicateKey("students.student_number")
+		}
+	}
+	return nil
+}
+
+func hasStatus(statuses []models.StatusStudent, status models.StatusStudent) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func sortStudents(students []models.Student) {
+	sort.Slice(students, func(i, j int) bool {
+		if students[i].Email != students[j].Email {
+			return students[i].Email < students[j].Email
+		}
+		return students[i].ID < students[j].ID
+	})
+}
+
+//changeStatus updates only the stored status, and only while it still is change.FromStatus, like the
+//conditional update of the database repository. The caller holds the lock.
+func (s *studentRepo) changeStatus(student *models.Student, change *models.StudentStatusChange) error {
+	stored, ok := s.store.students[student.ID]
+	if !ok || stored.Status != change.FromStatus {
+		return models.ErrStatusChanged
+	}
+	stored.Status = change.ToStatus
+	stored.UpdatedAT = time.Now()
+	s.store.students[student.ID] = stored
+	student.Status = stored.Status
+	student.UpdatedAT = stored.UpdatedAT
+
+	s.store.lastStatusChangeID++
+	change.ID = s.store.lastStatusChangeID
+	change.StudentID = student.ID
+	if change.CreatedAt.IsZero() {
+		change.CreatedAt = time.Now()
+	}
+	s.store.statusChanges = append(s.store.statusChanges, *change)
+
+	return nil
+}
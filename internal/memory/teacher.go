@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"class-management/internal/models"
+	"context"
+	"sort"
+	"time"
+)
+
+type teacherRepo struct {
+	store *Store
+}
+
+func NewTeacherRepo(store *Store) models.TeacherRepo {
+	return &teacherRepo{store}
+}
+
+//Create a new teacher. Emails are unique.
+func (t *teacherRepo) CreateTeacher(ctx context.Context, teacher *models.Teacher) (*models.Teacher, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	if _, ok := t.store.teacherByEmail(teacher.Email); ok {
+		return nil, duplicateKey("teachers.email")
+	}
+
+	t.store.lastTeacherID++
+	teacher.ID = t.store.lastTeacherID
+	if teacher.CreatedAt.IsZero() {
+		teacher.CreatedAt = time.Now()
+	}
+	teacher.UpdatedAT = teacher.CreatedAt
+	t.store.teachers[teacher.ID] = cloneTeacher(*teacher)
+
+	return teacher, nil
+}
+
+//Get teacher's detail by its email id
+func (t *teacherRepo) GetTeacherByEmail(ctx context.Context, email string) (*models.Teacher, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	teacher, ok := t.store.teacherByEmail(email)
+	if !ok {
+		return nil, nil
+	}
+	teacher = cloneTeacher(teacher)
+	return &teacher, nil
+}
+
+//Get a page of teachers ordered by email, with the total number of teachers
+func (t *teacherRepo) ListTeachers(ctx context.Context, page models.Page) ([]models.Teacher, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	teachers := make([]models.Teacher, 0, len(t.store.teachers))
+	for _, teacher := range t.store.teachers {
+		teachers = append(teachers, cloneTeacher(teacher))
+	}
+	sortTeachers(teachers)
+
+	start, end := paginate(len(teachers), page)
+	return teachers[start:end], int64(len(teachers)), nil
+}
+
+//Get the teachers a student is currently registered with, ordered by email. Registrations archived by graduation are left out.
+func (t *teacherRepo) GetTeachersByStudent(ctx context.Context, studentID uint) ([]models.Teacher, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	var teachers []models.Teacher
+	for _, registration := range t.store.currentRegistrations() {
+		if registration.StudentID != studentID {
+			continue
+		}
+		if teacher, ok := t.store.teachers[registration.TeacherID]; ok {
+			teachers = append(teachers, cloneTeacher(teacher))
+		}
+	}
+	sortTeachers(teachers)
+
+	return teachers, nil
+}
+
+//Save the profile fields of a teacher
+func (t *teacherRepo) UpdateTeacherProfile(ctx context.Context, teacher *models.Teacher) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	stored, ok := t.store.teachers[teacher.ID]
+	if !ok {
+		return nil
+	}
+	stored.Name = teacher.Name
+	stored.PreferredName = teacher.PreferredName
+	stored.Department = teacher.Department
+	stored.Metadata = cloneMetadata(teacher.Metadata)
+	stored.UpdatedAT = time.Now()
+	t.store.teachers[teacher.ID] = stored
+
+	return nil
+}
+
+func sortTeachers(teachers []models.Teacher) {
+	sort.Slice(teachers, func(i, j int) bool {
+		if teachers[i].Email != teachers[j].Email {
+			return teachers[i].Email < teachers[j].Email
+		}
+		return teachers[i].ID < teachers[j].ID
+	})
+}
@@ -0,0 +1,254 @@
+package memory
+
+import (
+	"class-management/internal/models"
+	"context"
+	"sort"
+	"time"
+)
+
+type teacherStudentRepo struct {
+	store *Store
+}
+
+func NewTeacherStudentRepo(store *Store) models.TeacherStudentRepo {
+	return &teacherStudentRepo{store}
+}
+
+//Register a student with a teacher
+func (ts *teacherStudentRepo) CreateTeacherStudent(ctx context.Context, teacherStudentObj *models.TeacherStudent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ts.store.mu.Lock()
+	defer ts.store.mu.Unlock()
+
+	ts.store.lastTeacherStudentID++
+	teacherStudentObj.ID = ts.store.lastTeacherStudentID
+	if teacherStudentObj.CreatedAt.IsZero() {
+		teacherStudentObj.CreatedAt = time.Now()
+	}
+	teacherStudentObj.UpdatedAT = teacherStudentObj.CreatedAt
+	ts.store.teacherStudents[teacherStudentObj.ID] = cloneTeacherStudent(*teacherStudentObj)
+
+	return nil
+}
+
+//Deregister a student from a teacher. The registration is soft deleted so past enrolments are kept.
+func (ts *teacherStudentRepo) DeleteTeacherStudent(ctx context.Context, teacherID uint, studentID uint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ts.store.mu.Lock()
+	defer ts.store.mu.Unlock()
+
+	deletedAt := time.Now()
+	for _, registration := range ts.store.activeRegistrations() {
+		if registration.TeacherID == teacherID && registration.StudentID == studentID {
+			registration.DeletedAt.Time = deletedAt
+			registration.DeletedAt.Valid = true
+			ts.store.teacherStudents[registration.ID] = registration
+		}
+	}
+
+	return nil
+}
+
+//Check if given student is registered with given teacher
+func (ts *teacherStudentRepo) IsStudentRegisteredForTeacher(ctx context.Context, teacherID uint, studentID uint) (*models.TeacherStudent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ts.store.mu.RLock()
+	defer ts.store.mu.RUnlock()
+
+	var found *models.TeacherStudent
+	for _, registration := range ts.store.activeRegistrations() {
+		if registration.TeacherID != teacherID || registration.StudentID != studentID {
+			continue
+		}
+		//the oldest registration wins, like the primary key order of the database
+		if found == nil || registration.ID < found.ID {
+			registration = cloneTeacherStudent(registration)
+			found = &registration
+		}
+	}
+
+	return found, nil
+}
+
+//Get list of students registered with at least MinTeachers of a given list of teachers and with none of ExcludeTeachers.
+//Graduated students and archived registrations are left out unless IncludeGraduated is set and the list is limited
+//to a class when ClassID is set.
+func (ts *teacherStudentRepo) GetCommonStudents(ctx context.Context, filter models.CommonStudentsFilter) ([]string, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+	ts.store.mu.RLock()
+	defer ts.store.mu.RUnlock()
+
+	teachers := ts.teacherIDs(filter.Teachers)
+	excludeTeachers := ts.teacherIDs(filter.ExcludeTeachers)
+	var classStudents map[uint]bool
+	if filter.ClassID != 0 {
+		classStudents = ts.store.classStudentIDs(filter.ClassID)
+	}
+
+	//distinct given teachers per student, counted like COUNT(DISTINCT t.id) in the SQL query
+	registrations := make(map[uint]map[uint]bool)
+	excluded := make(map[uint]bool)
+	for _, registration := range ts.registrations(filter.IncludesGraduated()) {
+		if teachers[registration.TeacherID] {
+			if registrations[registration.StudentID] == nil {
+				registrations[registration.StudentID] = make(map[uint]bool)
+			}
+			registrations[registration.StudentID][registration.TeacherID] = true
+		}
+		if excludeTeachers[registration.TeacherID] {
+			excluded[registration.StudentID] = true
+		}
+	}
+
+	var students []models.Student
+	for studentID, studentTeachers := range registrations {
+		student, ok := ts.store.students[studentID]
+		if !ok || excluded[studentID] || len(studentTeachers) < filter.MinTeachers {
+			continue
+		}
+		if classStudents != nil && !classStudents[studentID] {
+			continue
+		}
+		if len(filter.Statuses) > 0 {
+			if !hasStatus(filter.Statuses, student.Status) {
+				continue
+			}
+		} else if !filter.IncludeGraduated && student.Status == models.StatusGraduated {
+			continue
+		}
+		students = append(students, student)
+	}
+
+	//email and id break ties so pages never overlap
+	sort.Slice(students, func(i, j int) bool {
+		a, b := students[i], students[j]
+		if filter.Descending {
+			a, b = b, a
+		}
+		if filter.Sort == models.CommonStudentsSortCreatedAt && !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		if a.Email != b.Email {
+			return a.Email < b.Email
+		}
+		return a.ID < b.ID
+	})
+
+	page := models.Page{Limit: filter.Limit, Offset: filter.Offset}
+	if page.Limit == 0 {
+		page.Limit = -1
+	}
+	start, end := paginate(len(students), page)
+	emails := make([]string, 0, end-start)
+	for _, student := range students[start:end] {
+		emails = append(emails, student.Email)
+	}
+
+	return emails, int64(len(students)), nil
+}
+
+//Get all registered students of a teacher who can receive notifications. Graduated students and archived registrations
+//are left out unless includeGraduated is set.
+func (ts *teacherStudentRepo) GetAllStudentsByTeacher(ctx context.Context, teacher string, includeGraduated bool) ([]models.Student, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ts.store.mu.RLock()
+	defer ts.store.mu.RUnlock()
+
+	details, ok := ts.store.teacherByEmail(teacher)
+	if !ok {
+		return nil, nil
+	}
+
+	var students []models.Student
+	for _, registration := range ts.registrations(includeGraduated) {
+		if registration.TeacherID != details.ID {
+			continue
+		}
+		student, ok := ts.store.students[registration.StudentID]
+		if !ok || student.Status == models.StatusSuspended {
+			continue
+		}
+		if !includeGraduated && student.Status == models.StatusGraduated {
+			continue
+		}
+		students = append(students, cloneStudent(student))
+	}
+	sort.Slice(students, func(i, j int) bool {
+		return students[i].ID < students[j].ID
+	})
+
+	return students, nil
+}
+
+//registrations returns the registrations that are not deregistered, with those archived by graduation only when
+//graduated students are included. The caller holds the lock.
+func (ts *teacherStudentRepo) registrations(includeGraduated bool) []models.TeacherStudent {
+	if includeGraduated {
+		return ts.store.activeRegistrations()
+	}
+	return ts.store.currentRegistrations()
+}
+
+//teacherIDs maps emails to the ids of the existing teachers. The caller holds the lock.
+func (ts *teacherStudentRepo) teacherIDs(emails []string) map[uint]bool {
+	ids := make(map[uint]bool, len(emails))
+	for _, email := range emails {
+		if teacher, ok := ts.store.teacherByEmail(email); ok {
+			ids[teacher.ID] = true
+		}
+	}
+	return ids
+}
+
+//Get every registration of a student, including archived and deregistered ones, oldest first
+func (ts *teacherStudentRepo) GetEnrolmentHistory(ctx context.Context, studentID uint) ([]models.Enrolment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ts.store.mu.RLock()
+	defer ts.store.mu.RUnlock()
+
+	registrations := make([]models.TeacherStudent, 0)
+	for _, registration := range ts.store.teacherStudents {
+		if registration.StudentID == studentID {
+			registrations = append(registrations, cloneTeacherStudent(registration))
+		}
+	}
+	sort.Slice(registrations, func(i, j int) bool {
+		if !registrations[i].CreatedAt.Equal(registrations[j].CreatedAt) {
+			return registrations[i].CreatedAt.Before(registrations[j].CreatedAt)
+		}
+		return registrations[i].ID < registrations[j].ID
+	})
+
+	enrolments := make([]models.Enrolment, 0, len(registrations))
+	for _, registration := range registrations {
+		enrolment := models.Enrolment{
+			TeacherEmail: ts.store.teachers[registration.TeacherID].Email,
+			RegisteredAt: registration.CreatedAt,
+			ArchivedAt:   registration.ArchivedAt,
+		}
+		if registration.DeletedAt.Valid {
+			deregisteredAt := registration.DeletedAt.Time
+			enrolment.DeregisteredAt = &deregisteredAt
+		}
+		enrolments = append(enrolments, enrolment)
+	}
+	return enrolments, nil
+}
@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"class-management/internal/utils"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+//Middleware counts requests and observes their latency, labelled with the route template
+//rather than the path so emails and ids in the path don't create a series each
+func Middleware(m *Metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			start := time.Now()
+			recorder := utils.NewStatusRecorder(writer)
+			next.ServeHTTP(recorder, request)
+
+			route := utils.RouteTemplate(request)
+			status := strconv.Itoa(recorder.Status)
+			m.httpRequests.WithLabelValues(route, request.Method, status).Inc()
+			m.httpRequestDuration.WithLabelValues(route, request.Method, status).Observe(time.Since(start).Seconds())
+		})
+	}
+}
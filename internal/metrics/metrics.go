@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//Buckets in seconds shared by the latency histograms, from 1ms to 10s
+var latencyBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+//Metrics holds the collectors of the HTTP, service and database layers and the business counters
+type Metrics struct {
+	httpRequests        *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+	serviceDuration     *prometheus.HistogramVec
+	queryDuration       *prometheus.HistogramVec
+
+	studentsRegistered     *prometheus.CounterVec
+	studentSuspensions     prometheus.Counter
+	notificationRecipients prometheus.Counter
+}
+
+//New creates the collectors and registers them with registerer
+func New(registerer prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by route template, method and status code.",
+		}, []string{"route", "method", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by route template, method and status code.",
+			Buckets: latencyBuckets,
+		}, []string{"route", "method", "status"}),
+		serviceDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "teacher_service_duration_seconds",
+			Help:    "TeacherService call latency by method and outcome.",
+			Buckets: latencyBuckets,
+		}, []string{"method", "outcome"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "GORM query latency by operation, table and outcome.",
+			Buckets: latencyBuckets,
+		}, []string{"operation", "table", "outcome"}),
+		studentsRegistered: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "students_registered_total",
+			Help: "Students registered to a teacher, by whether the student was created or an existing one was linked.",
+		}, []string{"status"}),
+		studentSuspensions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "student_suspensions_total",
+			Help: "Students suspended.",
+		}),
+		notificationRecipients: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "notification_recipients_resolved_total",
+			Help: "Recipients resolved for notifications.",
+		}),
+	}
+
+	registerer.MustRegister(
+		m.httpRequests,
+		m.httpRequestDuration,
+		m.serviceDuration,
+		m.queryDuration,
+		m.studentsRegistered,
+		m.studentSuspensions,
+		m.notificationRecipients,
+	)
+	return m
+}
+
+//Outcome label of a call
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"class-management/internal/dto"
+	"class-management/internal/models"
+	"class-management/internal/service/teacher"
+	"context"
+	"time"
+)
+
+//teacherService times every call to the wrapped service and counts the business events of the successful ones
+type teacherService struct {
+	next    teacher.TeacherService
+	metrics *Metrics
+}
+
+//NewTeacherService wraps a TeacherService with metrics
+func NewTeacherService(next teacher.TeacherService, m *Metrics) teacher.TeacherService {
+	return &teacherService{
+		next:    next,
+		metrics: m,
+	}
+}
+
+//Observe the latency of a call from start
+func (s *teacherService) observe(method string, start time.Time, err error) {
+	s.metrics.serviceDuration.WithLabelValues(method, outcome(err)).Observe(time.Since(start).Seconds())
+}
+
+//Count the students created or linked, registrations can partly succeed so they are counted even on error
+func (s *teacherService) countRegistrations(response dto.RegistrationResponse) {
+	for _, result := range response.Results {
+		if result.Status == dto.RegistrationCreated || result.Status == dto.RegistrationLinked {
+			s.metrics.studentsRegistered.WithLabelValues(string(result.Status)).Inc()
+		}
+	}
+}
+
+func (s *teacherService) RegisterStudents(ctx context.Context, req dto.RegisterStudentsRequest) (dto.RegistrationResponse, error) {
+	start := time.Now()
+	response, err := s.next.RegisterStudents(ctx, req)
+	s.observe("RegisterStudents", start, err)
+	s.countRegistrations(response)
+	return response, err
+}
+
+func (s *teacherService) DeregisterStudents(ctx context.Context, req dto.DeregisterStudentsRequest) error {
+	start := time.Now()
+	err := s.next.DeregisterStudents(ctx, req)
+	s.observe("DeregisterStudents", start, err)
+	return err
+}
+
+func (s *teacherService) SuspendStudent(ctx context.Context, req dto.SuspendRequest) error {
+	start := time.Now()
+	err := s.next.SuspendStudent(ctx, req)
+	s.observe("SuspendStudent", start, err)
+	if err == nil {
+		s.metrics.studentSuspensions.Inc()
+	}
+	return err
+}
+
+func (s *teacherService) UnsuspendStudent(ctx context.Context, req dto.UnsuspendRequest) error {
+	start := time.Now()
+	err := s.next.UnsuspendStudent(ctx, req)
+	s.observe("UnsuspendStudent", start, err)
+	return err
+}
+
+func (s *teacherService) ChangeStudentStatus(ctx context.Context, req dto.ChangeStudentStatusRequest) error {
+	start := time.Now()
+	err := s.next.ChangeStudentStatus(ctx, req)
+	s.observe("ChangeStudentStatus", start, err)
+	if err == nil && models.StatusStudent(req.Status) == models.StatusSuspended {
+		s.metrics.studentSuspensions.Inc()
+	}
+	return err
+}
+
+func (s *teacherService) GraduateStudents(ctx context.Context, req dto.GraduateStudentsRequest) (dto.GraduateStudentsResponse, error) {
+	start := time.Now()
+	response, err := s.next.GraduateStudents(ctx, req)
+	s.observe("GraduateStudents", start, err)
+	return response, err
+}
+
+func (s *teacherService) CommonStudentsOfTeachers(ctx context.Context, req dto.CommonStudentsRequest) (dto.CommonStudentsResponse, error) {
+	start := time.Now()
+	response, err := s.next.CommonStudentsOfTeachers(ctx, req)
+	s.observe("CommonStudentsOfTeachers", start, err)
+	return response, err
+}
+
+func (s *teacherService) FetchStudentsForNotification(ctx context.Context, req dto.FetchStudentsForNotificationRequest) (dto.FetchStudentsForNotificationResponse, error) {
+	start := time.Now()
+	response, err := s.next.FetchStudentsForNotification(ctx, req)
+	s.observe("FetchStudentsForNotification", start, err)
+	if err == nil {
+		s.metrics.notificationRecipients.Add(float64(len(response.Recipients)))
+	}
+	return response, err
+}
+
+func (s *teacherService) RegisterTeachers(ctx context.Context, req dto.RegisterTeachersRequest) (dto.RegistrationResponse, error) {
+	start := time.Now()
+	response, err := s.next.RegisterTeachers(ctx, req)
+	s.observe("RegisterTeachers", start, err)
+	return response, err
+}
@@ -0,0 +1,397 @@
+package migrate
+
+import (
+	"class-management/internal/database"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Table recording the applied versions
+const migrationsTable = "schema_migrations"
+
+//Name of the MySQL named lock and PostgreSQL advisory lock held while migrating
+const lockName = "class_management_schema_migrations"
+
+var ErrLocked = errors.New("migrate: another migration run holds the lock")
+
+//Options tune the migrator. Zero values fall back to the defaults.
+type Options struct {
+	//Database the migrations run against. Defaults to MySQL.
+	Driver database.Driver
+
+	//Print the SQL of the pending migrations to Out instead of executing it.
+	//Nothing is written to the database and no lock is taken.
+	DryRun bool
+
+	//Progress and dry-run output. Defaults to io.Discard.
+	Out io.Writer
+
+	//How long to wait for a concurrent run to release the lock. Defaults to 1 minute.
+	LockTimeout time.Duration
+}
+
+//Status of one known migration
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+//Migrator applies and reverts migrations, recording the applied versions in schema_migrations.
+//On PostgreSQL every migration runs in its own transaction and on SQLite the whole run is one transaction.
+//MySQL commits DDL implicitly, so there a migration failing halfway is not rolled back and is not recorded as applied.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	options    Options
+}
+
+func NewMigrator(db *sql.DB, migrations []Migration, options Options) *Migrator {
+	if options.Driver == "" {
+		options.Driver = database.MySQL
+	}
+	if options.Out == nil {
+		options.Out = io.Discard
+	}
+	if options.LockTimeout <= 0 {
+		options.LockTimeout = time.Minute
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		options:    options,
+	}
+}
+
+//Up applies every pending migration in version order and returns the ones applied
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.withConn(ctx, func(conn *sql.Conn) error {
+		applied, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			err := m.apply(ctx, conn, migration, "up", migration.Up, func() error {
+				_, err := conn.ExecContext(ctx, m.bind("INSERT INTO "+migrationsTable+" (version, name) VALUES (?, ?)"), migration.Version, migration.Name)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+//Down reverts the last steps applied migrations, newest first, and returns the ones reverted
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("migrate: invalid steps %d", steps)
+	}
+
+	byVersion := make(map[uint64]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	var done []Migration
+	err := m.withConn(ctx, func(conn *sql.Conn) error {
+		applied, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		versions := make([]uint64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i] > versions[j]
+		})
+		if steps < len(versions) {
+			versions = versions[:steps]
+		}
+
+		for _, version := range versions {
+			migration, ok := byVersion[version]
+			if !ok {
+				return fmt.Errorf("migrate: applied version %d has no migration file", version)
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migrate: migration %s has no down file", migration.Label())
+			}
+			err := m.apply(ctx, conn, migration, "down", migration.Down, func() error {
+				_, err := conn.ExecContext(ctx, m.bind("DELETE FROM "+migrationsTable+" WHERE version = ?"), migration.Version)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+//Status lists every known migration and whether it is applied. It never writes to the database.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	applied, err := m.appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Migration: migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+//Pending lists the known migrations that aren't applied yet. Like Status it never writes to the database.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, status := range statuses {
+		if !status.Applied {
+			pending = append(pending, status.Migration)
+		}
+	}
+	return pending, nil
+}
+
+//withConn runs fn on a single connection. Outside dry-run the connection holds the migration lock
+//and schema_migrations is created first.
+func (m *Migrator) withConn(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if m.options.DryRun {
+		return fn(conn)
+	}
+
+	if err := m.lock(ctx, conn); err != nil {
+		return err
+	}
+
+	_, err = conn.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+migrationsTable+" ("+
+		"version BIGINT NOT NULL PRIMARY KEY, "+
+		"name VARCHAR(255) NOT NULL, "+
+		"applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)")
+	if err != nil {
+		err = fmt.Errorf("migrate: create %s: %w", migrationsTable, err)
+	} else {
+		err = fn(conn)
+	}
+
+	return m.unlock(conn, err)
+}
+
+//lock keeps concurrent runs out. MySQL and PostgreSQL use a named lock held by the connection,
+//SQLite an immediate transaction that blocks every other writer until the run ends.
+func (m *Migrator) lock(ctx context.Context, conn *sql.Conn) error {
+	switch m.options.Driver {
+	case database.Postgres:
+		deadline := time.Now().Add(m.options.LockTimeout)
+		for {
+			var locked bool
+			if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", lockName).Scan(&locked); err != nil {
+				return fmt.Errorf("migrate: acquire lock: %w", err)
+			}
+			if locked {
+				return nil
+			}
+			if time.Now().After(deadline) {
+				return ErrLocked
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+
+	case database.SQLite:
+		//waits up to the busy timeout of the connection
+		if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+			return fmt.Errorf("%w: %v", ErrLocked, err)
+		}
+		return nil
+
+	default:
+		var locked sql.NullInt64
+		timeout := int(m.options.LockTimeout / time.Second)
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, timeout).Scan(&locked); err != nil {
+			return fmt.Errorf("migrate: acquire lock: %w", err)
+		}
+		if !locked.Valid || locked.Int64 != 1 {
+			return ErrLocked
+		}
+		return nil
+	}
+}
+
+//unlock releases the lock taken by lock and returns the outcome of the run. On SQLite it commits the run,
+//or rolls it back if runErr is set.
+func (m *Migrator) unlock(conn *sql.Conn, runErr error) error {
+	ctx := context.Background()
+	switch m.options.Driver {
+	case database.Postgres:
+		//released with the connection anyway, so a failure here is not fatal
+		conn.ExecContext(ctx, "SELECT pg_advisory_unlock(hashtext($1))", lockName)
+	case database.SQLite:
+		if runErr != nil {
+			conn.ExecContext(ctx, "ROLLBACK")
+			return runErr
+		}
+		if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+			return fmt.Errorf("migrate: commit: %w", err)
+		}
+	default:
+		var released sql.NullInt64
+		conn.QueryRowContext(ctx, "SELECT RELEASE_LOCK(?)", lockName).Scan(&released)
+	}
+
+	return runErr
+}
+
+//bind rewrites ? placeholders to the $n form PostgreSQL expects
+func (m *Migrator) bind(query string) string {
+	if m.options.Driver != database.Postgres {
+		return query
+	}
+
+	var bound strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			bound.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		bound.WriteRune(c)
+	}
+	return bound.String()
+}
+
+//appliedVersions maps the applied versions to when they were applied. A missing table means none are applied.
+func (m *Migrator) appliedVersions(ctx context.Context, conn *sql.Conn) (map[uint64]time.Time, error) {
+	query := "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?"
+	switch m.options.Driver {
+	case database.Postgres:
+		query = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = ?"
+	case database.SQLite:
+		query = "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?"
+	}
+
+	var tables int
+	if err := conn.QueryRowContext(ctx, m.bind(query), migrationsTable).Scan(&tables); err != nil {
+		return nil, fmt.Errorf("migrate: read %s: %w", migrationsTable, err)
+	}
+
+	applied := make(map[uint64]time.Time)
+	if tables == 0 {
+		return applied, nil
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM "+migrationsTable)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: read %s: %w", migrationsTable, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version uint64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("migrate: read %s: %w", migrationsTable, err)
+		}
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+//apply runs the statements of one migration direction followed by record, or prints them in dry-run.
+//On PostgreSQL both happen in one transaction.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration, direction string, body string, record func() error) error {
+	statements := SplitStatements(body)
+
+	if m.options.DryRun {
+		fmt.Fprintf(m.options.Out, "-- %s (%s)\n", migration.Label(), direction)
+		for _, statement := range statements {
+			fmt.Fprintf(m.options.Out, "%s;\n", statement)
+		}
+		fmt.Fprintln(m.options.Out)
+		return nil
+	}
+
+	transactional := m.options.Driver == database.Postgres
+	if transactional {
+		if _, err := conn.ExecContext(ctx, "BEGIN"); err != nil {
+			return fmt.Errorf("migrate: %s (%s): %w", migration.Label(), direction, err)
+		}
+	}
+
+	err := func() error {
+		for _, statement := range statements {
+			if _, err := conn.ExecContext(ctx, statement); err != nil {
+				return err
+			}
+		}
+		return record()
+	}()
+	if err != nil {
+		if transactional {
+			conn.ExecContext(context.Background(), "ROLLBACK")
+		}
+		return fmt.Errorf("migrate: %s (%s): %w", migration.Label(), direction, err)
+	}
+
+	if transactional {
+		if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+			return fmt.Errorf("migrate: %s (%s): %w", migration.Label(), direction, err)
+		}
+	}
+	fmt.Fprintf(m.options.Out, "migrated %s (%s)\n", migration.Label(), direction)
+
+	return nil
+}
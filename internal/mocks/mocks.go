@@ -2,18 +2,24 @@ package mocks
 
 import (
 	"class-management/internal/models"
+	"class-management/internal/notifier"
+	"context"
 	"errors"
+	"time"
 )
 
 // MockTeacherRepo is a mock implementation of the TeacherRepo interface
 type MockTeacherRepo struct {
-	TeacherByEmailFn func(email string) (*models.Teacher, error)
-	CreateTeacherFn  func(teacher *models.Teacher) (*models.Teacher, error)
+	TeacherByEmailFn       func(ctx context.Context, email string) (*models.Teacher, error)
+	CreateTeacherFn        func(ctx context.Context, teacher *models.Teacher) (*models.Teacher, error)
+	ListTeachersFn         func(ctx context.Context, page models.Page) ([]models.Teacher, int64, error)
+	GetTeachersByStudentFn func(ctx context.Context, studentID uint) ([]models.Teacher, error)
+	UpdateTeacherProfileFn func(ctx context.Context, teacher *models.Teacher) error
 }
 
-func (m *MockTeacherRepo) GetTeacherByEmail(email string) (*models.Teacher, error) {
+func (m *MockTeacherRepo) GetTeacherByEmail(ctx context.Context, email string) (*models.Teacher, error) {
 	if m.TeacherByEmailFn != nil {
-		return m.TeacherByEmailFn(email)
+		return m.TeacherByEmailFn(ctx, email)
 	}
 
 	// Default behavior: Return a mock teacher with the provided email
@@ -27,7 +33,7 @@ func (m *MockTeacherRepo) GetTeacherByEmail(email string) (*models.Teacher, erro
 	return mockTeacher, nil
 }
 
-func (m *MockTeacherRepo) CreateTeacher(teacher *models.Teacher) (*models.Teacher, error) {
+func (m *MockTeacherRepo) CreateTeacher(ctx context.Context, teacher *models.Teacher) (*models.Teacher, error) {
 	createdTeacher := &models.Teacher{
 		ID:    1,
 		Email: teacher.Email,
@@ -37,25 +43,58 @@ func (m *MockTeacherRepo) CreateTeacher(teacher *models.Teacher) (*models.Teache
 	return createdTeacher, nil
 }
 
+func (m *MockTeacherRepo) ListTeachers(ctx context.Context, page models.Page) ([]models.Teacher, int64, error) {
+	if m.ListTeachersFn != nil {
+		return m.ListTeachersFn(ctx, page)
+	}
+
+	// Default behavior: Return an empty slice of teachers
+	return []models.Teacher{}, 0, nil
+}
+
+func (m *MockTeacherRepo) GetTeachersByStudent(ctx context.Context, studentID uint) ([]models.Teacher, error) {
+	if m.GetTeachersByStudentFn != nil {
+		return m.GetTeachersByStudentFn(ctx, studentID)
+	}
+
+	// Default behavior: Return an empty slice of teachers
+	return []models.Teacher{}, nil
+}
+
+func (m *MockTeacherRepo) UpdateTeacherProfile(ctx context.Context, teacher *models.Teacher) error {
+	if m.UpdateTeacherProfileFn != nil {
+		return m.UpdateTeacherProfileFn(ctx, teacher)
+	}
+
+	// Default behavior: Return nil error
+	return nil
+}
+
 // MockStudentRepo is a mock implementation of the StudentRepo interface
 type MockStudentRepo struct {
-	CreateStudentFn       func(student *models.Student) (*models.Student, error)
-	GetStudentByEmailFn   func(email string) (*models.Student, error)
-	UpdateStudentStatusFn func(student *models.Student) error
+	CreateStudentFn           func(ctx context.Context, student *models.Student) (*models.Student, error)
+	GetStudentByEmailFn       func(ctx context.Context, email string) (*models.Student, error)
+	UpdateStudentStatusFn     func(ctx context.Context, student *models.Student, change *models.StudentStatusChange) error
+	GraduateStudentFn         func(ctx context.Context, student *models.Student, change *models.StudentStatusChange) error
+	ListStudentsFn            func(ctx context.Context, statuses []models.StatusStudent, page models.Page) ([]models.Student, int64, error)
+	ListStudentsByTeacherFn   func(ctx context.Context, teacherID uint, page models.Page) ([]models.Student, int64, error)
+	GetStudentsByGradeLevelFn func(ctx context.Context, gradeLevel string, includeGraduated bool) ([]models.Student, error)
+	GetStudentByNumberFn      func(ctx context.Context, studentNumber string) (*models.Student, error)
+	UpdateStudentProfileFn    func(ctx context.Context, student *models.Student) error
 }
 
-func (m *MockStudentRepo) CreateStudent(student *models.Student) (*models.Student, error) {
+func (m *MockStudentRepo) CreateStudent(ctx context.Context, student *models.Student) (*models.Student, error) {
 	if m.CreateStudentFn != nil {
-		return m.CreateStudentFn(student)
+		return m.CreateStudentFn(ctx, student)
 	}
 
 	// Default behavior: Return the provided student as is
 	return student, nil
 }
 
-func (m *MockStudentRepo) GetStudentByEmail(email string) (*models.Student, error) {
+func (m *MockStudentRepo) GetStudentByEmail(ctx context.Context, email string) (*models.Student, error) {
 	if m.GetStudentByEmailFn != nil {
-		return m.GetStudentByEmailFn(email)
+		return m.GetStudentByEmailFn(ctx, email)
 	}
 
 	// Default behavior: Return a mock student with the provided email
@@ -69,35 +108,100 @@ func (m *MockStudentRepo) GetStudentByEmail(email string) (*models.Student, erro
 	return mockStudent, nil
 }
 
-func (m *MockStudentRepo) UpdateStudentStatus(student *models.Student) error {
+func (m *MockStudentRepo) UpdateStudentStatus(ctx context.Context, student *models.Student, change *models.StudentStatusChange) error {
 	if m.UpdateStudentStatusFn != nil {
-		return m.UpdateStudentStatusFn(student)
+		return m.UpdateStudentStatusFn(ctx, student, change)
+	}
+
+	// Default behavior: Return nil error
+	return nil
+}
+
+func (m *MockStudentRepo) GraduateStudent(ctx context.Context, student *models.Student, change *models.StudentStatusChange) error {
+	if m.GraduateStudentFn != nil {
+		return m.GraduateStudentFn(ctx, student, change)
 	}
 
 	// Default behavior: Return nil error
 	return nil
 }
 
+func (m *MockStudentRepo) ListStudents(ctx context.Context, statuses []models.StatusStudent, page models.Page) ([]models.Student, int64, error) {
+	if m.ListStudentsFn != nil {
+		return m.ListStudentsFn(ctx, statuses, page)
+	}
+
+	// Default behavior: Return an empty slice of students
+	return []models.Student{}, 0, nil
+}
+
+func (m *MockStudentRepo) ListStudentsByTeacher(ctx context.Context, teacherID uint, page models.Page) ([]models.Student, int64, error) {
+	if m.ListStudentsByTeacherFn != nil {
+		return m.ListStudentsByTeacherFn(ctx, teacherID, page)
+	}
+
+	// Default behavior: Return an empty slice of students
+	return []models.Student{}, 0, nil
+}
+
+func (m *MockStudentRepo) GetStudentByNumber(ctx context.Context, studentNumber string) (*models.Student, error) {
+	if m.GetStudentByNumberFn != nil {
+		return m.GetStudentByNumberFn(ctx, studentNumber)
+	}
+
+	// Default behavior: No student has the provided number
+	return nil, nil
+}
+
+func (m *MockStudentRepo) UpdateStudentProfile(ctx context.Context, student *models.Student) error {
+	if m.UpdateStudentProfileFn != nil {
+		return m.UpdateStudentProfileFn(ctx, student)
+	}
+
+	// Default behavior: Return nil error
+	return nil
+}
+
+func (m *MockStudentRepo) GetStudentsByGradeLevel(ctx context.Context, gradeLevel string, includeGraduated bool) ([]models.Student, error) {
+	if m.GetStudentsByGradeLevelFn != nil {
+		return m.GetStudentsByGradeLevelFn(ctx, gradeLevel, includeGraduated)
+	}
+
+	// Default behavior: Return an empty slice of students
+	return []models.Student{}, nil
+}
+
 // MockTeacherStudentsRepo is a mock implementation of the TeacherStudentsRepo interface
 type MockTeacherStudentsRepo struct {
-	CreateTeacherStudentFn          func(*models.TeacherStudent) error
-	IsStudentRegisteredForTeacherFn func(uint, uint) (*models.TeacherStudent, error)
-	GetAllStudentsByTeacherFn       func(string) ([]models.Student, error)
-	GetCommonStudentsFn             func([]string) ([]string, error)
+	CreateTeacherStudentFn          func(context.Context, *models.TeacherStudent) error
+	DeleteTeacherStudentFn          func(context.Context, uint, uint) error
+	IsStudentRegisteredForTeacherFn func(context.Context, uint, uint) (*models.TeacherStudent, error)
+	GetAllStudentsByTeacherFn       func(context.Context, string, bool) ([]models.Student, error)
+	GetCommonStudentsFn             func(context.Context, models.CommonStudentsFilter) ([]string, int64, error)
+	GetEnrolmentHistoryFn           func(context.Context, uint) ([]models.Enrolment, error)
 }
 
-func (m *MockTeacherStudentsRepo) CreateTeacherStudent(student *models.TeacherStudent) error {
+func (m *MockTeacherStudentsRepo) CreateTeacherStudent(ctx context.Context, student *models.TeacherStudent) error {
 	if m.CreateTeacherStudentFn != nil {
-		return m.CreateTeacherStudentFn(student)
+		return m.CreateTeacherStudentFn(ctx, student)
 	}
 
 	// Default behavior: Return an error
 	return errors.New("failed to create teacher student")
 }
 
-func (m *MockTeacherStudentsRepo) IsStudentRegisteredForTeacher(teacherID uint, studentID uint) (*models.TeacherStudent, error) {
+func (m *MockTeacherStudentsRepo) DeleteTeacherStudent(ctx context.Context, teacherID uint, studentID uint) error {
+	if m.DeleteTeacherStudentFn != nil {
+		return m.DeleteTeacherStudentFn(ctx, teacherID, studentID)
+	}
+
+	// Default behavior: Return nil error
+	return nil
+}
+
+func (m *MockTeacherStudentsRepo) IsStudentRegisteredForTeacher(ctx context.Context, teacherID uint, studentID uint) (*models.TeacherStudent, error) {
 	if m.IsStudentRegisteredForTeacherFn != nil {
-		return m.IsStudentRegisteredForTeacherFn(teacherID, studentID)
+		return m.IsStudentRegisteredForTeacherFn(ctx, teacherID, studentID)
 	}
 
 	// Default behavior: Return a mock TeacherStudent object or an error based on your test case scenario
@@ -112,20 +216,236 @@ func (m *MockTeacherStudentsRepo) IsStudentRegisteredForTeacher(teacherID uint,
 	return mockTeacherStudent, nil
 }
 
-func (m *MockTeacherStudentsRepo) GetAllStudentsByTeacher(teacherEmail string) ([]models.Student, error) {
+func (m *MockTeacherStudentsRepo) GetAllStudentsByTeacher(ctx context.Context, teacherEmail string, includeGraduated bool) ([]models.Student, error) {
 	if m.GetAllStudentsByTeacherFn != nil {
-		return m.GetAllStudentsByTeacherFn(teacherEmail)
+		return m.GetAllStudentsByTeacherFn(ctx, teacherEmail, includeGraduated)
 	}
 
 	// Default behavior: Return an empty slice of students
 	return []models.Student{}, nil
 }
 
-func (m *MockTeacherStudentsRepo) GetCommonStudents(teacherEmails []string) ([]string, error) {
+func (m *MockTeacherStudentsRepo) GetCommonStudents(ctx context.Context, filter models.CommonStudentsFilter) ([]string, int64, error) {
 	if m.GetCommonStudentsFn != nil {
-		return m.GetCommonStudentsFn(teacherEmails)
+		return m.GetCommonStudentsFn(ctx, filter)
 	}
 
 	// Default behavior: Return an empty slice of common students
-	return []string{}, nil
+	return []string{}, 0, nil
+}
+
+func (m *MockTeacherStudentsRepo) GetEnrolmentHistory(ctx context.Context, studentID uint) ([]models.Enrolment, error) {
+	if m.GetEnrolmentHistoryFn != nil {
+		return m.GetEnrolmentHistoryFn(ctx, studentID)
+	}
+
+	// Default behavior: Return an empty enrolment history
+	return []models.Enrolment{}, nil
+}
+
+// MockUnitOfWork is a mock implementation of the UnitOfWork interface
+type MockUnitOfWork struct {
+	Repos models.Repositories
+	DoFn  func(ctx context.Context, fn func(models.Repositories) error) error
+}
+
+// NewMockUnitOfWork returns a unit of work that hands the given mock repositories to every transaction
+func NewMockUnitOfWork(teacherRepo models.TeacherRepo, studentRepo models.StudentRepo, teacherStudentRepo models.TeacherStudentRepo, classRepo models.ClassRepo, notificationRepo models.NotificationRepo) *MockUnitOfWork {
+	return &MockUnitOfWork{
+		Repos: models.Repositories{
+			Teachers:        teacherRepo,
+			Students:        studentRepo,
+			TeacherStudents: teacherStudentRepo,
+			Classes:         classRepo,
+			Notifications:   notificationRepo,
+			Deliveries:      &MockDeliveryRepo{},
+		},
+	}
+}
+
+func (m *MockUnitOfWork) Do(ctx context.Context, fn func(models.Repositories) error) error {
+	if m.DoFn != nil {
+		return m.DoFn(ctx, fn)
+	}
+
+	// Default behavior: Run the callback with the mock repositories
+	return fn(m.Repos)
+}
+
+// MockClassRepo is a mock implementation of the ClassRepo interface
+type MockClassRepo struct {
+	CreateClassFn              func(ctx context.Context, class *models.Class) (*models.Class, error)
+	GetClassByIDFn             func(ctx context.Context, id uint) (*models.Class, error)
+	GetClassByNameFn           func(ctx context.Context, name string) (*models.Class, error)
+	AssignTeacherFn            func(ctx context.Context, classTeacher *models.ClassTeacher) error
+	IsTeacherAssignedToClassFn func(ctx context.Context, classID uint, teacherID uint) (*models.ClassTeacher, error)
+	EnrolStudentFn             func(ctx context.Context, classStudent *models.ClassStudent) error
+	IsStudentEnrolledInClassFn func(ctx context.Context, classID uint, studentID uint) (*models.ClassStudent, error)
+	GetClassTeachersFn         func(ctx context.Context, classID uint) ([]models.Teacher, error)
+	GetClassStudentsFn         func(ctx context.Context, classID uint, includeGraduated bool) ([]models.Student, error)
+}
+
+func (m *MockClassRepo) CreateClass(ctx context.Context, class *models.Class) (*models.Class, error) {
+	if m.CreateClassFn != nil {
+		return m.CreateClassFn(ctx, class)
+	}
+
+	// Default behavior: Return the provided class with an id
+	class.ID = 1
+	return class, nil
+}
+
+func (m *MockClassRepo) GetClassByID(ctx context.Context, id uint) (*models.Class, error) {
+	if m.GetClassByIDFn != nil {
+		return m.GetClassByIDFn(ctx, id)
+	}
+
+	// Default behavior: Return a mock class with the provided id
+	return &models.Class{ID: id, Name: "Math 3B"}, nil
+}
+
+func (m *MockClassRepo) GetClassByName(ctx context.Context, name string) (*models.Class, error) {
+	if m.GetClassByNameFn != nil {
+		return m.GetClassByNameFn(ctx, name)
+	}
+
+	// Default behavior: No class exists with the provided name
+	return nil, nil
+}
+
+func (m *MockClassRepo) AssignTeacher(ctx context.Context, classTeacher *models.ClassTeacher) error {
+	if m.AssignTeacherFn != nil {
+		return m.AssignTeacherFn(ctx, classTeacher)
+	}
+
+	// Default behavior: Return nil error
+	return nil
+}
+
+func (m *MockClassRepo) IsTeacherAssignedToClass(ctx context.Context, classID uint, teacherID uint) (*models.ClassTeacher, error) {
+	if m.IsTeacherAssignedToClassFn != nil {
+		return m.IsTeacherAssignedToClassFn(ctx, classID, teacherID)
+	}
+
+	// Default behavior: The teacher is assigned to the class
+	return &models.ClassTeacher{ID: 1, ClassID: classID, TeacherID: teacherID}, nil
+}
+
+func (m *MockClassRepo) EnrolStudent(ctx context.Context, classStudent *models.ClassStudent) error {
+	if m.EnrolStudentFn != nil {
+		return m.EnrolStudentFn(ctx, classStudent)
+	}
+
+	// Default behavior: Return nil error
+	return nil
+}
+
+func (m *MockClassRepo) IsStudentEnrolledInClass(ctx context.Context, classID uint, studentID uint) (*models.ClassStudent, error) {
+	if m.IsStudentEnrolledInClassFn != nil {
+		return m.IsStudentEnrolledInClassFn(ctx, classID, studentID)
+	}
+
+	// Default behavior: The student is not enrolled yet
+	return nil, nil
+}
+
+func (m *MockClassRepo) GetClassTeachers(ctx context.Context, classID uint) ([]models.Teacher, error) {
+	if m.GetClassTeachersFn != nil {
+		return m.GetClassTeachersFn(ctx, classID)
+	}
+
+	// Default behavior: Return an empty slice of teachers
+	return []models.Teacher{}, nil
+}
+
+func (m *MockClassRepo) GetClassStudents(ctx context.Context, classID uint, includeGraduated bool) ([]models.Student, error) {
+	if m.GetClassStudentsFn != nil {
+		return m.GetClassStudentsFn(ctx, classID, includeGraduated)
+	}
+
+	// Default behavior: Return an empty slice of students
+	return []models.Student{}, nil
+}
+
+// MockNotificationRepo is a mock implementation of the NotificationRepo interface
+type MockNotificationRepo struct {
+	CreateNotificationFn        func(ctx context.Context, notification *models.Notification) error
+	GetNotificationsByTeacherFn func(ctx context.Context, teacherID uint, page models.Page) ([]models.NotificationSummary, int64, error)
+	GetNotificationByIDFn       func(ctx context.Context, id uint) (*models.Notification, error)
+}
+
+func (m *MockNotificationRepo) CreateNotification(ctx context.Context, notification *models.Notification) error {
+	if m.CreateNotificationFn != nil {
+		return m.CreateNotificationFn(ctx, notification)
+	}
+
+	// Default behavior: Assign an id and return nil error
+	notification.ID = 1
+	return nil
+}
+
+func (m *MockNotificationRepo) GetNotificationsByTeacher(ctx context.Context, teacherID uint, page models.Page) ([]models.NotificationSummary, int64, error) {
+	if m.GetNotificationsByTeacherFn != nil {
+		return m.GetNotificationsByTeacherFn(ctx, teacherID, page)
+	}
+
+	// Default behavior: Return an empty slice of notifications
+	return []models.NotificationSummary{}, 0, nil
+}
+
+func (m *MockNotificationRepo) GetNotificationByID(ctx context.Context, id uint) (*models.Notification, error) {
+	if m.GetNotificationByIDFn != nil {
+		return m.GetNotificationByIDFn(ctx, id)
+	}
+
+	// Default behavior: No notification exists with the provided id
+	return nil, nil
+}
+
+// MockDeliveryRepo is a mock implementation of the DeliveryRepo interface
+type MockDeliveryRepo struct {
+	CreateDeliveriesFn   func(ctx context.Context, deliveries []models.NotificationDelivery) error
+	ClaimDueDeliveriesFn func(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]models.NotificationDelivery, error)
+	UpdateDeliveryFn     func(ctx context.Context, delivery *models.NotificationDelivery) error
+}
+
+func (m *MockDeliveryRepo) CreateDeliveries(ctx context.Context, deliveries []models.NotificationDelivery) error {
+	if m.CreateDeliveriesFn != nil {
+		return m.CreateDeliveriesFn(ctx, deliveries)
+	}
+
+	// Default behavior: Return nil error
+	return nil
+}
+
+func (m *MockDeliveryRepo) ClaimDueDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]models.NotificationDelivery, error) {
+	if m.ClaimDueDeliveriesFn != nil {
+		return m.ClaimDueDeliveriesFn(ctx, now, limit, lease)
+	}
+
+	// Default behavior: Nothing is due
+	return []models.NotificationDelivery{}, nil
+}
+
+func (m *MockDeliveryRepo) UpdateDelivery(ctx context.Context, delivery *models.NotificationDelivery) error {
+	if m.UpdateDeliveryFn != nil {
+		return m.UpdateDeliveryFn(ctx, delivery)
+	}
+
+	// Default behavior: Return nil error
+	return nil
+}
+
+// MockNotifier is a mock implementation of the Notifier interface
+type MockNotifier struct {
+	SendFn func(ctx context.Context, message notifier.Message) error
+}
+
+func (m *MockNotifier) Send(ctx context.Context, message notifier.Message) error {
+	if m.SendFn != nil {
+		return m.SendFn(ctx, message)
+	}
+
+	// Default behavior: Every message is delivered
+	return nil
 }
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Class struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"unique;not null" json:"name"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAT time.Time `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime" json:"updated_at"`
+}
+
+func (Class) TableName() string {
+	return "classes"
+}
+
+type ClassTeacher struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ClassID   uint      `gorm:"not null" json:"class_id"`
+	TeacherID uint      `gorm:"not null" json:"teacher_id"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (ClassTeacher) TableName() string {
+	return "class_teachers"
+}
+
+type ClassStudent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ClassID   uint      `gorm:"not null" json:"class_id"`
+	StudentID uint      `gorm:"not null" json:"student_id"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (ClassStudent) TableName() string {
+	return "class_students"
+}
+
+type classRepo struct {
+	db *gorm.DB
+}
+
+func NewClassRepo(db *gorm.DB) ClassRepo {
+	return &classRepo{db}
+}
+
+type ClassRepo interface {
+	CreateClass(ctx context.Context, class *Class) (*Class, error)
+	GetClassByID(ctx context.Context, id uint) (*Class, error)
+	GetClassByName(ctx context.Context, name string) (*Class, error)
+	AssignTeacher(ctx context.Context, classTeacher *ClassTeacher) error
+	IsTeacherAssignedToClass(ctx context.Context, classID uint, teacherID uint) (*ClassTeacher, error)
+	EnrolStudent(ctx context.Context, classStudent *ClassStudent) error
+	IsStudentEnrolledInClass(ctx context.Context, classID uint, studentID uint) (*ClassStudent, error)
+	GetClassTeachers(ctx context.Context, classID uint) ([]Teacher, error)
+	GetClassStudents(ctx context.Context, classID uint, includeGraduated bool) ([]Student, error)
+}
+
+//Create a new class
+func (c *classRepo) CreateClass(ctx context.Context, class *Class) (*Class, error) {
+	err := c.db.WithContext(ctx).Create(class).Error
+	if err != nil {
+		return nil, err
+	}
+	return class, nil
+}
+
+//Get class detail by its id
+func (c *classRepo) GetClassByID(ctx context.Context, id uint) (*Class, error) {
+	var details Class
+	res := c.db.WithContext(ctx).Where("id = ?", id).First(&details)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else {
+			return nil, res.Error
+		}
+	}
+	return &details, nil
+}
+
+//Get class detail by its name
+func (c *classRepo) GetClassByName(ctx context.Context, name string) (*Class, error) {
+	var details Class
+	res := c.db.WithContext(ctx).Where("name = ?", name).First(&details)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else {
+			return nil, res.Error
+		}
+	}
+	return &details, nil
+}
+
+//Assign a teacher to a class
+func (c *classRepo) AssignTeacher(ctx context.Context, classTeacher *ClassTeacher) error {
+	return c.db.WithContext(ctx).Create(classTeacher).Error
+}
+
+//Check if given teacher is assigned to given class
+func (c *classRepo) IsTeacherAssignedToClass(ctx context.Context, classID uint, teacherID uint) (*ClassTeacher, error) {
+	var details ClassTeacher
+	res := c.db.WithContext(ctx).Where("class_id = ?", classID).Where("teacher_id = ?", teacherID).First(&details)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else {
+			return nil, res.Error
+		}
+	}
+	return &details, nil
+}
+
+//Enrol a student in a class
+func (c *classRepo) EnrolStudent(ctx context.Context, classStudent *ClassStudent) error {
+	return c.db.WithContext(ctx).Create(classStudent).Error
+}
+
+//Check if given student is enrolled in given class
+func (c *classRepo) IsStudentEnrolledInClass(ctx context.Context, classID uint, studentID uint) (*ClassStudent, error) {
+	var details ClassStudent
+	res := c.db.WithContext(ctx).Where("class_id = ?", classID).Where("student_id = ?", studentID).First(&details)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else {
+			return nil, res.Error
+		}
+	}
+	return &details, nil
+}
+
+//Get all teachers assigned to a class
+func (c *classRepo) GetClassTeachers(ctx context.Context, classID uint) ([]Teacher, error) {
+	var teachers []Teacher
+	err := c.db.WithContext(ctx).
+		Model(ClassTeacher{}).
+		Select("teachers.*").
+		Joins("JOIN teachers ON teachers.id = class_teachers.teacher_id").
+		Where("class_teachers.class_id = ?", classID).
+		Order("teachers.email").
+		Find(&teachers).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return teachers, nil
+}
+
+//Get all students enrolled in a class. Graduated students are left out unless includeGraduated is set.
+func (c *classRepo) GetClassStudents(ctx context.Context, classID uint, includeGraduated bool) ([]Student, error) {
+	var students []Student
+	query := c.db.WithContext(ctx).
+		Model(ClassStudent{}).
+		Select("students.*").
+		Joins("JOIN students ON students.id = class_students.student_id").
+		Where("class_students.class_id = ?", classID)
+	if !includeGraduated {
+		query = query.Not("students.status = ?", StatusGraduated)
+	}
+	err := query.Order("students.email").Find(&students).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return students, nil
+}
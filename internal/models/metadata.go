@@ -0,0 +1,35 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+//Free-form key/value data stored as a JSON column
+type Metadata map[string]interface{}
+
+func (m Metadata) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	value, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(value), nil
+}
+
+func (m *Metadata) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(data, m)
+	case string:
+		return json.Unmarshal([]byte(data), m)
+	default:
+		return fmt.Errorf("cannot scan %T into metadata", value)
+	}
+}
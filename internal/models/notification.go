@@ -0,0 +1,111 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Notification struct {
+	ID         uint                    `gorm:"primaryKey" json:"id"`
+	TeacherID  uint                    `gorm:"not null" json:"teacher_id"`
+	Teacher    Teacher                 `json:"teacher"`
+	Text       string                  `gorm:"not null" json:"text"`
+	Recipients []NotificationRecipient `json:"recipients"`
+	CreatedAt  time.Time               `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+//A student who received a notification, either because of registration with the teacher or an @mention
+type NotificationRecipient struct {
+	ID             uint                  `gorm:"primaryKey" json:"id"`
+	NotificationID uint                  `gorm:"not null" json:"notification_id"`
+	Email          string                `gorm:"not null" json:"email"`
+	Registered     bool                  `gorm:"not null" json:"registered"`
+	Mentioned      bool                  `gorm:"not null" json:"mentioned"`
+	Delivery       *NotificationDelivery `gorm:"foreignKey:RecipientID" json:"delivery,omitempty"`
+}
+
+func (NotificationRecipient) TableName() string {
+	return "notification_recipients"
+}
+
+//A notification with the number of its recipients
+type NotificationSummary struct {
+	ID             uint      `json:"id"`
+	Text           string    `json:"text"`
+	RecipientCount int       `json:"recipient_count"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type notificationRepo struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepo(db *gorm.DB) NotificationRepo {
+	return &notificationRepo{db}
+}
+
+type NotificationRepo interface {
+	CreateNotification(ctx context.Context, notification *Notification) error
+	GetNotificationsByTeacher(ctx context.Context, teacherID uint, page Page) ([]NotificationSummary, int64, error)
+	GetNotificationByID(ctx context.Context, id uint) (*Notification, error)
+}
+
+//Store a notification together with its recipients
+func (n *notificationRepo) CreateNotification(ctx context.Context, notification *Notification) error {
+	return n.db.WithContext(ctx).Omit("Teacher").Create(notification).Error
+}
+
+//Get a page of the notifications sent by a teacher, newest first, with the number of all of them
+func (n *notificationRepo) GetNotificationsByTeacher(ctx context.Context, teacherID uint, page Page) ([]NotificationSummary, int64, error) {
+	var total int64
+	err := n.db.WithContext(ctx).Model(Notification{}).Where("teacher_id = ?", teacherID).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []NotificationSummary
+	err = n.db.WithContext(ctx).
+		Model(Notification{}).
+		Select("notifications.id, notifications.text, notifications.created_at, COUNT(notification_recipients.id) AS recipient_count").
+		Joins("LEFT JOIN notification_recipients ON notification_recipients.notification_id = notifications.id").
+		Where("notifications.teacher_id = ?", teacherID).
+		Group("notifications.id, notifications.text, notifications.created_at").
+		Order("notifications.created_at DESC, notifications.id DESC").
+		Limit(page.Limit).
+		Offset(page.Offset).
+		Scan(&notifications).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
+
+//Get a notification with its teacher and recipients
+func (n *notificationRepo) GetNotificationByID(ctx context.Context, id uint) (*Notification, error) {
+	var details Notification
+	res := n.db.WithContext(ctx).
+		Preload("Teacher").
+		Preload("Recipients", func(db *gorm.DB) *gorm.DB {
+			return db.Order("notification_recipients.email")
+		}).
+		Preload("Recipients.Delivery").
+		Where("id = ?", id).
+		First(&details)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else {
+			return nil, res.Error
+		}
+	}
+	return &details, nil
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending DeliveryStatus = "PENDING"
+	DeliverySent    DeliveryStatus = "SENT"
+	DeliveryFailed  DeliveryStatus = "FAILED"
+)
+
+//Outbox entry for delivering a notification to one recipient. It is written in the same transaction as the notification
+//and picked up by the delivery worker.
+type NotificationDelivery struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	NotificationID uint           `gorm:"not null" json:"notification_id"`
+	Notification   Notification   `json:"notification"`
+	RecipientID    uint           `gorm:"not null" json:"recipient_id"`
+	Email          string         `gorm:"not null" json:"email"`
+	Status         DeliveryStatus `gorm:"type:ENUM('PENDING', 'SENT', 'FAILED');default:'PENDING'" json:"status"`
+	Attempts       int            `gorm:"not null" json:"attempts"`
+	LastError      string         `json:"last_error"`
+	NextAttemptAt  time.Time      `gorm:"not null" json:"next_attempt_at"`
+	DeliveredAt    *time.Time     `json:"delivered_at"`
+	//Set while a worker holds the delivery. Other workers skip it until LockedUntil has passed.
+	ClaimToken  string     `gorm:"not null" json:"-"`
+	LockedUntil *time.Time `json:"locked_until"`
+	CreatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (NotificationDelivery) TableName() string {
+	return "notification_deliveries"
+}
+
+type deliveryRepo struct {
+	db *gorm.DB
+}
+
+func NewDeliveryRepo(db *gorm.DB) DeliveryRepo {
+	return &deliveryRepo{db}
+}
+
+type DeliveryRepo interface {
+	CreateDeliveries(ctx context.Context, deliveries []NotificationDelivery) error
+	ClaimDueDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]NotificationDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *NotificationDelivery) error
+}
+
+//Queue deliveries for the recipients of a notification
+func (d *deliveryRepo) CreateDeliveries(ctx context.Context, deliveries []NotificationDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return d.db.WithContext(ctx).Omit("Notification").Create(&deliveries).Error
+}
+
+//Claim pending deliveries whose next attempt is due, oldest first, and get them with the notification and its teacher.
+//Claimed deliveries are locked until now+lease, so concurrent workers never get the same delivery. A delivery whose
+//worker stopped before saving the outcome can be claimed again once its lock has passed.
+func (d *deliveryRepo) ClaimDueDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]NotificationDelivery, error) {
+	token, err := newClaimToken()
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		query := tx.Model(&NotificationDelivery{}).
+			Where("status = ? AND next_attempt_at <= ?", DeliveryPending, now).
+			Where("(locked_until IS NULL OR locked_until <= ?)", now).
+			Order("next_attempt_at, id").
+			Limit(limit)
+		//MySQL and PostgreSQL skip the rows another worker is claiming. SQLite has no row locks
+		//and runs one write transaction at a time.
+		if tx.Dialector.Name() != "sqlite" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+		if err := query.Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		//the conditions are checked again so a delivery claimed in the meantime is left to its worker
+		return tx.Model(&NotificationDelivery{}).
+			Where("id IN ?", ids).
+			Where("status = ? AND (locked_until IS NULL OR locked_until <= ?)", DeliveryPending, now).
+			Updates(map[string]interface{}{"claim_token": token, "locked_until": now.Add(lease)}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries []NotificationDelivery
+	err = d.db.WithContext(ctx).
+		Preload("Notification.Teacher").
+		Where("claim_token = ?", token).
+		Order("next_attempt_at, id").
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+//Save the outcome of a delivery attempt and release the claim. Nothing is saved when the claim has passed
+//to another worker.
+func (d *deliveryRepo) UpdateDelivery(ctx context.Context, delivery *NotificationDelivery) error {
+	return d.db.WithContext(ctx).
+		Model(&NotificationDelivery{}).
+		Where("id = ? AND claim_token = ?", delivery.ID, delivery.ClaimToken).
+		Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"last_error":      delivery.LastError,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"delivered_at":    delivery.DeliveredAt,
+			"claim_token":     "",
+			"locked_until":    nil,
+		}).Error
+}
+
+//Random token identifying one claim of deliveries
+func newClaimToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
@@ -0,0 +1,7 @@
+package models
+
+//Page of a listing query
+type Page struct {
+	Limit  int
+	Offset int
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -8,11 +9,16 @@ import (
 )
 
 type Student struct {
-	ID        uint          `gorm:"primaryKey" json:"id"`
-	Email     string        `gorm:"unique;not null" json:"email"`
-	Status    StatusStudent `gorm:"default:ACTIVE;not null" json:"status"`
-	CreatedAt time.Time     `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAT time.Time     `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	ID            uint          `gorm:"primaryKey" json:"id"`
+	Email         string        `gorm:"unique;not null" json:"email"`
+	Status        StatusStudent `gorm:"default:ACTIVE;not null" json:"status"`
+	Name          string        `json:"name"`
+	PreferredName string        `json:"preferred_name"`
+	StudentNumber *string       `gorm:"unique" json:"student_number"`
+	GradeLevel    string        `json:"grade_level"`
+	Metadata      Metadata      `gorm:"type:json" json:"metadata"`
+	CreatedAt     time.Time     `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAT     time.Time     `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime" json:"updated_at"`
 }
 
 func (Student) TableName() string {
@@ -28,9 +34,15 @@ func NewStudentRepo(db *gorm.DB) StudentRepo {
 }
 
 type StudentRepo interface {
-	GetStudentByEmail(email string) (*Student, error)
-	CreateStudent(student *Student) (*Student, error)
-	UpdateStudentStatus(student *Student) error
+	GetStudentByEmail(ctx context.Context, email string) (*Student, error)
+	GetStudentByNumber(ctx context.Context, studentNumber string) (*Student, error)
+	CreateStudent(ctx context.Context, student *Student) (*Student, error)
+	UpdateStudentStatus(ctx context.Context, student *Student, change *StudentStatusChange) error
+	GraduateStudent(ctx context.Context, student *Student, change *StudentStatusChange) error
+	ListStudents(ctx context.Context, statuses []StatusStudent, page Page) ([]Student, int64, error)
+	ListStudentsByTeacher(ctx context.Context, teacherID uint, page Page) ([]Student, int64, error)
+	GetStudentsByGradeLevel(ctx context.Context, gradeLevel string, includeGraduated bool) ([]Student, error)
+	UpdateStudentProfile(ctx context.Context, student *Student) error
 }
 
 //status of students
@@ -43,9 +55,23 @@ const (
 )
 
 //Get student detail by its email id
-func (s *studentRepo) GetStudentByEmail(email string) (*Student, error) {
+func (s *studentRepo) GetStudentByEmail(ctx context.Context, email string) (*Student, error) {
 	var details Student
-	res := s.db.Where("email = ?", email).First(&details)
+	res := s.db.WithContext(ctx).Where("email = ?", email).First(&details)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else {
+			return nil, res.Error
+		}
+	}
+	return &details, nil
+}
+
+//Get student detail by its school issued student number
+func (s *studentRepo) GetStudentByNumber(ctx context.Context, studentNumber string) (*Student, error) {
+	var details Student
+	res := s.db.WithContext(ctx).Where("student_number = ?", studentNumber).First(&details)
 	if res.Error != nil {
 		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -57,19 +83,117 @@ func (s *studentRepo) GetStudentByEmail(email string) (*Student, error) {
 }
 
 //create a new student
-func (s *studentRepo) CreateStudent(student *Student) (*Student, error) {
-	err := s.db.Create(student).Error
+func (s *studentRepo) CreateStudent(ctx context.Context, student *Student) (*Student, error) {
+	err := s.db.WithContext(ctx).Create(student).Error
 	if err != nil {
 		return nil, err
 	}
 	return student, nil
 }
 
-//Update student's status
-func (s *studentRepo) UpdateStudentStatus(student *Student) error {
-	err := s.db.Save(student).Error
+//Move student's status from change.FromStatus to change.ToStatus and record the change in the same transaction.
+//ErrStatusChanged is returned when the student's status is no longer change.FromStatus.
+func (s *studentRepo) UpdateStudentStatus(ctx context.Context, student *Student, change *StudentStatusChange) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return changeStatus(tx, student, change)
+	})
+}
+
+//Mark student as graduated, record the change and archive its teacher registrations in the same transaction.
+//ErrStatusChanged is returned when the student's status is no longer change.FromStatus.
+func (s *studentRepo) GraduateStudent(ctx context.Context, student *Student, change *StudentStatusChange) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := changeStatus(tx, student, change)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&TeacherStudent{}).
+			Where("student_id = ?", student.ID).
+			Where("archived_at IS NULL").
+			Update("archived_at", time.Now()).Error
+	})
+}
+
+//Update only the status column, and only while it still holds the from status, so two concurrent changes
+//of one student can't both succeed
+func changeStatus(tx *gorm.DB, student *Student, change *StudentStatusChange) error {
+	res := tx.Model(student).
+		Where("status = ?", change.FromStatus).
+		Update("status", change.ToStatus)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrStatusChanged
+	}
+
+	change.StudentID = student.ID
+	return tx.Create(change).Error
+}
+
+//Get a page of students ordered by email, with the total number of matching students.
+//All students are listed when no statuses are given.
+func (s *studentRepo) ListStudents(ctx context.Context, statuses []StatusStudent, page Page) ([]Student, int64, error) {
+	query := s.db.WithContext(ctx).Model(Student{})
+	if len(statuses) > 0 {
+		query = query.Where("status IN (?)", statuses)
+	}
+	//the conditions are shared by the count and the page query
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var students []Student
+	err = query.Order("email, id").Limit(page.Limit).Offset(page.Offset).Find(&students).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return students, total, nil
+}
+
+//Get a page of the students currently registered with a teacher ordered by email, with the total number of them.
+//Registrations archived by graduation are left out.
+func (s *studentRepo) ListStudentsByTeacher(ctx context.Context, teacherID uint, page Page) ([]Student, int64, error) {
+	query := s.db.WithContext(ctx).
+		Model(Student{}).
+		Joins("JOIN teacher_students ON teacher_students.student_id = students.id").
+		Where("teacher_students.teacher_id = ? AND teacher_students.deleted_at IS NULL AND teacher_students.archived_at IS NULL", teacherID).
+		Session(&gorm.Session{})
+
+	var total int64
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var students []Student
+	err = query.Select("students.*").Order("students.email, students.id").Limit(page.Limit).Offset(page.Offset).Find(&students).Error
 	if err != nil {
-		return err
+		return nil, 0, err
+	}
+	return students, total, nil
+}
+
+//Get the students of a grade level ordered by email. Graduated students are left out unless includeGraduated is set.
+func (s *studentRepo) GetStudentsByGradeLevel(ctx context.Context, gradeLevel string, includeGraduated bool) ([]Student, error) {
+	query := s.db.WithContext(ctx).Where("grade_level = ?", gradeLevel)
+	if !includeGraduated {
+		query = query.Not("status = ?", StatusGraduated)
 	}
-	return nil
+
+	var students []Student
+	err := query.Order("email, id").Find(&students).Error
+	if err != nil {
+		return nil, err
+	}
+	return students, nil
+}
+
+//Save the profile fields of a student
+func (s *studentRepo) UpdateStudentProfile(ctx context.Context, student *Student) error {
+	return s.db.WithContext(ctx).Model(student).Select("Name", "PreferredName", "StudentNumber", "GradeLevel", "Metadata").Updates(student).Error
 }
@@ -0,0 +1,30 @@
+package models
+
+import "errors"
+
+//ErrStatusChanged is returned by a status update when the student's status is no longer the change's from status,
+//because another request changed it first
+var ErrStatusChanged = errors.New("student status was changed concurrently")
+
+//allowed status changes of a student, keyed by the current status
+var studentStatusTransitions = map[StatusStudent][]StatusStudent{
+	StatusActive:    {StatusSuspended, StatusGraduated},
+	StatusSuspended: {StatusActive},
+	StatusGraduated: {},
+}
+
+//Check if the status is one of the known student statuses
+func (status StatusStudent) IsValid() bool {
+	_, ok := studentStatusTransitions[status]
+	return ok
+}
+
+//Check if a student can be moved from the current status to the next one
+func (status StatusStudent) CanTransitionTo(next StatusStudent) bool {
+	for _, allowed := range studentStatusTransitions[status] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
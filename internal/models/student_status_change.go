@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type StudentStatusChange struct {
+	ID         uint          `gorm:"primaryKey" json:"id"`
+	StudentID  uint          `gorm:"not null" json:"student_id"`
+	FromStatus StatusStudent `gorm:"not null" json:"from_status"`
+	ToStatus   StatusStudent `gorm:"not null" json:"to_status"`
+	ChangedBy  string        `json:"changed_by"`
+	Reason     string        `json:"reason"`
+	CreatedAt  time.Time     `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (StudentStatusChange) TableName() string {
+	return "student_status_changes"
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -8,10 +9,14 @@ import (
 )
 
 type Teacher struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	Email     string    `gorm:"unique;not null" json:"email"`
-	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAT time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	Email         string    `gorm:"unique;not null" json:"email"`
+	Name          string    `json:"name"`
+	PreferredName string    `json:"preferred_name"`
+	Department    string    `json:"department"`
+	Metadata      Metadata  `gorm:"type:json" json:"metadata"`
+	CreatedAt     time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAT     time.Time `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime" json:"updated_at"`
 }
 
 func (Teacher) TableName() string {
@@ -27,13 +32,16 @@ func NewTeacherRepo(db *gorm.DB) TeacherRepo {
 }
 
 type TeacherRepo interface {
-	CreateTeacher(teacher *Teacher) (*Teacher, error)
-	GetTeacherByEmail(email string) (*Teacher, error)
+	CreateTeacher(ctx context.Context, teacher *Teacher) (*Teacher, error)
+	GetTeacherByEmail(ctx context.Context, email string) (*Teacher, error)
+	ListTeachers(ctx context.Context, page Page) ([]Teacher, int64, error)
+	GetTeachersByStudent(ctx context.Context, studentID uint) ([]Teacher, error)
+	UpdateTeacherProfile(ctx context.Context, teacher *Teacher) error
 }
 
 //Create a new teacher
-func (s *teacherRepo) CreateTeacher(teacher *Teacher) (*Teacher, error) {
-	err := s.db.Create(teacher).Error
+func (s *teacherRepo) CreateTeacher(ctx context.Context, teacher *Teacher) (*Teacher, error) {
+	err := s.db.WithContext(ctx).Create(teacher).Error
 	if err != nil {
 		return nil, err
 	}
@@ -41,9 +49,9 @@ func (s *teacherRepo) CreateTeacher(teacher *Teacher) (*Teacher, error) {
 }
 
 //Get teacher's detail by its email id
-func (t *teacherRepo) GetTeacherByEmail(email string) (*Teacher, error) {
+func (t *teacherRepo) GetTeacherByEmail(ctx context.Context, email string) (*Teacher, error) {
 	var details Teacher
-	res := t.db.Where("email = ?", email).First(&details)
+	res := t.db.WithContext(ctx).Where("email = ?", email).First(&details)
 	if res.Error != nil {
 		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -53,3 +61,40 @@ func (t *teacherRepo) GetTeacherByEmail(email string) (*Teacher, error) {
 	}
 	return &details, nil
 }
+
+//Get a page of teachers ordered by email, with the total number of teachers
+func (t *teacherRepo) ListTeachers(ctx context.Context, page Page) ([]Teacher, int64, error) {
+	var total int64
+	err := t.db.WithContext(ctx).Model(Teacher{}).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var teachers []Teacher
+	err = t.db.WithContext(ctx).Order("email, id").Limit(page.Limit).Offset(page.Offset).Find(&teachers).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return teachers, total, nil
+}
+
+//Get the teachers a student is currently registered with, ordered by email. Registrations archived by graduation are left out.
+func (t *teacherRepo) GetTeachersByStudent(ctx context.Context, studentID uint) ([]Teacher, error) {
+	var teachers []Teacher
+	err := t.db.WithContext(ctx).
+		Model(Teacher{}).
+		Select("teachers.*").
+		Joins("JOIN teacher_students ON teacher_students.teacher_id = teachers.id").
+		Where("teacher_students.student_id = ? AND teacher_students.deleted_at IS NULL AND teacher_students.archived_at IS NULL", studentID).
+		Order("teachers.email").
+		Find(&teachers).Error
+	if err != nil {
+		return nil, err
+	}
+	return teachers, nil
+}
+
+//Save the profile fields of a teacher
+func (t *teacherRepo) UpdateTeacherProfile(ctx context.Context, teacher *Teacher) error {
+	return t.db.WithContext(ctx).Model(teacher).Select("Name", "PreferredName", "Department", "Metadata").Updates(teacher).Error
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -8,17 +9,71 @@ import (
 )
 
 type TeacherStudent struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	TeacherID uint      `gorm:"primaryKey" json:"teacher_id"`
-	StudentID uint      `gorm:"primaryKey" json:"student_id"`
-	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAT time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	TeacherID  uint           `gorm:"primaryKey" json:"teacher_id"`
+	StudentID  uint           `gorm:"primaryKey" json:"student_id"`
+	ArchivedAt *time.Time     `json:"archived_at"`
+	CreatedAt  time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAT  time.Time      `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime" json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
 
 func (TeacherStudent) TableName() string {
 	return "teacher_students"
 }
 
+//A past or current registration of a student with a teacher
+type Enrolment struct {
+	TeacherEmail   string
+	RegisteredAt   time.Time
+	ArchivedAt     *time.Time
+	DeregisteredAt *time.Time
+}
+
+//filters of the common students query
+type CommonStudentsFilter struct {
+	Teachers []string
+
+	//Minimum number of Teachers a student must be registered with
+	MinTeachers int
+
+	//Students registered with any of these teachers are left out
+	ExcludeTeachers []string
+
+	IncludeGraduated bool
+	ClassID          uint
+
+	//Only students with these statuses are returned when given. It takes precedence over IncludeGraduated.
+	Statuses []StatusStudent
+
+	//One of the CommonStudentsSort values, email by default
+	Sort       string
+	Descending bool
+
+	//Every matching student is returned when Limit is 0
+	Limit  int
+	Offset int
+}
+
+//IncludesGraduated reports whether graduated students can match the filter. The registrations archived by graduation
+//only count towards the teachers of a student when they can.
+func (filter CommonStudentsFilter) IncludesGraduated() bool {
+	if len(filter.Statuses) == 0 {
+		return filter.IncludeGraduated
+	}
+	for _, status := range filter.Statuses {
+		if status == StatusGraduated {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	CommonStudentsSortEmail     = "email"
+	CommonStudentsSortCreatedAt = "created_at"
+)
+
 type teacherStudentRepo struct {
 	db *gorm.DB
 }
@@ -28,21 +83,28 @@ func NewTeacherStudentRepo(db *gorm.DB) TeacherStudentRepo {
 }
 
 type TeacherStudentRepo interface {
-	CreateTeacherStudent(*TeacherStudent) error
-	IsStudentRegisteredForTeacher(uint, uint) (*TeacherStudent, error)
-	GetCommonStudents([]string) ([]string, error)
-	GetAllStudentsByTeacher(string) ([]Student, error)
+	CreateTeacherStudent(context.Context, *TeacherStudent) error
+	DeleteTeacherStudent(context.Context, uint, uint) error
+	IsStudentRegisteredForTeacher(context.Context, uint, uint) (*TeacherStudent, error)
+	GetCommonStudents(context.Context, CommonStudentsFilter) ([]string, int64, error)
+	GetAllStudentsByTeacher(context.Context, string, bool) ([]Student, error)
+	GetEnrolmentHistory(context.Context, uint) ([]Enrolment, error)
 }
 
 //Register a student with a teacher
-func (ts *teacherStudentRepo) CreateTeacherStudent(teacherStudentObj *TeacherStudent) error {
-	return ts.db.Create(teacherStudentObj).Error
+func (ts *teacherStudentRepo) CreateTeacherStudent(ctx context.Context, teacherStudentObj *TeacherStudent) error {
+	return ts.db.WithContext(ctx).Create(teacherStudentObj).Error
+}
+
+//Deregister a student from a teacher. The registration is soft deleted so past enrolments are kept.
+func (ts *teacherStudentRepo) DeleteTeacherStudent(ctx context.Context, teacherID uint, studentID uint) error {
+	return ts.db.WithContext(ctx).Where("teacher_id = ?", teacherID).Where("student_id = ?", studentID).Delete(&TeacherStudent{}).Error
 }
 
 //Check if given student is registered with given teacher
-func (ts *teacherStudentRepo) IsStudentRegisteredForTeacher(teacherID uint, studentID uint) (*TeacherStudent, error) {
+func (ts *teacherStudentRepo) IsStudentRegisteredForTeacher(ctx context.Context, teacherID uint, studentID uint) (*TeacherStudent, error) {
 	var details TeacherStudent
-	res := ts.db.Where("teacher_id = ?", teacherID).Where("student_id = ?", studentID).First(&details)
+	res := ts.db.WithContext(ctx).Where("teacher_id = ?", teacherID).Where("student_id = ?", studentID).First(&details)
 	if res.Error != nil {
 		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -53,34 +115,110 @@ func (ts *teacherStudentRepo) IsStudentRegisteredForTeacher(teacherID uint, stud
 	return &details, nil
 }
 
-//Get list of common students to a given list of teachers
-func (ts *teacherStudentRepo) GetCommonStudents(teachers []string) ([]string, error) {
-	var students []string
-	query := ts.db.Raw(`SELECT s.email
+//Get every registration of a student, including archived and deregistered ones, oldest first
+func (ts *teacherStudentRepo) GetEnrolmentHistory(ctx context.Context, studentID uint) ([]Enrolment, error) {
+	var enrolments []Enrolment
+	err := ts.db.WithContext(ctx).
+		Unscoped().
+		Model(&TeacherStudent{}).
+		Select("teachers.email AS teacher_email, teacher_students.created_at AS registered_at, teacher_students.archived_at, teacher_students.deleted_at AS deregistered_at").
+		Joins("JOIN teachers ON teachers.id = teacher_students.teacher_id").
+		Where("teacher_students.student_id = ?", studentID).
+		Order("teacher_students.created_at, teacher_students.id").
+		Scan(&enrolments).Error
+	if err != nil {
+		return nil, err
+	}
+	return enrolments, nil
+}
+
+//Get list of students registered with at least MinTeachers of a given list of teachers and with none of ExcludeTeachers.
+//Graduated students and archived registrations are left out unless IncludeGraduated is set and the list is limited
+//to a class when ClassID is set.
+func (ts *teacherStudentRepo) GetCommonStudents(ctx context.Context, filter CommonStudentsFilter) ([]string, int64, error) {
+	registrations := "ts.deleted_at IS NULL"
+	excludedRegistrations := "ets.deleted_at IS NULL"
+	if !filter.IncludesGraduated() {
+		registrations += " AND ts.archived_at IS NULL"
+		excludedRegistrations += " AND ets.archived_at IS NULL"
+	}
+
+	conditions := ""
+	args := []interface{}{filter.Teachers}
+	if len(filter.Statuses) > 0 {
+		conditions += " AND s.status IN (?)"
+		args = append(args, filter.Statuses)
+	} else if !filter.IncludeGraduated {
+		conditions += " AND s.status <> ?"
+		args = append(args, StatusGraduated)
+	}
+	if filter.ClassID != 0 {
+		conditions += " AND s.id IN (SELECT cs.student_id FROM class_students AS cs WHERE cs.class_id = ?)"
+		args = append(args, filter.ClassID)
+	}
+	if len(filter.ExcludeTeachers) > 0 {
+		conditions += ` AND s.id NOT IN (SELECT ets.student_id FROM teacher_students AS ets
+					   JOIN teachers AS et ON et.id = ets.teacher_id
+					   WHERE et.email IN (?) AND ` + excludedRegistrations + `)`
+		args = append(args, filter.ExcludeTeachers)
+	}
+	args = append(args, filter.MinTeachers)
+
+	common := `SELECT s.id, s.email, s.created_at
 	                   FROM students AS s 
 					   JOIN teacher_students AS ts ON s.id = ts.student_id
 					   JOIN teachers AS t ON t.id = ts.teacher_id
-					   WHERE t.email IN (?)
-					   GROUP BY s.email
-					   HAVING COUNT(s.email) = ?
-					   `, teachers, len(teachers)).Scan(&students)
+					   WHERE t.email IN (?) AND ` + registrations + conditions + `
+					   GROUP BY s.id, s.email, s.created_at
+					   HAVING COUNT(DISTINCT t.id) >= ?`
+
+	//count all matches before paginating
+	var total int64
+	query := ts.db.WithContext(ctx).Raw(`SELECT COUNT(*) FROM (`+common+`) AS common`, args...).Scan(&total)
 	if query.Error != nil {
-		return nil, query.Error
+		return nil, 0, query.Error
 	}
-	return students, nil
+
+	//sort column and direction come from fixed values only, email and id break ties so pages never overlap
+	direction := "ASC"
+	if filter.Descending {
+		direction = "DESC"
+	}
+	orderBy := "common.email " + direction + ", common.id " + direction
+	if filter.Sort == CommonStudentsSortCreatedAt {
+		orderBy = "common.created_at " + direction + ", " + orderBy
+	}
+
+	page := ""
+	if filter.Limit > 0 {
+		page = " LIMIT ? OFFSET ?"
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	var students []string
+	query = ts.db.WithContext(ctx).Raw(`SELECT common.email FROM (`+common+`) AS common
+					   ORDER BY `+orderBy+page, args...).Scan(&students)
+	if query.Error != nil {
+		return nil, 0, query.Error
+	}
+	return students, total, nil
 }
 
-//Get all registered students of a teacher
-func (ts *teacherStudentRepo) GetAllStudentsByTeacher(teacher string) ([]Student, error) {
+//Get all registered students of a teacher who can receive notifications. Graduated students and archived registrations
+//are left out unless includeGraduated is set.
+func (ts *teacherStudentRepo) GetAllStudentsByTeacher(ctx context.Context, teacher string, includeGraduated bool) ([]Student, error) {
 	var students []Student
-	err := ts.db.
+	query := ts.db.WithContext(ctx).
 		Model(TeacherStudent{}).
 		Select("students.*").
 		Joins("JOIN students ON students.id = teacher_students.student_id").
 		Joins("JOIN teachers ON teachers.id = teacher_students.teacher_id").
 		Where("teachers.email = ?", teacher).
-		Not("students.status = ?", StatusSuspended).
-		Find(&students).Error
+		Not("students.status = ?", StatusSuspended)
+	if !includeGraduated {
+		query = query.Not("students.status = ?", StatusGraduated).Where("teacher_students.archived_at IS NULL")
+	}
+	err := query.Find(&students).Error
 
 	if err != nil {
 		return nil, err
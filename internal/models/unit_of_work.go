@@ -0,0 +1,45 @@
+package models
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+//Repositories bound to the same database handle
+type Repositories struct {
+	Teachers        TeacherRepo
+	Students        StudentRepo
+	TeacherStudents TeacherStudentRepo
+	Classes         ClassRepo
+	Notifications   NotificationRepo
+	Deliveries      DeliveryRepo
+}
+
+//UnitOfWork runs a group of repository calls inside one transaction
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(repos Repositories) error) error
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{db}
+}
+
+//Run fn with repositories bound to a new transaction. It is committed if fn returns nil and rolled back otherwise,
+//including when ctx is done before the commit.
+func (u *unitOfWork) Do(ctx context.Context, fn func(repos Repositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(Repositories{
+			Teachers:        NewTeacherRepo(tx),
+			Students:        NewStudentRepo(tx),
+			TeacherStudents: NewTeacherStudentRepo(tx),
+			Classes:         NewClassRepo(tx),
+			Notifications:   NewNotificationRepo(tx),
+			Deliveries:      NewDeliveryRepo(tx),
+		})
+	})
+}
@@ -0,0 +1,34 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+//LogNotifier writes every message as a JSON line. It is meant for local use.
+type LogNotifier struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+func NewLogNotifier(writer io.Writer) *LogNotifier {
+	return &LogNotifier{writer: writer}
+}
+
+func (l *LogNotifier) Send(ctx context.Context, message Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.writer.Write(append(line, '\n'))
+	return err
+}
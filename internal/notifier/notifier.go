@@ -0,0 +1,67 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+//Message delivered to one recipient of a notification
+type Message struct {
+	NotificationID uint   `json:"notification_id"`
+	From           string `json:"from"`
+	To             string `json:"to"`
+	Subject        string `json:"subject"`
+	Body           string `json:"body"`
+}
+
+//Notifier delivers a message to its recipient. A returned error means the delivery should be retried.
+//Send gives up once ctx is done.
+type Notifier interface {
+	Send(ctx context.Context, message Message) error
+}
+
+//Config selects and configures the notifier used for delivery
+type Config struct {
+	//Kind is one of smtp, webhook or log. Defaults to log.
+	Kind string `json:"kind"`
+
+	//File the log notifier writes to. Defaults to stdout.
+	LogFile string `json:"log_file"`
+
+	SMTPHost     string `json:"smtp_host"`
+	SMTPPort     string `json:"smtp_port"`
+	SMTPUsername string `json:"smtp_username"`
+	SMTPPassword string `json:"smtp_password"`
+	SMTPFrom     string `json:"smtp_from"`
+
+	WebhookURL string `json:"webhook_url"`
+}
+
+//New builds the notifier selected by the config
+func New(config Config) (Notifier, error) {
+	switch strings.ToLower(config.Kind) {
+	case "", "log":
+		if config.LogFile == "" {
+			return NewLogNotifier(os.Stdout), nil
+		}
+		file, err := os.OpenFile(config.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		return NewLogNotifier(file), nil
+	case "smtp":
+		if config.SMTPHost == "" || config.SMTPFrom == "" {
+			return nil, fmt.Errorf("smtp notifier requires a host and a from address")
+		}
+		return NewSMTPNotifier(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword, config.SMTPFrom), nil
+	case "webhook":
+		if config.WebhookURL == "" {
+			return nil, fmt.Errorf("webhook notifier requires a url")
+		}
+		return NewWebhookNotifier(config.WebhookURL, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier %q", config.Kind)
+	}
+}
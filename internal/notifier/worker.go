@@ -0,0 +1,248 @@
+package notifier
+
+import (
+	"class-management/internal/models"
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+//Size of the last_error column
+const maxLastErrorLength = 1024
+
+//WorkerOptions tune the delivery worker. Zero values fall back to the defaults.
+type WorkerOptions struct {
+	//How often the outbox is polled. Defaults to 5 seconds.
+	Interval time.Duration
+
+	//Maximum deliveries attempted per poll. Defaults to 100.
+	BatchSize int
+
+	//Attempts after which a delivery is marked FAILED. Defaults to 5.
+	MaxAttempts int
+
+	//Delay before the first retry, doubled after every failed attempt. Defaults to 30 seconds.
+	BaseBackoff time.Duration
+
+	//Upper bound of the retry delay. Defaults to 1 hour.
+	MaxBackoff time.Duration
+
+	//How long the deliveries of a poll stay claimed by this worker. Deliveries the worker hasn't attempted
+	//when the claim runs out are left to the next poll of any worker. Defaults to 5 minutes.
+	ClaimTimeout time.Duration
+
+	//Deadline of sending one delivery. It never reaches past the claim of the delivery. Defaults to 30 seconds.
+	SendTimeout time.Duration
+
+	//Receives failed polls and deliveries. Defaults to a logger that drops everything.
+	Logger *zap.Logger
+}
+
+//Worker delivers pending outbox entries through a notifier and records the outcome of every attempt
+type Worker struct {
+	deliveries models.DeliveryRepo
+	notifier   Notifier
+	options    WorkerOptions
+
+	//1 while Run is polling, and the unix nano time the last poll started. Read by Check.
+	running  int32
+	lastPoll int64
+}
+
+func NewWorker(deliveries models.DeliveryRepo, notifier Notifier, options WorkerOptions) *Worker {
+	if options.Interval <= 0 {
+		options.Interval = 5 * time.Second
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = 5
+	}
+	if options.BaseBackoff <= 0 {
+		options.BaseBackoff = 30 * time.Second
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = time.Hour
+	}
+	if options.ClaimTimeout <= 0 {
+		options.ClaimTimeout = 5 * time.Minute
+	}
+	if options.SendTimeout <= 0 {
+		options.SendTimeout = 30 * time.Second
+	}
+	if options.Logger == nil {
+		options.Logger = zap.NewNop()
+	}
+
+	return &Worker{
+		deliveries: deliveries,
+		notifier:   notifier,
+		options:    options,
+	}
+}
+
+//Run polls the outbox until stop is closed or ctx is done. Closing stop lets the send in flight finish and
+//releases the rest of the batch, ctx bounds the sends and is only meant to be cancelled once that takes too long.
+func (w *Worker) Run(ctx context.Context, stop <-chan struct{}) {
+	ticker := time.NewTicker(w.options.Interval)
+	defer ticker.Stop()
+
+	atomic.StoreInt64(&w.lastPoll, time.Now().UnixNano())
+	atomic.StoreInt32(&w.running, 1)
+	defer atomic.StoreInt32(&w.running, 0)
+
+	for {
+		if _, err := w.processDue(ctx, stop); err != nil && ctx.Err() == nil {
+			w.options.Logger.Error("delivering notifications failed", zap.Error(err))
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			atomic.StoreInt64(&w.lastPoll, time.Now().UnixNano())
+		}
+	}
+}
+
+//Check reports an error when Run isn't polling, or when a poll hasn't started for three intervals,
+//for example because sending a batch hangs
+func (w *Worker) Check(ctx context.Context) error {
+	if atomic.LoadInt32(&w.running) == 0 {
+		return fmt.Errorf("delivery worker is not running")
+	}
+	sinceLastPoll := time.Since(time.Unix(0, atomic.LoadInt64(&w.lastPoll)))
+	if sinceLastPoll > 3*w.options.Interval {
+		return fmt.Errorf("delivery worker last polled %s ago", sinceLastPoll.Round(time.Second))
+	}
+	return nil
+}
+
+//ProcessDue claims the deliveries that are due, attempts them and returns the number of deliveries attempted.
+//Once ctx is done or the claim has run out no further delivery is attempted. A send interrupted by ctx
+//isn't recorded, and its claim is released together with the rest of the batch, so any worker attempts them again.
+func (w *Worker) ProcessDue(ctx context.Context) (int, error) {
+	return w.processDue(ctx, nil)
+}
+
+//Attempt the due deliveries like ProcessDue, releasing the ones not attempted yet once stop is closed
+func (w *Worker) processDue(ctx context.Context, stop <-chan struct{}) (int, error) {
+	select {
+	case <-stop:
+		return 0, nil
+	default:
+	}
+
+	deliveries, err := w.deliveries.ClaimDueDeliveries(ctx, time.Now(), w.options.BatchSize, w.options.ClaimTimeout)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range deliveries {
+		select {
+		case <-stop:
+			w.release(deliveries[i:])
+			return i, nil
+		default:
+		}
+		if err := ctx.Err(); err != nil {
+			w.release(deliveries[i:])
+			return i, err
+		}
+		delivery := &deliveries[i]
+		//another worker may claim the rest of the batch now, so sending it here could deliver it twice
+		if delivery.LockedUntil == nil || !time.Now().Before(*delivery.LockedUntil) {
+			w.options.Logger.Warn("delivery claim ran out", zap.Int("attempted", i), zap.Int("claimed", len(deliveries)))
+			return i, nil
+		}
+		if !w.attempt(ctx, delivery) {
+			w.release(deliveries[i:])
+			return i, ctx.Err()
+		}
+		//the outcome is saved even when ctx is done by now, so a sent delivery isn't sent again
+		if err := w.save(delivery); err != nil {
+			return i + 1, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+//Send one delivery and update its status, attempts and next attempt time. It returns false, leaving the
+//delivery unchanged, when ctx was done before the send finished.
+func (w *Worker) attempt(ctx context.Context, delivery *models.NotificationDelivery) bool {
+	sendCtx, cancel := context.WithTimeout(ctx, w.options.SendTimeout)
+	defer cancel()
+	sendCtx, cancelClaim := context.WithDeadline(sendCtx, *delivery.LockedUntil)
+	defer cancelClaim()
+
+	err := w.notifier.Send(sendCtx, Message{
+		NotificationID: delivery.NotificationID,
+		From:           delivery.Notification.Teacher.Email,
+		To:             delivery.Email,
+		Subject:        fmt.Sprintf("New notification from %s", delivery.Notification.Teacher.Email),
+		Body:           delivery.Notification.Text,
+	})
+	if err != nil && ctx.Err() != nil {
+		return false
+	}
+
+	delivery.Attempts++
+	if err == nil {
+		deliveredAt := time.Now()
+		delivery.Status = models.DeliverySent
+		delivery.LastError = ""
+		delivery.DeliveredAt = &deliveredAt
+		return true
+	}
+
+	w.options.Logger.Warn("delivery failed",
+		zap.Uint("delivery_id", delivery.ID),
+		zap.String("email", delivery.Email),
+		zap.Int("attempt", delivery.Attempts),
+		zap.Error(err),
+	)
+	delivery.LastError = err.Error()
+	if len(delivery.LastError) > maxLastErrorLength {
+		delivery.LastError = delivery.LastError[:maxLastErrorLength]
+	}
+	if delivery.Attempts >= w.options.MaxAttempts {
+		delivery.Status = models.DeliveryFailed
+		return true
+	}
+	delivery.NextAttemptAt = time.Now().Add(w.backoff(delivery.Attempts))
+	return true
+}
+
+//Save the outcome of an attempt with its own deadline, since the worker context may be done after the send
+func (w *Worker) save(delivery *models.NotificationDelivery) error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.options.SendTimeout)
+	defer cancel()
+	return w.deliveries.UpdateDelivery(ctx, delivery)
+}
+
+//Save claimed deliveries unchanged, which releases their claim, so any worker can attempt them at once
+func (w *Worker) release(deliveries []models.NotificationDelivery) {
+	for i := range deliveries {
+		if err := w.save(&deliveries[i]); err != nil {
+			w.options.Logger.Warn("releasing delivery failed", zap.Uint("delivery_id", deliveries[i].ID), zap.Error(err))
+		}
+	}
+}
+
+//Delay before the retry that follows the given number of attempts
+func (w *Worker) backoff(attempts int) time.Duration {
+	delay := w.options.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= w.options.MaxBackoff {
+			return w.options.MaxBackoff
+		}
+	}
+	return delay
+}
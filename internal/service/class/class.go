@@ -0,0 +1,265 @@
+package class
+
+import (
+	"class-management/errors"
+	"class-management/internal/dto"
+	"class-management/internal/models"
+	"class-management/internal/utils"
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type ClassService interface {
+	CreateClass(context.Context, dto.CreateClassRequest) (dto.ClassResponse, error)
+	AssignTeachers(context.Context, dto.AssignClassTeachersRequest) error
+	EnrolStudents(context.Context, dto.EnrolClassStudentsRequest) (dto.RegistrationResponse, error)
+	ClassRoster(context.Context, dto.ClassRosterRequest) (dto.ClassRosterResponse, error)
+}
+
+type classService struct {
+	classRepo   models.ClassRepo
+	teacherRepo models.TeacherRepo
+	unitOfWork  models.UnitOfWork
+	logger      *zap.Logger
+}
+
+func NewClassService(classRepo models.ClassRepo, teacherRepo models.TeacherRepo, unitOfWork models.UnitOfWork, logger *zap.Logger) ClassService {
+	return &classService{
+		classRepo:   classRepo,
+		teacherRepo: teacherRepo,
+		unitOfWork:  unitOfWork,
+		logger:      logger,
+	}
+}
+
+//CreateClass service creates a new class and assigns the given teachers to it.
+func (cs *classService) CreateClass(ctx context.Context, req dto.CreateClassRequest) (dto.ClassResponse, error) {
+	var response dto.ClassResponse
+
+	classDetails, err := cs.classRepo.GetClassByName(ctx, req.Name)
+	if err != nil {
+		return response, err
+	}
+	if classDetails != nil {
+		return response, errors.ErrClassAlreadyExists
+	}
+
+	teachers, err := cs.getTeachers(ctx, req.Teachers)
+	if err != nil {
+		return response, err
+	}
+
+	//class and its teachers are created together
+	err = cs.unitOfWork.Do(ctx, func(repos models.Repositories) error {
+		classDetails, err = repos.Classes.CreateClass(ctx, &models.Class{Name: req.Name})
+		if err != nil {
+			return err
+		}
+		for _, teacher := range teachers {
+			err = repos.Classes.AssignTeacher(ctx, &models.ClassTeacher{ClassID: classDetails.ID, TeacherID: teacher.ID})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		cs.logger.Error("CreateClass failed", zap.String("class", req.Name), zap.Error(err))
+		return response, err
+	}
+
+	response = dto.ClassResponse{
+		ID:       classDetails.ID,
+		Name:     classDetails.Name,
+		Teachers: []string{},
+	}
+	for _, teacher := range teachers {
+		response.Teachers = append(response.Teachers, teacher.Email)
+	}
+	return response, nil
+}
+
+//AssignTeachers service assigns one or more teachers to a class. Teachers already assigned are ignored.
+func (cs *classService) AssignTeachers(ctx context.Context, req dto.AssignClassTeachersRequest) error {
+	classDetails, err := cs.getClass(ctx, req.ClassID)
+	if err != nil {
+		return err
+	}
+
+	teachers, err := cs.getTeachers(ctx, req.Teachers)
+	if err != nil {
+		return err
+	}
+
+	return cs.unitOfWork.Do(ctx, func(repos models.Repositories) error {
+		for _, teacher := range teachers {
+			assigned, err := repos.Classes.IsTeacherAssignedToClass(ctx, classDetails.ID, teacher.ID)
+			if err != nil {
+				return err
+			}
+			if assigned != nil {
+				continue
+			}
+			err = repos.Classes.AssignTeacher(ctx, &models.ClassTeacher{ClassID: classDetails.ID, TeacherID: teacher.ID})
+			if err != nil {
+				cs.logger.Error("AssignTeacher failed", zap.String("email", teacher.Email), zap.Error(err))
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+//EnrolStudents service enrols students in a class, creating the ones who don't exist yet, and reports the outcome for every email.
+func (cs *classService) EnrolStudents(ctx context.Context, req dto.EnrolClassStudentsRequest) (dto.RegistrationResponse, error) {
+	response := dto.RegistrationResponse{Results: []dto.RegistrationResult{}}
+
+	classDetails, err := cs.getClass(ctx, req.ClassID)
+	if err != nil {
+		return response, err
+	}
+
+	//a teacher can only enrol students in a class they are assigned to
+	if req.ActingTeacher != "" {
+		teacherDetails, err := cs.teacherRepo.GetTeacherByEmail(ctx, req.ActingTeacher)
+		if err != nil {
+			return response, err
+		}
+		if teacherDetails == nil {
+			return response, errors.ErrNotClassTeacher
+		}
+		assigned, err := cs.classRepo.IsTeacherAssignedToClass(ctx, classDetails.ID, teacherDetails.ID)
+		if err != nil {
+			return response, err
+		}
+		if assigned == nil {
+			return response, errors.ErrNotClassTeacher
+		}
+	}
+
+	for _, studentEmail := range req.Students {
+		if !utils.IsEmailValid(studentEmail) {
+			cs.logger.Info("invalid email", zap.String("email", studentEmail))
+			response.Results = append(response.Results, dto.RegistrationResult{Email: studentEmail, Status: dto.RegistrationInvalid, Reason: "email address is not valid"})
+			continue
+		}
+
+		var status dto.RegistrationStatus
+		err := cs.unitOfWork.Do(ctx, func(repos models.Repositories) error {
+			var err error
+			status, err = cs.enrolStudent(ctx, repos, classDetails, studentEmail)
+			return err
+		})
+		if err != nil {
+			response.Results = append(response.Results, dto.RegistrationResult{Email: studentEmail, Status: dto.RegistrationFailed, Reason: "email could not be saved, please try again"})
+			continue
+		}
+		response.Results = append(response.Results, dto.RegistrationResult{Email: studentEmail, Status: status})
+	}
+
+	return response, nil
+}
+
+//Create the student if needed and enrol it in the class using the given repositories
+func (cs *classService) enrolStudent(ctx context.Context, repos models.Repositories, classDetails *models.Class, studentEmail string) (dto.RegistrationStatus, error) {
+	status := dto.RegistrationLinked
+
+	studentDetails, err := repos.Students.GetStudentByEmail(ctx, studentEmail)
+	if err != nil {
+		cs.logger.Error("GetStudentByEmail failed", zap.String("email", studentEmail), zap.Error(err))
+		return "", err
+	}
+	if studentDetails == nil {
+		studentDetails, err = repos.Students.CreateStudent(ctx, &models.Student{Email: studentEmail, Status: models.StatusActive})
+		if err != nil {
+			cs.logger.Error("CreateStudent failed", zap.String("email", studentEmail), zap.Error(err))
+			return "", err
+		}
+		status = dto.RegistrationCreated
+	}
+
+	enrolled, err := repos.Classes.IsStudentEnrolledInClass(ctx, classDetails.ID, studentDetails.ID)
+	if err != nil {
+		cs.logger.Error("IsStudentEnrolledInClass failed", zap.String("email", studentEmail), zap.Error(err))
+		return "", err
+	}
+	if enrolled != nil {
+		return dto.RegistrationAlreadyRegistered, nil
+	}
+
+	err = repos.Classes.EnrolStudent(ctx, &models.ClassStudent{ClassID: classDetails.ID, StudentID: studentDetails.ID})
+	if err != nil {
+		cs.logger.Error("EnrolStudent failed", zap.String("email", studentEmail), zap.Error(err))
+		return "", err
+	}
+	return status, nil
+}
+
+//ClassRoster service lists the teachers and students of a class.
+func (cs *classService) ClassRoster(ctx context.Context, req dto.ClassRosterRequest) (dto.ClassRosterResponse, error) {
+	var response dto.ClassRosterResponse
+
+	classDetails, err := cs.getClass(ctx, req.ClassID)
+	if err != nil {
+		return response, err
+	}
+
+	teachers, err := cs.classRepo.GetClassTeachers(ctx, classDetails.ID)
+	if err != nil {
+		return response, err
+	}
+
+	students, err := cs.classRepo.GetClassStudents(ctx, classDetails.ID, req.IncludeGraduated)
+	if err != nil {
+		return response, err
+	}
+
+	response = dto.ClassRosterResponse{
+		ID:       classDetails.ID,
+		Name:     classDetails.Name,
+		Teachers: []string{},
+		Students: []string{},
+	}
+	for _, teacher := range teachers {
+		response.Teachers = append(response.Teachers, teacher.Email)
+	}
+	for _, student := range students {
+		response.Students = append(response.Students, student.Email)
+	}
+	return response, nil
+}
+
+//Get class by id or return error if it doesn't exist
+func (cs *classService) getClass(ctx context.Context, classID uint) (*models.Class, error) {
+	classDetails, err := cs.classRepo.GetClassByID(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+	if classDetails == nil {
+		return nil, errors.ErrClassNotExists
+	}
+	return classDetails, nil
+}
+
+//Get all given teachers once each, in the given order, or return error if any of them doesn't exist
+func (cs *classService) getTeachers(ctx context.Context, emails []string) ([]models.Teacher, error) {
+	teachers := []models.Teacher{}
+	//teachers are told apart by id, since the database may match an email regardless of its case
+	found := make(map[uint]bool)
+	for _, email := range emails {
+		teacherDetails, err := cs.teacherRepo.GetTeacherByEmail(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		if teacherDetails == nil {
+			return nil, errors.ErrTeacherNotExists
+		}
+		if found[teacherDetails.ID] {
+			continue
+		}
+		found[teacherDetails.ID] = true
+		teachers = append(teachers, *teacherDetails)
+	}
+	return teachers, nil
+}
@@ -0,0 +1,281 @@
+package directory
+
+import (
+	"class-management/errors"
+	"class-management/internal/dto"
+	"class-management/internal/models"
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type DirectoryService interface {
+	ListTeachers(ctx context.Context, page dto.PageRequest) (dto.TeacherListResponse, error)
+	GetTeacher(ctx context.Context, email string) (dto.TeacherResponse, error)
+	ListStudents(ctx context.Context, req dto.ListStudentsRequest) (dto.StudentListResponse, error)
+	GetStudent(ctx context.Context, email string) (dto.StudentResponse, error)
+	ListTeacherStudents(ctx context.Context, teacher string, page dto.PageRequest) (dto.TeacherStudentsResponse, error)
+	GetStudentEnrolments(ctx context.Context, email string) (dto.StudentEnrolmentsResponse, error)
+	UpdateTeacher(ctx context.Context, req dto.UpdateTeacherRequest) (dto.TeacherResponse, error)
+	UpdateStudent(ctx context.Context, req dto.UpdateStudentRequest) (dto.StudentResponse, error)
+}
+
+type directoryService struct {
+	teacherRepo        models.TeacherRepo
+	studentRepo        models.StudentRepo
+	teacherStudentRepo models.TeacherStudentRepo
+	logger             *zap.Logger
+}
+
+func NewDirectoryService(teacherRepo models.TeacherRepo, studentRepo models.StudentRepo, teacherStudentRepo models.TeacherStudentRepo, logger *zap.Logger) DirectoryService {
+	return &directoryService{
+		teacherRepo:        teacherRepo,
+		studentRepo:        studentRepo,
+		teacherStudentRepo: teacherStudentRepo,
+		logger:             logger,
+	}
+}
+
+//ListTeachers service lists a page of teachers ordered by email.
+func (ds *directoryService) ListTeachers(ctx context.Context, page dto.PageRequest) (dto.TeacherListResponse, error) {
+	response := dto.TeacherListResponse{
+		Teachers:     []dto.TeacherResponse{},
+		PageResponse: dto.PageResponse{Limit: page.Limit, Offset: page.Offset},
+	}
+
+	teachers, total, err := ds.teacherRepo.ListTeachers(ctx, models.Page{Limit: page.Limit, Offset: page.Offset})
+	if err != nil {
+		return response, err
+	}
+
+	for _, teacher := range teachers {
+		response.Teachers = append(response.Teachers, teacherResponse(teacher))
+	}
+	response.Total = total
+	return response, nil
+}
+
+//GetTeacher service retrieves a teacher by email.
+func (ds *directoryService) GetTeacher(ctx context.Context, email string) (dto.TeacherResponse, error) {
+	teacher, err := ds.teacherRepo.GetTeacherByEmail(ctx, email)
+	if err != nil {
+		return dto.TeacherResponse{}, err
+	}
+	if teacher == nil {
+		return dto.TeacherResponse{}, errors.ErrTeacherNotExists
+	}
+	return teacherResponse(*teacher), nil
+}
+
+//ListStudents service lists a page of students ordered by email, optionally limited to some statuses.
+func (ds *directoryService) ListStudents(ctx context.Context, req dto.ListStudentsRequest) (dto.StudentListResponse, error) {
+	response := dto.StudentListResponse{
+		Students:     []dto.StudentSummary{},
+		PageResponse: dto.PageResponse{Limit: req.Limit, Offset: req.Offset},
+	}
+
+	statuses := make([]models.StatusStudent, 0, len(req.Statuses))
+	for _, status := range req.Statuses {
+		statuses = append(statuses, models.StatusStudent(status))
+	}
+
+	students, total, err := ds.studentRepo.ListStudents(ctx, statuses, models.Page{Limit: req.Limit, Offset: req.Offset})
+	if err != nil {
+		return response, err
+	}
+
+	for _, student := range students {
+		response.Students = append(response.Students, studentSummary(student))
+	}
+	response.Total = total
+	return response, nil
+}
+
+//GetStudent service retrieves a student by email with the teachers the student is registered with.
+func (ds *directoryService) GetStudent(ctx context.Context, email string) (dto.StudentResponse, error) {
+	var response dto.StudentResponse
+
+	student, err := ds.studentRepo.GetStudentByEmail(ctx, email)
+	if err != nil {
+		return response, err
+	}
+	if student == nil {
+		return response, errors.ErrStudentNotExists
+	}
+
+	teachers, err := ds.teacherRepo.GetTeachersByStudent(ctx, student.ID)
+	if err != nil {
+		return response, err
+	}
+
+	response = dto.StudentResponse{
+		StudentSummary: studentSummary(*student),
+		Teachers:       []string{},
+	}
+	for _, teacher := range teachers {
+		response.Teachers = append(response.Teachers, teacher.Email)
+	}
+	return response, nil
+}
+
+//ListTeacherStudents service lists a page of the students registered with a teacher ordered by email.
+func (ds *directoryService) ListTeacherStudents(ctx context.Context, teacher string, page dto.PageRequest) (dto.TeacherStudentsResponse, error) {
+	response := dto.TeacherStudentsResponse{
+		Teacher:      teacher,
+		Students:     []dto.StudentSummary{},
+		PageResponse: dto.PageResponse{Limit: page.Limit, Offset: page.Offset},
+	}
+
+	teacherDetails, err := ds.teacherRepo.GetTeacherByEmail(ctx, teacher)
+	if err != nil {
+		return response, err
+	}
+	if teacherDetails == nil {
+		return response, errors.ErrTeacherNotExists
+	}
+
+	students, total, err := ds.studentRepo.ListStudentsByTeacher(ctx, teacherDetails.ID, models.Page{Limit: page.Limit, Offset: page.Offset})
+	if err != nil {
+		return response, err
+	}
+
+	for _, student := range students {
+		response.Students = append(response.Students, studentSummary(student))
+	}
+	response.Total = total
+	return response, nil
+}
+
+//GetStudentEnrolments service retrieves every registration of a student with a teacher, oldest first,
+//including archived and deregistered ones.
+func (ds *directoryService) GetStudentEnrolments(ctx context.Context, email string) (dto.StudentEnrolmentsResponse, error) {
+	response := dto.StudentEnrolmentsResponse{
+		Student:    email,
+		Enrolments: []dto.EnrolmentResponse{},
+	}
+
+	student, err := ds.studentRepo.GetStudentByEmail(ctx, email)
+	if err != nil {
+		return response, err
+	}
+	if student == nil {
+		return response, errors.ErrStudentNotExists
+	}
+
+	enrolments, err := ds.teacherStudentRepo.GetEnrolmentHistory(ctx, student.ID)
+	if err != nil {
+		return response, err
+	}
+
+	for _, enrolment := range enrolments {
+		response.Enrolments = append(response.Enrolments, dto.EnrolmentResponse{
+			Teacher:        enrolment.TeacherEmail,
+			RegisteredAt:   enrolment.RegisteredAt,
+			ArchivedAt:     enrolment.ArchivedAt,
+			DeregisteredAt: enrolment.DeregisteredAt,
+		})
+	}
+	return response, nil
+}
+
+//UpdateTeacher service changes the profile fields of a teacher.
+func (ds *directoryService) UpdateTeacher(ctx context.Context, req dto.UpdateTeacherRequest) (dto.TeacherResponse, error) {
+	teacher, err := ds.teacherRepo.GetTeacherByEmail(ctx, req.Email)
+	if err != nil {
+		return dto.TeacherResponse{}, err
+	}
+	if teacher == nil {
+		return dto.TeacherResponse{}, errors.ErrTeacherNotExists
+	}
+
+	req.TeacherProfile.ApplyTo(teacher)
+	err = ds.teacherRepo.UpdateTeacherProfile(ctx, teacher)
+	if err != nil {
+		ds.logger.Error("UpdateTeacherProfile failed", zap.String("email", req.Email), zap.Error(err))
+		return dto.TeacherResponse{}, err
+	}
+	return teacherResponse(*teacher), nil
+}
+
+//UpdateStudent service changes the profile fields of a student. A student number can only belong to one student.
+func (ds *directoryService) UpdateStudent(ctx context.Context, req dto.UpdateStudentRequest) (dto.StudentResponse, error) {
+	var response dto.StudentResponse
+
+	student, err := ds.studentRepo.GetStudentByEmail(ctx, req.Email)
+	if err != nil {
+		return response, err
+	}
+	if student == nil {
+		return response, errors.ErrStudentNotExists
+	}
+
+	if req.ActingTeacher != "" {
+		err = ds.checkStudentsTeacher(ctx, req.ActingTeacher, student)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if req.StudentNumber != nil && *req.StudentNumber != "" {
+		owner, err := ds.studentRepo.GetStudentByNumber(ctx, *req.StudentNumber)
+		if err != nil {
+			return response, err
+		}
+		if owner != nil && owner.ID != student.ID {
+			return response, errors.ErrStudentNumberTaken
+		}
+	}
+
+	req.StudentProfile.ApplyTo(student)
+	err = ds.studentRepo.UpdateStudentProfile(ctx, student)
+	if err != nil {
+		ds.logger.Error("UpdateStudentProfile failed", zap.String("email", req.Email), zap.Error(err))
+		return response, err
+	}
+
+	return ds.GetStudent(ctx, student.Email)
+}
+
+//Return an error unless the student is registered with the teacher
+func (ds *directoryService) checkStudentsTeacher(ctx context.Context, teacher string, student *models.Student) error {
+	teacherDetails, err := ds.teacherRepo.GetTeacherByEmail(ctx, teacher)
+	if err != nil {
+		return err
+	}
+	if teacherDetails == nil {
+		return errors.ErrNotStudentsTeacher
+	}
+
+	registered, err := ds.teacherStudentRepo.IsStudentRegisteredForTeacher(ctx, teacherDetails.ID, student.ID)
+	if err != nil {
+		return err
+	}
+	if registered == nil {
+		return errors.ErrNotStudentsTeacher
+	}
+	return nil
+}
+
+func teacherResponse(teacher models.Teacher) dto.TeacherResponse {
+	return dto.TeacherResponse{
+		Email:         teacher.Email,
+		Name:          teacher.Name,
+		PreferredName: teacher.PreferredName,
+		Department:    teacher.Department,
+		Metadata:      teacher.Metadata,
+		CreatedAt:     teacher.CreatedAt,
+	}
+}
+
+func studentSummary(student models.Student) dto.StudentSummary {
+	return dto.StudentSummary{
+		Email:         student.Email,
+		Status:        string(student.Status),
+		Name:          student.Name,
+		PreferredName: student.PreferredName,
+		StudentNumber: student.StudentNumber,
+		GradeLevel:    student.GradeLevel,
+		Metadata:      student.Metadata,
+		CreatedAt:     student.CreatedAt,
+	}
+}
@@ -0,0 +1,92 @@
+package notification
+
+import (
+	"class-management/errors"
+	"class-management/internal/dto"
+	"class-management/internal/models"
+	"context"
+)
+
+type NotificationService interface {
+	TeacherNotifications(ctx context.Context, teacher string, page dto.PageRequest) (dto.TeacherNotificationsResponse, error)
+	GetNotification(ctx context.Context, id uint, actingTeacher string) (dto.NotificationResponse, error)
+}
+
+type notificationService struct {
+	teacherRepo      models.TeacherRepo
+	notificationRepo models.NotificationRepo
+}
+
+func NewNotificationService(teacherRepo models.TeacherRepo, notificationRepo models.NotificationRepo) NotificationService {
+	return &notificationService{
+		teacherRepo:      teacherRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+//TeacherNotifications service lists a page of the notifications a teacher has sent, newest first.
+func (ns *notificationService) TeacherNotifications(ctx context.Context, teacher string, page dto.PageRequest) (dto.TeacherNotificationsResponse, error) {
+	response := dto.TeacherNotificationsResponse{
+		Teacher:       teacher,
+		Notifications: []dto.NotificationSummary{},
+		PageResponse:  dto.PageResponse{Limit: page.Limit, Offset: page.Offset},
+	}
+
+	teacherDetails, err := ns.teacherRepo.GetTeacherByEmail(ctx, teacher)
+	if err != nil {
+		return response, err
+	}
+	if teacherDetails == nil {
+		return response, errors.ErrTeacherNotExists
+	}
+
+	notifications, total, err := ns.notificationRepo.GetNotificationsByTeacher(ctx, teacherDetails.ID, models.Page{Limit: page.Limit, Offset: page.Offset})
+	if err != nil {
+		return response, err
+	}
+
+	for _, notification := range notifications {
+		response.Notifications = append(response.Notifications, dto.NotificationSummary{
+			ID:             notification.ID,
+			Notification:   notification.Text,
+			RecipientCount: notification.RecipientCount,
+			CreatedAt:      notification.CreatedAt,
+		})
+	}
+	response.Total = total
+	return response, nil
+}
+
+//GetNotification service retrieves a notification with the students who received it. When actingTeacher is set,
+//another teacher's notification is reported as not existing, so its id gives nothing away.
+func (ns *notificationService) GetNotification(ctx context.Context, id uint, actingTeacher string) (dto.NotificationResponse, error) {
+	var response dto.NotificationResponse
+
+	notification, err := ns.notificationRepo.GetNotificationByID(ctx, id)
+	if err != nil {
+		return response, err
+	}
+	if notification == nil || (actingTeacher != "" && notification.Teacher.Email != actingTeacher) {
+		return response, errors.ErrNotificationNotExists
+	}
+
+	response = dto.NotificationResponse{
+		ID:           notification.ID,
+		Teacher:      notification.Teacher.Email,
+		Notification: notification.Text,
+		CreatedAt:    notification.CreatedAt,
+		Recipients:   []dto.NotificationRecipient{},
+	}
+	for _, recipient := range notification.Recipients {
+		responseRecipient := dto.NotificationRecipient{
+			Email:      recipient.Email,
+			Registered: recipient.Registered,
+			Mentioned:  recipient.Mentioned,
+		}
+		if recipient.Delivery != nil {
+			responseRecipient.DeliveryStatus = string(recipient.Delivery.Status)
+		}
+		response.Recipients = append(response.Recipients, responseRecipient)
+	}
+	return response, nil
+}
@@ -5,88 +5,231 @@ import (
 	"class-management/internal/dto"
 	"class-management/internal/models"
 	"class-management/internal/utils"
-	"log"
+	"context"
+	stderrors "errors"
 	"regexp"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 )
 
+//Name of the tracer of the spans started inside the service. They join the trace of ctx when the caller traces,
+//and cost nothing otherwise.
+const tracerName = "class-management/internal/service/teacher"
+
 type TeacherService interface {
-	RegisterStudents(dto.RegisterStudentsRequest) error
-	SuspendStudent(dto.SuspendRequest) error
-	CommonStudentsOfTeachers([]string) ([]string, error)
-	FetchStudentsForNotification(dto.FetchStudentsForNotificationRequest) ([]string, error)
-	RegisterTeachers(dto.RegisterTeachersRequest) error
+	RegisterStudents(context.Context, dto.RegisterStudentsRequest) (dto.RegistrationResponse, error)
+	DeregisterStudents(context.Context, dto.DeregisterStudentsRequest) error
+	SuspendStudent(context.Context, dto.SuspendRequest) error
+	UnsuspendStudent(context.Context, dto.UnsuspendRequest) error
+	ChangeStudentStatus(context.Context, dto.ChangeStudentStatusRequest) error
+	GraduateStudents(context.Context, dto.GraduateStudentsRequest) (dto.GraduateStudentsResponse, error)
+	CommonStudentsOfTeachers(context.Context, dto.CommonStudentsRequest) (dto.CommonStudentsResponse, error)
+	FetchStudentsForNotification(context.Context, dto.FetchStudentsForNotificationRequest) (dto.FetchStudentsForNotificationResponse, error)
+	RegisterTeachers(context.Context, dto.RegisterTeachersRequest) (dto.RegistrationResponse, error)
 }
 
 type teacherService struct {
 	teacherRepo        models.TeacherRepo
 	studentRepo        models.StudentRepo
 	teacherStudentRepo models.TeacherStudentRepo
+	classRepo          models.ClassRepo
+	unitOfWork         models.UnitOfWork
+	logger             *zap.Logger
 }
 
-func NewTeacherService(teacherRepo models.TeacherRepo, studentRepo models.StudentRepo, teacherStudentRepo models.TeacherStudentRepo) TeacherService {
+func NewTeacherService(teacherRepo models.TeacherRepo, studentRepo models.StudentRepo, teacherStudentRepo models.TeacherStudentRepo, classRepo models.ClassRepo, unitOfWork models.UnitOfWork, logger *zap.Logger) TeacherService {
 	return &teacherService{
 		teacherRepo:        teacherRepo,
 		studentRepo:        studentRepo,
 		teacherStudentRepo: teacherStudentRepo,
+		classRepo:          classRepo,
+		unitOfWork:         unitOfWork,
+		logger:             logger,
 	}
 }
 
 //RegisterStudents service for registering multiple students with a teacher. A student can also be registered to multiple teachers.
-func (ts *teacherService) RegisterStudents(req dto.RegisterStudentsRequest) error {
-	teacherDetails, err := ts.teacherRepo.GetTeacherByEmail(req.Teacher)
+//It reports the outcome for every email. In strict mode nothing is registered if any email is invalid.
+//In atomic mode all students are registered in one transaction, otherwise every student is registered in its own.
+func (ts *teacherService) RegisterStudents(ctx context.Context, req dto.RegisterStudentsRequest) (dto.RegistrationResponse, error) {
+	response := dto.RegistrationResponse{Results: []dto.RegistrationResult{}}
+
+	teacherDetails, err := ts.teacherRepo.GetTeacherByEmail(ctx, req.Teacher)
 	if err != nil {
-		return err
+		return response, err
 	}
 
 	if teacherDetails == nil {
-		return errors.ErrTeacherNotExists
+		return response, errors.ErrTeacherNotExists
 	}
 
-	for _, studentEmail := range req.Students {
-		if utils.IsEmailValid(studentEmail) {
-			tx := models.DB.Begin()
-			studentDetails, err := ts.studentRepo.GetStudentByEmail(studentEmail)
-			if err != nil {
-				log.Printf("GetStudentByEmail error for email: %s", studentEmail)
-				tx.Rollback()
-				return err
-			}
-			if studentDetails == nil {
-				studentObj := &models.Student{
-					Email:  studentEmail,
-					Status: "Active",
+	if req.Strict {
+		if invalid := invalidEmailResults(req.Students); len(invalid) > 0 {
+			response.Results = invalid
+			return response, errors.ErrInvalidEmailsInBatch
+		}
+	}
+
+	if req.Atomic {
+		err = ts.unitOfWork.Do(ctx, func(repos models.Repositories) error {
+			for _, studentEmail := range req.Students {
+				if !utils.IsEmailValid(studentEmail) {
+					ts.logger.Info("invalid email", zap.String("email", studentEmail))
+					response.Results = append(response.Results, invalidEmailResult(studentEmail))
+					continue
 				}
 
-				studentDetails, err = ts.studentRepo.CreateStudent(studentObj)
+				status, err := ts.registerStudent(ctx, repos, teacherDetails, studentEmail, req.Profiles[studentEmail])
 				if err != nil {
-					log.Printf("CreateStudent error for email: %s", studentEmail)
-					tx.Rollback()
 					return err
 				}
+				response.Results = append(response.Results, dto.RegistrationResult{Email: studentEmail, Status: status})
 			}
-
-			teacherStudentDetails, err := ts.teacherStudentRepo.IsStudentRegisteredForTeacher(teacherDetails.ID, studentDetails.ID)
-			if err != nil {
-				log.Printf("IsStudentRegisteredForTeacher error for email: %s", studentEmail)
-				tx.Rollback()
-				return err
+			return nil
+		})
+		if err != nil {
+			ts.logger.Error("registration rolled back", zap.String("teacher", req.Teacher), zap.Error(err))
+			//a request that ran out of time or was cancelled is reported as such
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return dto.RegistrationResponse{Results: []dto.RegistrationResult{}}, ctxErr
 			}
+			return dto.RegistrationResponse{Results: []dto.RegistrationResult{}}, errors.ErrRegistrationRolledBack
+		}
+		return response, nil
+	}
 
-			if teacherStudentDetails == nil {
-				teacherStudentObj := &models.TeacherStudent{
-					TeacherID: teacherDetails.ID,
-					StudentID: studentDetails.ID,
-				}
-				err := ts.teacherStudentRepo.CreateTeacherStudent(teacherStudentObj)
-				if err != nil {
-					log.Printf("CreateTeacherStudent error for email: %s", studentEmail)
-					tx.Rollback()
-					return err
-				}
-			}
-			tx.Commit()
-		} else {
-			log.Printf("Invalid email: %s", studentEmail)
+	for _, studentEmail := range req.Students {
+		if !utils.IsEmailValid(studentEmail) {
+			ts.logger.Info("invalid email", zap.String("email", studentEmail))
+			response.Results = append(response.Results, invalidEmailResult(studentEmail))
+			continue
+		}
+
+		var status dto.RegistrationStatus
+		err := ts.unitOfWork.Do(ctx, func(repos models.Repositories) error {
+			var err error
+			status, err = ts.registerStudent(ctx, repos, teacherDetails, studentEmail, req.Profiles[studentEmail])
+			return err
+		})
+		if err != nil {
+			response.Results = append(response.Results, failedResult(studentEmail))
+			continue
+		}
+		response.Results = append(response.Results, dto.RegistrationResult{Email: studentEmail, Status: status})
+	}
+
+	return response, nil
+}
+
+//Create the student with the given profile if needed and link it with the teacher using the given repositories
+func (ts *teacherService) registerStudent(ctx context.Context, repos models.Repositories, teacherDetails *models.Teacher, studentEmail string, profile dto.StudentProfile) (dto.RegistrationStatus, error) {
+	status := dto.RegistrationLinked
+
+	studentDetails, err := repos.Students.GetStudentByEmail(ctx, studentEmail)
+	if err != nil {
+		ts.logger.Error("GetStudentByEmail failed", zap.String("email", studentEmail), zap.Error(err))
+		return "", err
+	}
+	if studentDetails == nil {
+		studentObj := &models.Student{
+			Email:  studentEmail,
+			Status: models.StatusActive,
+		}
+		profile.ApplyTo(studentObj)
+
+		studentDetails, err = repos.Students.CreateStudent(ctx, studentObj)
+		if err != nil {
+			ts.logger.Error("CreateStudent failed", zap.String("email", studentEmail), zap.Error(err))
+			return "", err
+		}
+		status = dto.RegistrationCreated
+	}
+
+	teacherStudentDetails, err := repos.TeacherStudents.IsStudentRegisteredForTeacher(ctx, teacherDetails.ID, studentDetails.ID)
+	if err != nil {
+		ts.logger.Error("IsStudentRegisteredForTeacher failed", zap.String("email", studentEmail), zap.Error(err))
+		return "", err
+	}
+
+	if teacherStudentDetails != nil {
+		return dto.RegistrationAlreadyRegistered, nil
+	}
+
+	teacherStudentObj := &models.TeacherStudent{
+		TeacherID: teacherDetails.ID,
+		StudentID: studentDetails.ID,
+	}
+	err = repos.TeacherStudents.CreateTeacherStudent(ctx, teacherStudentObj)
+	if err != nil {
+		ts.logger.Error("CreateTeacherStudent failed", zap.String("email", studentEmail), zap.Error(err))
+		return "", err
+	}
+
+	return status, nil
+}
+
+//Collect results for all invalid emails of a batch
+func invalidEmailResults(emails []string) []dto.RegistrationResult {
+	results := []dto.RegistrationResult{}
+	for _, email := range emails {
+		if !utils.IsEmailValid(email) {
+			results = append(results, invalidEmailResult(email))
+		}
+	}
+	return results
+}
+
+func invalidEmailResult(email string) dto.RegistrationResult {
+	return dto.RegistrationResult{Email: email, Status: dto.RegistrationInvalid, Reason: "email address is not valid"}
+}
+
+func failedResult(email string) dto.RegistrationResult {
+	return dto.RegistrationResult{Email: email, Status: dto.RegistrationFailed, Reason: "email could not be saved, please try again"}
+}
+
+//DeregisterStudents service removes students from a teacher. Students who are not registered with the teacher are ignored.
+func (ts *teacherService) DeregisterStudents(ctx context.Context, req dto.DeregisterStudentsRequest) error {
+	teacherDetails, err := ts.teacherRepo.GetTeacherByEmail(ctx, req.Teacher)
+	if err != nil {
+		return err
+	}
+
+	if teacherDetails == nil {
+		return errors.ErrTeacherNotExists
+	}
+
+	for _, studentEmail := range req.Students {
+		if !utils.IsEmailValid(studentEmail) {
+			ts.logger.Info("invalid email", zap.String("email", studentEmail))
+			continue
+		}
+
+		studentDetails, err := ts.studentRepo.GetStudentByEmail(ctx, studentEmail)
+		if err != nil {
+			ts.logger.Error("GetStudentByEmail failed", zap.String("email", studentEmail), zap.Error(err))
+			return err
+		}
+		if studentDetails == nil {
+			ts.logger.Info("student not found", zap.String("email", studentEmail))
+			continue
+		}
+
+		teacherStudentDetails, err := ts.teacherStudentRepo.IsStudentRegisteredForTeacher(ctx, teacherDetails.ID, studentDetails.ID)
+		if err != nil {
+			ts.logger.Error("IsStudentRegisteredForTeacher failed", zap.String("email", studentEmail), zap.Error(err))
+			return err
+		}
+		if teacherStudentDetails == nil {
+			continue
+		}
+
+		err = ts.teacherStudentRepo.DeleteTeacherStudent(ctx, teacherDetails.ID, studentDetails.ID)
+		if err != nil {
+			ts.logger.Error("DeleteTeacherStudent failed", zap.String("email", studentEmail), zap.Error(err))
+			return err
 		}
 	}
 
@@ -94,8 +237,28 @@ func (ts *teacherService) RegisterStudents(req dto.RegisterStudentsRequest) erro
 }
 
 //SuspendStudent service to suspend a student.
-func (ts *teacherService) SuspendStudent(req dto.SuspendRequest) error {
-	studentDetails, err := ts.studentRepo.GetStudentByEmail(req.Student)
+func (ts *teacherService) SuspendStudent(ctx context.Context, req dto.SuspendRequest) error {
+	return ts.changeStudentStatus(ctx, req.Student, models.StatusSuspended, req.ActingTeacher, req.ChangedBy, req.Reason)
+}
+
+//UnsuspendStudent service to reinstate a suspended student.
+func (ts *teacherService) UnsuspendStudent(ctx context.Context, req dto.UnsuspendRequest) error {
+	return ts.changeStudentStatus(ctx, req.Student, models.StatusActive, req.ActingTeacher, req.ChangedBy, req.Reason)
+}
+
+//ChangeStudentStatus service moves a student to any status allowed from its current one.
+func (ts *teacherService) ChangeStudentStatus(ctx context.Context, req dto.ChangeStudentStatusRequest) error {
+	status := models.StatusStudent(req.Status)
+	if !status.IsValid() {
+		return errors.ErrInvalidStudentStatus
+	}
+	return ts.changeStudentStatus(ctx, req.Student, status, req.ActingTeacher, req.ChangedBy, req.Reason)
+}
+
+//Validate the transition against the student's current status and record who made it and why.
+//When actingTeacher is set the student must be registered with that teacher.
+func (ts *teacherService) changeStudentStatus(ctx context.Context, email string, next models.StatusStudent, actingTeacher string, changedBy string, reason string) error {
+	studentDetails, err := ts.studentRepo.GetStudentByEmail(ctx, email)
 	if err != nil {
 		return err
 	}
@@ -103,114 +266,409 @@ func (ts *teacherService) SuspendStudent(req dto.SuspendRequest) error {
 		return errors.ErrStudentNotExists
 	}
 
-	studentDetails.Status = models.StatusSuspended
+	if actingTeacher != "" {
+		err = ts.checkStudentsTeacher(ctx, actingTeacher, studentDetails)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !studentDetails.Status.CanTransitionTo(next) {
+		return errors.ErrInvalidStatusTransition
+	}
+
+	change := &models.StudentStatusChange{
+		FromStatus: studentDetails.Status,
+		ToStatus:   next,
+		ChangedBy:  changedBy,
+		Reason:     reason,
+	}
+	studentDetails.Status = next
+
+	//graduation also closes the student's teacher registrations
+	if next == models.StatusGraduated {
+		err = ts.studentRepo.GraduateStudent(ctx, studentDetails, change)
+	} else {
+		err = ts.studentRepo.UpdateStudentStatus(ctx, studentDetails, change)
+	}
+	if stderrors.Is(err, models.ErrStatusChanged) {
+		return errors.ErrStudentStatusChanged
+	}
+	return err
+}
 
-	err = ts.studentRepo.UpdateStudentStatus(studentDetails)
+//Return an error unless the student is registered with the teacher
+func (ts *teacherService) checkStudentsTeacher(ctx context.Context, teacher string, studentDetails *models.Student) error {
+	teacherDetails, err := ts.teacherRepo.GetTeacherByEmail(ctx, teacher)
 	if err != nil {
 		return err
 	}
+	if teacherDetails == nil {
+		return errors.ErrNotStudentsTeacher
+	}
+
+	registered, err := ts.teacherStudentRepo.IsStudentRegisteredForTeacher(ctx, teacherDetails.ID, studentDetails.ID)
+	if err != nil {
+		return err
+	}
+	if registered == nil {
+		return errors.ErrNotStudentsTeacher
+	}
 	return nil
 }
 
+//GraduateStudents service graduates the given students, all active students of a teacher and/or of a grade level.
+//Students that cannot graduate are reported back with a reason instead of failing the whole batch.
+func (ts *teacherService) GraduateStudents(ctx context.Context, req dto.GraduateStudentsRequest) (dto.GraduateStudentsResponse, error) {
+	response := dto.GraduateStudentsResponse{
+		Graduated: []string{},
+		Skipped:   []dto.SkippedGraduation{},
+	}
+
+	students := req.Students
+	if req.Teacher != "" {
+		teacherDetails, err := ts.teacherRepo.GetTeacherByEmail(ctx, req.Teacher)
+		if err != nil {
+			return response, err
+		}
+		if teacherDetails == nil {
+			return response, errors.ErrTeacherNotExists
+		}
+
+		registeredStudents, err := ts.teacherStudentRepo.GetAllStudentsByTeacher(ctx, req.Teacher, false)
+		if err != nil {
+			return response, err
+		}
+		for _, student := range registeredStudents {
+			students = append(students, student.Email)
+		}
+	}
+	if req.GradeLevel != "" {
+		cohort, err := ts.studentRepo.GetStudentsByGradeLevel(ctx, req.GradeLevel, false)
+		if err != nil {
+			return response, err
+		}
+		for _, student := range cohort {
+			students = append(students, student.Email)
+		}
+	}
+
+	processed := make(map[string]bool)
+	for _, studentEmail := range students {
+		if processed[studentEmail] {
+			continue
+		}
+		processed[studentEmail] = true
+
+		if !utils.IsEmailValid(studentEmail) {
+			response.Skipped = append(response.Skipped, dto.SkippedGraduation{Student: studentEmail, Reason: errors.ErrInvalidStudentEmail.Message})
+			continue
+		}
+
+		err := ts.changeStudentStatus(ctx, studentEmail, models.StatusGraduated, req.ActingTeacher, req.ChangedBy, req.Reason)
+		if apiErr, ok := err.(errors.ApiError); ok {
+			response.Skipped = append(response.Skipped, dto.SkippedGraduation{Student: studentEmail, Reason: apiErr.Message})
+			continue
+		}
+		if err != nil {
+			ts.logger.Error("GraduateStudent failed", zap.String("email", studentEmail), zap.Error(err))
+			return response, err
+		}
+		response.Graduated = append(response.Graduated, studentEmail)
+	}
+
+	return response, nil
+}
+
 // CommonStudentsOfTeachers service retrieves a list of students common to a given list of teachers.
-func (ts *teacherService) CommonStudentsOfTeachers(teachers []string) ([]string, error) {
-	//validate given teachers
-	for _, teacher := range teachers {
-		teacherDetails, err := ts.teacherRepo.GetTeacherByEmail(teacher)
+func (ts *teacherService) CommonStudentsOfTeachers(ctx context.Context, req dto.CommonStudentsRequest) (dto.CommonStudentsResponse, error) {
+	response := dto.CommonStudentsResponse{
+		Students: []string{},
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	}
+
+	//validate given and excluded teachers
+	for _, teacher := range append(append([]string{}, req.Teachers...), req.ExcludeTeachers...) {
+		teacherDetails, err := ts.teacherRepo.GetTeacherByEmail(ctx, teacher)
 		if err != nil {
-			return nil, err
+			return response, err
 		}
 		if teacherDetails == nil {
-			return nil, errors.ErrTeacherNotExists
+			return response, errors.ErrTeacherNotExists
 		}
 	}
 
-	students, err := ts.teacherStudentRepo.GetCommonStudents(teachers)
+	if req.ClassID != 0 {
+		classDetails, err := ts.classRepo.GetClassByID(ctx, req.ClassID)
+		if err != nil {
+			return response, err
+		}
+		if classDetails == nil {
+			return response, errors.ErrClassNotExists
+		}
+	}
+
+	statuses := make([]models.StatusStudent, 0, len(req.Statuses))
+	for _, status := range req.Statuses {
+		statuses = append(statuses, models.StatusStudent(status))
+	}
+
+	//a student must be registered with every teacher, with one of them or with at least the given number of them
+	minTeachers := len(req.Teachers)
+	switch req.Mode {
+	case dto.CommonStudentsModeAny:
+		minTeachers = 1
+	case dto.CommonStudentsModeAtLeast:
+		minTeachers = req.MinTeachers
+	}
+
+	students, total, err := ts.teacherStudentRepo.GetCommonStudents(ctx, models.CommonStudentsFilter{
+		Teachers:         req.Teachers,
+		MinTeachers:      minTeachers,
+		ExcludeTeachers:  req.ExcludeTeachers,
+		IncludeGraduated: req.IncludeGraduated,
+		ClassID:          req.ClassID,
+		Statuses:         statuses,
+		Sort:             req.Sort,
+		Descending:       req.Descending,
+		Limit:            req.Limit,
+		Offset:           req.Offset,
+	})
 	if err != nil {
-		return nil, err
+		return response, err
 	}
-	return students, nil
+	if students != nil {
+		response.Students = students
+	}
+	response.Total = total
+	return response, nil
 }
 
 //FetchStudentsForNotification service retrieve a list of students who can receive a given notification.
-func (ts *teacherService) FetchStudentsForNotification(req dto.FetchStudentsForNotificationRequest) ([]string, error) {
-	teacherDetails, err := ts.teacherRepo.GetTeacherByEmail(req.Teacher)
+//When a class is given, the class students are notified instead of the students registered with the teacher.
+//The notification is stored together with its recipients.
+func (ts *teacherService) FetchStudentsForNotification(ctx context.Context, req dto.FetchStudentsForNotificationRequest) (dto.FetchStudentsForNotificationResponse, error) {
+	var response dto.FetchStudentsForNotificationResponse
+
+	teacherDetails, err := ts.teacherRepo.GetTeacherByEmail(ctx, req.Teacher)
 	if err != nil {
-		return nil, err
+		return response, err
 	}
 
 	if teacherDetails == nil {
-		return nil, errors.ErrTeacherNotExists
+		return response, errors.ErrTeacherNotExists
+	}
+
+	//fetch students mentioned in the notification, dropping the ones who can't receive it.
+	//Parsing gets its own span as long notifications make the regex a visible part of the request.
+	_, parseSpan := trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName).Start(ctx, "parse mentions")
+	mentions := fetchMentionedStudents(req.Notification)
+	parseSpan.SetAttributes(attribute.Int("notification_length", len(req.Notification)), attribute.Int("mentions", len(mentions)))
+	parseSpan.End()
+
+	namedStudents, droppedMentions, err := ts.resolveMentions(ctx, mentions, req.IncludeGraduated)
+	if err != nil {
+		return response, err
+	}
+
+	var registeredStudent []models.Student
+	if req.ClassID != 0 {
+		registeredStudent, err = ts.classStudentsForNotification(ctx, teacherDetails, req.ClassID, req.IncludeGraduated)
+	} else {
+		registeredStudent, err = ts.teacherStudentRepo.GetAllStudentsByTeacher(ctx, req.Teacher, req.IncludeGraduated)
+	}
+	if err != nil {
+		return response, err
+	}
+
+	notification := &models.Notification{
+		TeacherID:  teacherDetails.ID,
+		Text:       req.Notification,
+		Recipients: filterStudents(namedStudents, registeredStudent),
+	}
+
+	//store the notification and queue its deliveries in one transaction, the delivery worker sends them later
+	err = ts.unitOfWork.Do(ctx, func(repos models.Repositories) error {
+		if err := repos.Notifications.CreateNotification(ctx, notification); err != nil {
+			ts.logger.Error("CreateNotification failed", zap.String("teacher", req.Teacher), zap.Error(err))
+			return err
+		}
+
+		now := time.Now()
+		deliveries := make([]models.NotificationDelivery, 0, len(notification.Recipients))
+		for _, recipient := range notification.Recipients {
+			deliveries = append(deliveries, models.NotificationDelivery{
+				NotificationID: notification.ID,
+				RecipientID:    recipient.ID,
+				Email:          recipient.Email,
+				Status:         models.DeliveryPending,
+				NextAttemptAt:  now,
+			})
+		}
+		return repos.Deliveries.CreateDeliveries(ctx, deliveries)
+	})
+	if err != nil {
+		return response, err
+	}
+
+	response = dto.FetchStudentsForNotificationResponse{
+		NotificationID:  notification.ID,
+		Recipients:      []string{},
+		DroppedMentions: droppedMentions,
+	}
+	for _, recipient := range notification.Recipients {
+		response.Recipients = append(response.Recipients, recipient.Email)
+	}
+	return response, nil
+}
+
+//Get students of a class taught by the teacher who can receive notifications
+func (ts *teacherService) classStudentsForNotification(ctx context.Context, teacherDetails *models.Teacher, classID uint, includeGraduated bool) ([]models.Student, error) {
+	classDetails, err := ts.classRepo.GetClassByID(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+	if classDetails == nil {
+		return nil, errors.ErrClassNotExists
 	}
 
-	//fetch students mentioned in the notification
-	namedStudents := fetchMentionedStudents(req.Notification)
+	assigned, err := ts.classRepo.IsTeacherAssignedToClass(ctx, classDetails.ID, teacherDetails.ID)
+	if err != nil {
+		return nil, err
+	}
+	if assigned == nil {
+		return nil, errors.ErrTeacherNotInClass
+	}
 
-	registeredStudent, err := ts.teacherStudentRepo.GetAllStudentsByTeacher(req.Teacher)
+	classStudents, err := ts.classRepo.GetClassStudents(ctx, classDetails.ID, includeGraduated)
 	if err != nil {
 		return nil, err
 	}
 
-	allStudents := filterStudents(namedStudents, registeredStudent)
-	return allStudents, nil
+	//suspended students don't receive notifications
+	students := []models.Student{}
+	for _, student := range classStudents {
+		if student.Status != models.StatusSuspended {
+			students = append(students, student)
+		}
+	}
+	return students, nil
 }
 
-//Retrieve email ids from given text
+//A mention is an @ directly followed by an email, at the start of the text or after whitespace
+var mentionRegex = regexp.MustCompile(`(?:^|\s)@([A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+
+//Retrieve the unique email ids @mentioned in given text, in order of appearance
 func fetchMentionedStudents(text string) []string {
-	regexPattern := `\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`
-	regexRule := regexp.MustCompile(regexPattern)
+	emails := []string{}
+	seen := make(map[string]bool)
 
-	//fetch all email address from notification text
-	emails := regexRule.FindAllString(text, -1)
+	for _, match := range mentionRegex.FindAllStringSubmatch(text, -1) {
+		email := match[1]
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+		emails = append(emails, email)
+	}
 	return emails
-
 }
 
-//filter unique students from notification text and registered students of a teacher
-func filterStudents(namedStudents []string, registeredStudent []models.Student) []string {
-	studentSet := make(map[string]bool)
+//Keep the mentioned students who exist and can receive notifications, and report why the others were dropped
+func (ts *teacherService) resolveMentions(ctx context.Context, mentions []string, includeGraduated bool) ([]string, []dto.DroppedMention, error) {
 	students := []string{}
+	dropped := []dto.DroppedMention{}
 
-	for _, student := range namedStudents {
-		studentSet[student] = true
+	for _, email := range mentions {
+		student, err := ts.studentRepo.GetStudentByEmail(ctx, email)
+		if err != nil {
+			ts.logger.Error("GetStudentByEmail failed", zap.String("email", email), zap.Error(err))
+			return nil, nil, err
+		}
+
+		switch {
+		case student == nil:
+			dropped = append(dropped, dto.DroppedMention{Email: email, Reason: dto.MentionNotFound})
+		case student.Status == models.StatusSuspended:
+			dropped = append(dropped, dto.DroppedMention{Email: email, Reason: dto.MentionSuspended})
+		case student.Status == models.StatusGraduated && !includeGraduated:
+			dropped = append(dropped, dto.DroppedMention{Email: email, Reason: dto.MentionGraduated})
+		default:
+			students = append(students, student.Email)
+		}
 	}
+	return students, dropped, nil
+}
+
+//filter unique students from registered students of a teacher and notification text, keeping where each of them came from
+func filterStudents(namedStudents []string, registeredStudent []models.Student) []models.NotificationRecipient {
+	recipients := []models.NotificationRecipient{}
+	positions := make(map[string]int)
 
 	for _, student := range registeredStudent {
-		studentSet[student.Email] = true
+		if _, ok := positions[student.Email]; ok {
+			continue
+		}
+		positions[student.Email] = len(recipients)
+		recipients = append(recipients, models.NotificationRecipient{Email: student.Email, Registered: true})
 	}
 
-	for student := range studentSet {
-		students = append(students, student)
+	for _, student := range namedStudents {
+		if position, ok := positions[student]; ok {
+			recipients[position].Mentioned = true
+			continue
+		}
+		positions[student] = len(recipients)
+		recipients = append(recipients, models.NotificationRecipient{Email: student, Mentioned: true})
 	}
 
-	return students
-
+	return recipients
 }
 
 //RegisterTeachers service registers single or multiple teachers.
-func (ts *teacherService) RegisterTeachers(req dto.RegisterTeachersRequest) error {
+//It reports the outcome for every email. In strict mode nothing is registered if any email is invalid.
+func (ts *teacherService) RegisterTeachers(ctx context.Context, req dto.RegisterTeachersRequest) (dto.RegistrationResponse, error) {
+	response := dto.RegistrationResponse{Results: []dto.RegistrationResult{}}
+
+	if req.Strict {
+		if invalid := invalidEmailResults(req.Teachers); len(invalid) > 0 {
+			response.Results = invalid
+			return response, errors.ErrInvalidEmailsInBatch
+		}
+	}
 
 	for _, email := range req.Teachers {
-		if utils.IsEmailValid(email) {
-			teacherDetails, err := ts.teacherRepo.GetTeacherByEmail(email)
-			if err != nil {
-				return err
-			}
+		if !utils.IsEmailValid(email) {
+			ts.logger.Info("invalid email", zap.String("email", email))
+			response.Results = append(response.Results, invalidEmailResult(email))
+			continue
+		}
 
-			if teacherDetails == nil {
-				teacherObj := &models.Teacher{
-					Email: email,
-				}
-				_, err := ts.teacherRepo.CreateTeacher(teacherObj)
-				if err != nil {
-					return err
-				}
-			}
+		teacherDetails, err := ts.teacherRepo.GetTeacherByEmail(ctx, email)
+		if err != nil {
+			ts.logger.Error("GetTeacherByEmail failed", zap.String("email", email), zap.Error(err))
+			response.Results = append(response.Results, failedResult(email))
+			continue
+		}
+
+		if teacherDetails != nil {
+			response.Results = append(response.Results, dto.RegistrationResult{Email: email, Status: dto.RegistrationAlreadyRegistered})
+			continue
+		}
 
-		} else {
-			log.Printf("Invalid email: %s", email)
+		teacherObj := &models.Teacher{
+			Email: email,
 		}
+		req.Profiles[email].ApplyTo(teacherObj)
+		_, err = ts.teacherRepo.CreateTeacher(ctx, teacherObj)
+		if err != nil {
+			ts.logger.Error("CreateTeacher failed", zap.String("email", email), zap.Error(err))
+			response.Results = append(response.Results, failedResult(email))
+			continue
+		}
+		response.Results = append(response.Results, dto.RegistrationResult{Email: email, Status: dto.RegistrationCreated})
 	}
 
-	return nil
+	return response, nil
 }
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"bytes"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/class"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestAssignClassTeachers(t *testing.T) {
+	// Create a new instance of the class service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	teacherStudentRepo := &mocks.MockTeacherStudentsRepo{}
+	classRepo := &mocks.MockClassRepo{}
+	notificationRepo := &mocks.MockNotificationRepo{}
+	unitOfWork := mocks.NewMockUnitOfWork(teacherRepo, studentRepo, teacherStudentRepo, classRepo, notificationRepo)
+	classService := class.NewClassService(classRepo, teacherRepo, unitOfWork, zap.NewNop())
+	classHandler := handler.NewClassHandler(classService)
+
+	// Test case: Only teachers not yet assigned are added
+	t.Run("AssignTeachers_Success", func(t *testing.T) {
+		teacherRepo.TeacherByEmailFn = func(_ context.Context, email string) (*models.Teacher, error) {
+			if email == "teacherken@gmail.com" {
+				return &models.Teacher{ID: 1, Email: email}, nil
+			}
+			return &models.Teacher{ID: 2, Email: email}, nil
+		}
+		classRepo.IsTeacherAssignedToClassFn = func(_ context.Context, classID uint, teacherID uint) (*models.ClassTeacher, error) {
+			if teacherID == 1 {
+				return &models.ClassTeacher{ID: 1, ClassID: classID, TeacherID: teacherID}, nil
+			}
+			return nil, nil
+		}
+		var assigned []uint
+		classRepo.AssignTeacherFn = func(_ context.Context, classTeacher *models.ClassTeacher) error {
+			assigned = append(assigned, classTeacher.TeacherID)
+			return nil
+		}
+
+		// Prepare the request payload
+		payload := []byte(`{"teachers": ["teacherken@gmail.com", "teacherjoe@gmail.com"]}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/classes/1/teachers", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(classHandler.AssignClassTeachers)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusNoContent {
+			t.Errorf("Expected status code %d, but got %d", http.StatusNoContent, rr.Code)
+		}
+		if len(assigned) != 1 || assigned[0] != 2 {
+			t.Errorf("Expected only teacher 2 to be assigned, but got %v", assigned)
+		}
+	})
+
+	// Test case: Unknown teacher
+	t.Run("AssignUnknownTeacher_NotFound", func(t *testing.T) {
+		teacherRepo.TeacherByEmailFn = func(_ context.Context, email string) (*models.Teacher, error) {
+			return nil, nil
+		}
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/classes/1/teachers", bytes.NewBuffer([]byte(`{"teachers": ["unknown@gmail.com"]}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(classHandler.AssignClassTeachers)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d, but got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"bytes"
+	"class-management/internal/auth"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/class"
+	"class-management/internal/service/teacher"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestAuthentication(t *testing.T) {
+	secret := "test-secret"
+	authenticator := auth.NewAuthenticator(map[string]auth.Identity{
+		"ken-key":   {Email: "teacherken@gmail.com", Role: auth.RoleTeacher},
+		"admin-key": {Role: auth.RoleAdmin},
+	}, secret)
+
+	// Create a handler that echoes the resolved identity
+	var resolved auth.Identity
+	protected := auth.Middleware(authenticator)(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		resolved, _ = auth.IdentityFromContext(request.Context())
+		writer.WriteHeader(http.StatusOK)
+	}))
+
+	kenToken, err := auth.IssueToken(secret, auth.Identity{Email: "teacherken@gmail.com", Role: auth.RoleTeacher}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiredToken, err := auth.IssueToken(secret, auth.Identity{Email: "teacherken@gmail.com", Role: auth.RoleTeacher}, -time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forgedToken, err := auth.IssueToken("other-secret", auth.Identity{Role: auth.RoleAdmin}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		header        string
+		value         string
+		expectedCode  int
+		expectedEmail string
+	}{
+		{"NoCredential_Unauthorized", "", "", http.StatusUnauthorized, ""},
+		{"TeacherAPIKey_Success", "X-API-Key", "ken-key", http.StatusOK, "teacherken@gmail.com"},
+		{"UnknownAPIKey_Unauthorized", "X-API-Key", "wrong-key", http.StatusUnauthorized, ""},
+		{"BearerToken_Success", "Authorization", "Bearer " + kenToken, http.StatusOK, "teacherken@gmail.com"},
+		{"ExpiredToken_Unauthorized", "Authorization", "Bearer " + expiredToken, http.StatusUnauthorized, ""},
+		{"ForgedToken_Unauthorized", "Authorization", "Bearer " + forgedToken, http.StatusUnauthorized, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resolved = auth.Identity{}
+
+			// Create a new HTTP request
+			req, err := http.NewRequest("GET", "/api/commonstudents", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.header != "" {
+				req.Header.Set(tc.header, tc.value)
+			}
+
+			// Create a new HTTP test recorder
+			rr := httptest.NewRecorder()
+
+			// Handle the request
+			protected.ServeHTTP(rr, req)
+
+			// Check the response status code and identity
+			if rr.Code != tc.expectedCode {
+				t.Errorf("Expected status code %d, but got %d", tc.expectedCode, rr.Code)
+			}
+			if resolved.Email != tc.expectedEmail {
+				t.Errorf("Expected identity %q, but got %q", tc.expectedEmail, resolved.Email)
+			}
+		})
+	}
+}
+
+func TestTeacherAuthorization(t *testing.T) {
+	// Create a new instance of the teacher service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	teacherStudentRepo := &mocks.MockTeacherStudentsRepo{}
+	classRepo := &mocks.MockClassRepo{}
+	notificationRepo := &mocks.MockNotificationRepo{}
+	unitOfWork := mocks.NewMockUnitOfWork(teacherRepo, studentRepo, teacherStudentRepo, classRepo, notificationRepo)
+	teacherService := teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo, classRepo, unitOfWork, zap.NewNop())
+	teacherHandler := handler.NewTeacherHandler(teacherService)
+
+	ken := auth.Identity{Email: "teacherken@gmail.com", Role: auth.RoleTeacher}
+
+	tests := []struct {
+		name         string
+		handler      http.HandlerFunc
+		url          string
+		body         string
+		expectedCode int
+	}{
+		{"RegisterOwnStudents_Success", asIdentity(teacherHandler.RegisterStudents, ken), "/api/register", `{"teacher": "teacherken@gmail.com", "students": ["studentjon@gmail.com"]}`, http.StatusOK},
+		{"RegisterForOtherTeacher_Forbidden", asIdentity(teacherHandler.RegisterStudents, ken), "/api/register", `{"teacher": "teacherjoe@gmail.com", "students": ["studentjon@gmail.com"]}`, http.StatusForbidden},
+		{"NotifyAsOtherTeacher_Forbidden", asIdentity(teacherHandler.FetchStudentsForNotification, ken), "/api/retrievefornotifications", `{"teacher": "teacherjoe@gmail.com", "notification": "Hello"}`, http.StatusForbidden},
+		{"NotifyWithoutIdentity_Unauthorized", teacherHandler.FetchStudentsForNotification, "/api/retrievefornotifications", `{"teacher": "teacherken@gmail.com", "notification": "Hello"}`, http.StatusUnauthorized},
+		{"AdminNotifiesForAnyTeacher_Success", asAdmin(teacherHandler.FetchStudentsForNotification), "/api/retrievefornotifications", `{"teacher": "teacherjoe@gmail.com", "notification": "Hello"}`, http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Create a new HTTP request
+			req, err := http.NewRequest("POST", tc.url, bytes.NewBufferString(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Create a new HTTP test recorder
+			rr := httptest.NewRecorder()
+
+			// Handle the request
+			tc.handler.ServeHTTP(rr, req)
+
+			// Check the response status code
+			if rr.Code != tc.expectedCode {
+				t.Errorf("Expected status code %d, but got %d", tc.expectedCode, rr.Code)
+			}
+		})
+	}
+}
+
+func TestStudentAndClassAuthorization(t *testing.T) {
+	// Create the teacher and class services, studentjon is the only student registered with a teacher
+	// and class 1 the only class a teacher is assigned to
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{GetStudentByEmailFn: func(_ context.Context, email string) (*models.Student, error) {
+		if email == "studentjon@gmail.com" {
+			return &models.Student{ID: 1, Email: email, Status: models.StatusActive}, nil
+		}
+		return &models.Student{ID: 2, Email: email, Status: models.StatusActive}, nil
+	}}
+	teacherStudentRepo := &mocks.MockTeacherStudentsRepo{IsStudentRegisteredForTeacherFn: func(_ context.Context, teacherID uint, studentID uint) (*models.TeacherStudent, error) {
+		if studentID != 1 {
+			return nil, nil
+		}
+		return &models.TeacherStudent{ID: 1, TeacherID: teacherID, StudentID: studentID}, nil
+	}}
+	classRepo := &mocks.MockClassRepo{IsTeacherAssignedToClassFn: func(_ context.Context, classID uint, teacherID uint) (*models.ClassTeacher, error) {
+		if classID != 1 {
+			return nil, nil
+		}
+		return &models.ClassTeacher{ID: 1, ClassID: classID, TeacherID: teacherID}, nil
+	}}
+	unitOfWork := mocks.NewMockUnitOfWork(teacherRepo, studentRepo, teacherStudentRepo, classRepo, &mocks.MockNotificationRepo{})
+	teacherHandler := handler.NewTeacherHandler(teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo, classRepo, unitOfWork, zap.NewNop()))
+	classHandler := handler.NewClassHandler(class.NewClassService(classRepo, teacherRepo, unitOfWork, zap.NewNop()))
+
+	ken := auth.Identity{Email: "teacherken@gmail.com", Role: auth.RoleTeacher}
+
+	tests := []struct {
+		name         string
+		handler      http.HandlerFunc
+		vars         map[string]string
+		body         string
+		expectedCode int
+	}{
+		{"SuspendOwnStudent_Success", asIdentity(teacherHandler.SuspendStudent, ken), nil, `{"student": "studentjon@gmail.com"}`, http.StatusNoContent},
+		{"SuspendOtherStudent_Forbidden", asIdentity(teacherHandler.SuspendStudent, ken), nil, `{"student": "studentjoe@gmail.com"}`, http.StatusForbidden},
+		{"AdminSuspendsAnyStudent_Success", asAdmin(teacherHandler.SuspendStudent), nil, `{"student": "studentjoe@gmail.com"}`, http.StatusNoContent},
+		{"SuspendWithoutIdentity_Unauthorized", teacherHandler.SuspendStudent, nil, `{"student": "studentjon@gmail.com"}`, http.StatusUnauthorized},
+		{"UnsuspendOtherStudent_Forbidden", asIdentity(teacherHandler.UnsuspendStudent, ken), nil, `{"student": "studentjoe@gmail.com"}`, http.StatusForbidden},
+		{"ChangeOtherStudentStatus_Forbidden", asIdentity(teacherHandler.ChangeStudentStatus, ken), map[string]string{"email": "studentjoe@gmail.com"}, `{"status": "GRADUATED"}`, http.StatusForbidden},
+		{"GraduateOtherTeachersStudents_Forbidden", asIdentity(teacherHandler.GraduateStudents, ken), nil, `{"teacher": "teacherjoe@gmail.com"}`, http.StatusForbidden},
+		{"GraduateOwnStudents_Success", asIdentity(teacherHandler.GraduateStudents, ken), nil, `{"teacher": "teacherken@gmail.com"}`, http.StatusOK},
+		{"RegisterTeachers_AdminRequired", asIdentity(teacherHandler.RegisterTeachers, ken), nil, `{"teachers": ["teacherjoe@gmail.com"]}`, http.StatusForbidden},
+		{"CreateClass_AdminRequired", asIdentity(classHandler.CreateClass, ken), nil, `{"name": "Math 3B", "teachers": ["teacherken@gmail.com"]}`, http.StatusForbidden},
+		{"AssignClassTeachers_AdminRequired", asIdentity(classHandler.AssignClassTeachers, ken), map[string]string{"id": "2"}, `{"teachers": ["teacherken@gmail.com"]}`, http.StatusForbidden},
+		{"EnrolInOwnClass_Success", asIdentity(classHandler.EnrolClassStudents, ken), map[string]string{"id": "1"}, `{"students": ["studentjoe@gmail.com"]}`, http.StatusOK},
+		{"EnrolInOtherClass_Forbidden", asIdentity(classHandler.EnrolClassStudents, ken), map[string]string{"id": "2"}, `{"students": ["studentjoe@gmail.com"]}`, http.StatusForbidden},
+		{"AdminEnrolsInAnyClass_Success", asAdmin(classHandler.EnrolClassStudents), map[string]string{"id": "2"}, `{"students": ["studentjoe@gmail.com"]}`, http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/api", bytes.NewBufferString(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = mux.SetURLVars(req, tc.vars)
+			rr := httptest.NewRecorder()
+			tc.handler.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedCode {
+				t.Errorf("Expected status code %d, but got %d", tc.expectedCode, rr.Code)
+			}
+		})
+	}
+}
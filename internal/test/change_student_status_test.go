@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"bytes"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/teacher"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestChangeStudentStatus(t *testing.T) {
+
+	// Create a new instance of the teacher service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	teacherStudentRepo := &mocks.MockTeacherStudentsRepo{}
+	classRepo := &mocks.MockClassRepo{}
+	notificationRepo := &mocks.MockNotificationRepo{}
+	teacherService := teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo, classRepo, mocks.NewMockUnitOfWork(teacherRepo, studentRepo, teacherStudentRepo, classRepo, notificationRepo), zap.NewNop())
+	teacherHandler := handler.NewTeacherHandler(teacherService)
+
+	tests := []struct {
+		name          string
+		currentStatus models.StatusStudent
+		body          string
+		expectedCode  int
+	}{
+		{"ActiveToSuspended_Success", models.StatusActive, `{"status": "SUSPENDED"}`, http.StatusNoContent},
+		{"SuspendedToActive_Success", models.StatusSuspended, `{"status": "ACTIVE"}`, http.StatusNoContent},
+		{"ActiveToGraduated_Success", models.StatusActive, `{"status": "GRADUATED"}`, http.StatusNoContent},
+		{"GraduatedToActive_IllegalTransition", models.StatusGraduated, `{"status": "ACTIVE"}`, http.StatusConflict},
+		{"SuspendedToSuspended_IllegalTransition", models.StatusSuspended, `{"status": "SUSPENDED"}`, http.StatusConflict},
+		{"UnknownStatus_BadRequest", models.StatusActive, `{"status": "EXPELLED"}`, http.StatusUnprocessableEntity},
+		{"MissingStatus_BadRequest", models.StatusActive, `{}`, http.StatusUnprocessableEntity},
+		{"LongReason_BadRequest", models.StatusActive, `{"status": "SUSPENDED", "reason": "` + strings.Repeat("x", 1025) + `"}`, http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			currentStatus := tc.currentStatus
+			studentRepo.GetStudentByEmailFn = func(_ context.Context, email string) (*models.Student, error) {
+				return &models.Student{ID: 1, Email: email, Status: currentStatus}, nil
+			}
+
+			// Create a new HTTP request with the student email as path variable
+			req, err := http.NewRequest("POST", "/api/students/student1@example.com/status", bytes.NewBufferString(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = mux.SetURLVars(req, map[string]string{"email": "student1@example.com"})
+
+			// Create a new HTTP test recorder
+			rr := httptest.NewRecorder()
+
+			// Handle the request
+			handler := asAdmin(teacherHandler.ChangeStudentStatus)
+			handler.ServeHTTP(rr, req)
+
+			// Check the response status code
+			if rr.Code != tc.expectedCode {
+				t.Errorf("Expected status code %d, but got %d", tc.expectedCode, rr.Code)
+			}
+		})
+	}
+	// Test case: A change that lost a race with another change of the same student is a conflict
+	t.Run("ConcurrentChange_Conflict", func(t *testing.T) {
+		studentRepo.GetStudentByEmailFn = func(_ context.Context, email string) (*models.Student, error) {
+			return &models.Student{ID: 1, Email: email, Status: models.StatusActive}, nil
+		}
+		studentRepo.UpdateStudentStatusFn = func(_ context.Context, student *models.Student, change *models.StudentStatusChange) error {
+			return models.ErrStatusChanged
+		}
+		defer func() { studentRepo.UpdateStudentStatusFn = nil }()
+
+		req, err := http.NewRequest("POST", "/api/students/student1@example.com/status", bytes.NewBufferString(`{"status": "SUSPENDED"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "student1@example.com"})
+		rr := httptest.NewRecorder()
+		asAdmin(teacherHandler.ChangeStudentStatus).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusConflict || !strings.Contains(rr.Body.String(), "STUDENT_STATUS_CHANGED") {
+			t.Errorf("Expected a STUDENT_STATUS_CHANGED conflict, but got %d %s", rr.Code, rr.Body.String())
+		}
+	})
+}
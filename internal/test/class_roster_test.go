@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"class-management/internal/dto"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/class"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestClassRoster(t *testing.T) {
+	// Create a new instance of the class service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	teacherStudentRepo := &mocks.MockTeacherStudentsRepo{}
+	classRepo := &mocks.MockClassRepo{}
+	notificationRepo := &mocks.MockNotificationRepo{}
+	unitOfWork := mocks.NewMockUnitOfWork(teacherRepo, studentRepo, teacherStudentRepo, classRepo, notificationRepo)
+	classService := class.NewClassService(classRepo, teacherRepo, unitOfWork, zap.NewNop())
+	classHandler := handler.NewClassHandler(classService)
+
+	// Test case: List teachers and students of a class
+	t.Run("ClassRoster_Success", func(t *testing.T) {
+		classRepo.GetClassTeachersFn = func(_ context.Context, classID uint) ([]models.Teacher, error) {
+			return []models.Teacher{{ID: 1, Email: "teacherken@gmail.com"}}, nil
+		}
+		classRepo.GetClassStudentsFn = func(_ context.Context, classID uint, includeGraduated bool) ([]models.Student, error) {
+			if !includeGraduated {
+				t.Error("Expected include_graduated to be passed to the repository")
+			}
+			return []models.Student{{ID: 1, Email: "studentjon@gmail.com"}, {ID: 2, Email: "studenthon@gmail.com"}}, nil
+		}
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("GET", "/api/classes/1/students?include_graduated=true", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := http.HandlerFunc(classHandler.ClassRoster)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+
+		// Check the response body
+		var response dto.ClassRosterResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Teachers) != 1 || len(response.Students) != 2 {
+			t.Errorf("Expected 1 teacher and 2 students, but got %+v", response)
+		}
+	})
+}
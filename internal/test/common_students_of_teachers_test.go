@@ -1,13 +1,20 @@
 package handler
 
 import (
+	"class-management/internal/dto"
 	"class-management/internal/handler"
 	"class-management/internal/mocks"
+	"class-management/internal/models"
 	"class-management/internal/service/teacher"
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"testing"
+
+	"go.uber.org/zap"
 )
 
 func TestGetCommonStudents(t *testing.T) {
@@ -15,7 +22,9 @@ func TestGetCommonStudents(t *testing.T) {
 	teacherRepo := &mocks.MockTeacherRepo{}
 	studentRepo := &mocks.MockStudentRepo{}
 	teacherStudentRepo := &mocks.MockTeacherStudentsRepo{}
-	teacherService := teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo)
+	classRepo := &mocks.MockClassRepo{}
+	notificationRepo := &mocks.MockNotificationRepo{}
+	teacherService := teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo, classRepo, mocks.NewMockUnitOfWork(teacherRepo, studentRepo, teacherStudentRepo, classRepo, notificationRepo), zap.NewNop())
 	teacherHandler := handler.NewTeacherHandler(teacherService)
 
 	// Test case: No teacher found in query parameters
@@ -42,4 +51,305 @@ func TestGetCommonStudents(t *testing.T) {
 			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
 		}
 	})
+
+	// Test case: Graduated students are excluded unless requested
+	t.Run("GetCommonStudents_IncludeGraduatedFlag", func(t *testing.T) {
+		var received []bool
+		teacherStudentRepo.GetCommonStudentsFn = func(_ context.Context, filter models.CommonStudentsFilter) ([]string, int64, error) {
+			received = append(received, filter.IncludeGraduated)
+			return []string{"commonstudent1@gmail.com"}, 1, nil
+		}
+
+		for _, reqURL := range []string{
+			"/api/commonstudents?teacher=teacherken%40gmail.com",
+			"/api/commonstudents?teacher=teacherken%40gmail.com&include_graduated=true",
+		} {
+			// Create a new HTTP request
+			req, err := http.NewRequest("GET", reqURL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Create a new HTTP test recorder
+			rr := httptest.NewRecorder()
+
+			// Handle the request
+			handler := http.HandlerFunc(teacherHandler.CommonStudentsOfTeachers)
+			handler.ServeHTTP(rr, req)
+
+			// Check the response status code
+			if rr.Code != http.StatusOK {
+				t.Errorf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+			}
+		}
+
+		if len(received) != 2 || received[0] || !received[1] {
+			t.Errorf("Expected include_graduated to be passed as [false true], but got %v", received)
+		}
+	})
+
+	// Test case: Invalid include_graduated value
+	t.Run("GetCommonStudents_InvalidIncludeGraduated", func(t *testing.T) {
+		// Create a new HTTP request
+		req, err := http.NewRequest("GET", "/api/commonstudents?teacher=teacherken%40gmail.com&include_graduated=maybe", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := http.HandlerFunc(teacherHandler.CommonStudentsOfTeachers)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+
+	// Test case: Pagination, sorting and status filters are passed to the repository
+	t.Run("GetCommonStudents_Paginated", func(t *testing.T) {
+		var received models.CommonStudentsFilter
+		teacherStudentRepo.GetCommonStudentsFn = func(_ context.Context, filter models.CommonStudentsFilter) ([]string, int64, error) {
+			received = filter
+			return []string{"commonstudent3@gmail.com", "commonstudent4@gmail.com"}, 12, nil
+		}
+		defer func() { teacherStudentRepo.GetCommonStudentsFn = nil }()
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("GET", "/api/commonstudents?teacher=teacherken%40gmail.com&status=active&status=SUSPENDED&sort=created_at&order=desc&limit=2&offset=2", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := http.HandlerFunc(teacherHandler.CommonStudentsOfTeachers)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+
+		// Check the filter
+		expected := []models.StatusStudent{models.StatusActive, models.StatusSuspended}
+		if !reflect.DeepEqual(received.Statuses, expected) || received.Sort != models.CommonStudentsSortCreatedAt || !received.Descending || received.Limit != 2 || received.Offset != 2 {
+			t.Errorf("Unexpected filter %+v", received)
+		}
+
+		// Check the response body
+		var response dto.CommonStudentsResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Students) != 2 || response.Total != 12 || response.Limit != 2 || response.Offset != 2 {
+			t.Errorf("Unexpected response %+v", response)
+		}
+	})
+
+	// Test case: Every student is returned when no pagination params are given
+	t.Run("GetCommonStudents_NoPage", func(t *testing.T) {
+		var received models.CommonStudentsFilter
+		teacherStudentRepo.GetCommonStudentsFn = func(_ context.Context, filter models.CommonStudentsFilter) ([]string, int64, error) {
+			received = filter
+			return []string{}, 0, nil
+		}
+		defer func() { teacherStudentRepo.GetCommonStudentsFn = nil }()
+
+		req, err := http.NewRequest("GET", "/api/commonstudents?teacher=teacherken%40gmail.com", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(teacherHandler.CommonStudentsOfTeachers)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+		if received.Sort != models.CommonStudentsSortEmail || received.Descending || received.Limit != 0 || received.Offset != 0 || len(received.Statuses) != 0 {
+			t.Errorf("Unexpected default filter %+v", received)
+		}
+	})
+
+	// Test case: The default limit applies when only an offset is given
+	t.Run("GetCommonStudents_DefaultLimit", func(t *testing.T) {
+		var received models.CommonStudentsFilter
+		teacherStudentRepo.GetCommonStudentsFn = func(_ context.Context, filter models.CommonStudentsFilter) ([]string, int64, error) {
+			received = filter
+			return []string{}, 0, nil
+		}
+		defer func() { teacherStudentRepo.GetCommonStudentsFn = nil }()
+
+		req, err := http.NewRequest("GET", "/api/commonstudents?teacher=teacherken%40gmail.com&offset=5", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(teacherHandler.CommonStudentsOfTeachers)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+		if received.Limit != 100 || received.Offset != 5 {
+			t.Errorf("Unexpected page %+v", received)
+		}
+	})
+
+	// Test case: Invalid pagination, sorting or status values
+	for _, query := range []string{"limit=0", "limit=1001", "limit=abc", "offset=-1", "sort=name", "order=up", "status=EXPELLED"} {
+		t.Run("GetCommonStudents_Invalid_"+query, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/api/commonstudents?teacher=teacherken%40gmail.com&"+query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			handler := http.HandlerFunc(teacherHandler.CommonStudentsOfTeachers)
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusUnprocessableEntity {
+				t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+			}
+		})
+	}
+
+	// Test case: The mode decides how many of the teachers a student must be registered with
+	t.Run("GetCommonStudents_Modes", func(t *testing.T) {
+		var received models.CommonStudentsFilter
+		teacherStudentRepo.GetCommonStudentsFn = func(_ context.Context, filter models.CommonStudentsFilter) ([]string, int64, error) {
+			received = filter
+			return []string{}, 0, nil
+		}
+		defer func() { teacherStudentRepo.GetCommonStudentsFn = nil }()
+
+		teachers := "teacher=teacherken%40gmail.com&teacher=teacherjoe%40gmail.com&teacher=teachermay%40gmail.com"
+		for query, expected := range map[string]int{
+			teachers:                          3,
+			teachers + "&mode=all":            3,
+			teachers + "&mode=any":            1,
+			teachers + "&mode=at_least&min=2": 2,
+		} {
+			req, err := http.NewRequest("GET", "/api/commonstudents?"+query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			handler := http.HandlerFunc(teacherHandler.CommonStudentsOfTeachers)
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d for %s, but got %d", http.StatusOK, query, rr.Code)
+			}
+			if received.MinTeachers != expected {
+				t.Errorf("Expected min teachers %d for %s, but got %d", expected, query, received.MinTeachers)
+			}
+		}
+	})
+
+	// Test case: A teacher given more than once only counts once
+	t.Run("GetCommonStudents_DuplicateTeachers", func(t *testing.T) {
+		var received models.CommonStudentsFilter
+		teacherStudentRepo.GetCommonStudentsFn = func(_ context.Context, filter models.CommonStudentsFilter) ([]string, int64, error) {
+			received = filter
+			return []string{}, 0, nil
+		}
+		defer func() { teacherStudentRepo.GetCommonStudentsFn = nil }()
+
+		req, err := http.NewRequest("GET", "/api/commonstudents?teacher=teacherken%40gmail.com&teacher=TeacherKen%40gmail.com&teacher=teacherjoe%40gmail.com", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(teacherHandler.CommonStudentsOfTeachers)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+		if !reflect.DeepEqual(received.Teachers, []string{"teacherken@gmail.com", "teacherjoe@gmail.com"}) || received.MinTeachers != 2 {
+			t.Errorf("Unexpected filter %+v", received)
+		}
+
+		req, err = http.NewRequest("GET", "/api/commonstudents?teacher=teacherken%40gmail.com&teacher=teacherken%40gmail.com&mode=at_least&min=2", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+
+	// Test case: Students of excluded teachers are left out
+	t.Run("GetCommonStudents_Difference", func(t *testing.T) {
+		var received models.CommonStudentsFilter
+		teacherStudentRepo.GetCommonStudentsFn = func(_ context.Context, filter models.CommonStudentsFilter) ([]string, int64, error) {
+			received = filter
+			return []string{"studentjon@gmail.com"}, 1, nil
+		}
+		defer func() { teacherStudentRepo.GetCommonStudentsFn = nil }()
+
+		req, err := http.NewRequest("GET", "/api/commonstudents?teacher=teacherken%40gmail.com&exclude_teacher=teacherjoe%40gmail.com", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(teacherHandler.CommonStudentsOfTeachers)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+		if !reflect.DeepEqual(received.ExcludeTeachers, []string{"teacherjoe@gmail.com"}) || received.MinTeachers != 1 {
+			t.Errorf("Unexpected filter %+v", received)
+		}
+	})
+
+	// Test case: Excluded teachers must exist too
+	t.Run("GetCommonStudents_UnknownExcludedTeacher", func(t *testing.T) {
+		teacherRepo.TeacherByEmailFn = func(_ context.Context, email string) (*models.Teacher, error) {
+			if email == "teacherjoe@gmail.com" {
+				return nil, nil
+			}
+			return &models.Teacher{ID: 1, Email: email}, nil
+		}
+		defer func() { teacherRepo.TeacherByEmailFn = nil }()
+
+		req, err := http.NewRequest("GET", "/api/commonstudents?teacher=teacherken%40gmail.com&exclude_teacher=teacherjoe%40gmail.com", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(teacherHandler.CommonStudentsOfTeachers)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d, but got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+
+	// Test case: Invalid mode or min values
+	for _, query := range []string{"mode=none", "mode=at_least", "mode=at_least&min=0", "mode=at_least&min=3", "exclude_teacher=joe"} {
+		t.Run("GetCommonStudents_InvalidMode_"+query, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/api/commonstudents?teacher=teacherken%40gmail.com&teacher=teacherjoe%40gmail.com&"+query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			handler := http.HandlerFunc(teacherHandler.CommonStudentsOfTeachers)
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusUnprocessableEntity {
+				t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+			}
+		})
+	}
 }
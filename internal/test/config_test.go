@@ -0,0 +1,319 @@
+package handler
+
+import (
+	"class-management/internal/config"
+	"class-management/internal/database"
+	"class-management/internal/tracing"
+	"class-management/internal/utils"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+//Environment variables read by config.Load
+var configEnv = []string{
+	"CONFIG_FILE", "HTTP_PORT", "HTTP_READ_TIMEOUT", "HTTP_READ_HEADER_TIMEOUT", "HTTP_WRITE_TIMEOUT", "HTTP_IDLE_TIMEOUT",
+	"SHUTDOWN_TIMEOUT", "DB_MAX_OPEN_CONNS", "DB_MAX_IDLE_CONNS", "DB_CONN_MAX_LIFETIME", "DB_CONN_MAX_IDLE_TIME", "DB_DRIVER", "DB_HOST", "DB_PORT", "DB_NAME", "DB_USER", "DB_PASSWORD", "DB_PASSWORD_FILE",
+	"DB_SSLMODE", "DB_PATH", "MIGRATE_ON_START", "API_KEYS", "API_KEYS_FILE", "AUTH_TOKEN_SECRET", "AUTH_TOKEN_SECRET_FILE",
+	"NOTIFIER", "NOTIFIER_LOG_FILE", "SMTP_HOST", "SMTP_PORT", "SMTP_USERNAME", "SMTP_PASSWORD", "SMTP_PASSWORD_FILE",
+	"SMTP_FROM", "WEBHOOK_URL", "LOG_LEVEL", "TRACING_EXPORTER", "TRACING_SERVICE_NAME", "TRACING_SAMPLE_RATIO",
+	"TRACING_OTLP_ENDPOINT", "TRACING_OTLP_INSECURE", "REQUEST_TIMEOUT", "ROUTE_TIMEOUTS",
+}
+
+//Clears the config environment and sets a valid MySQL config
+func setConfigEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range configEnv {
+		t.Setenv(name, "")
+	}
+	t.Setenv("DB_HOST", "db")
+	t.Setenv("DB_PORT", "3306")
+	t.Setenv("DB_NAME", "class_management")
+	t.Setenv("DB_USER", "root")
+	t.Setenv("DB_PASSWORD", "root")
+	t.Setenv("API_KEYS", "dev-admin-key:admin")
+}
+
+func TestConfig(t *testing.T) {
+	// Test case: Env variables fill the config on top of the defaults
+	t.Run("Load_Env", func(t *testing.T) {
+		setConfigEnv(t)
+
+		cfg, args, err := config.Load(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.HTTPPort != 8080 || cfg.Database.Driver != database.MySQL || cfg.Database.Host != "db" || !cfg.MigrateOnStart {
+			t.Errorf("Expected defaults with the env values, but got %+v", *cfg)
+		}
+		if len(args) != 0 {
+			t.Errorf("Expected no arguments left, but got %v", args)
+		}
+	})
+
+	// Test case: Flags override env variables and the arguments after them are returned
+	t.Run("Load_Flags", func(t *testing.T) {
+		setConfigEnv(t)
+		t.Setenv("HTTP_PORT", "9000")
+
+		cfg, args, err := config.Load([]string{"-http-port", "9090", "-migrate-on-start=false", "migrate", "-dry-run", "up"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.HTTPPort != 9090 || cfg.MigrateOnStart {
+			t.Errorf("Expected port 9090 without migrating on start, but got %+v", *cfg)
+		}
+		expected := []string{"migrate", "-dry-run", "up"}
+		if !reflect.DeepEqual(args, expected) {
+			t.Errorf("Expected arguments %v, but got %v", expected, args)
+		}
+	})
+
+	// Test case: The config file is read, and env variables take precedence over it
+	t.Run("Load_File", func(t *testing.T) {
+		setConfigEnv(t)
+		t.Setenv("DB_HOST", "")
+		t.Setenv("DB_NAME", "from_env")
+
+		path := filepath.Join(t.TempDir(), "config.json")
+		content := `{"http_port": 8000, "database": {"host": "file-db", "name": "from_file"}, "notifier": {"kind": "webhook", "webhook_url": "https://example.com/hook"}}`
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, _, err := config.Load([]string{"-config", path})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.HTTPPort != 8000 || cfg.Database.Host != "file-db" || cfg.Database.Name != "from_env" || cfg.Notifier.WebhookURL != "https://example.com/hook" {
+			t.Errorf("Expected file values with env overrides, but got %+v", *cfg)
+		}
+	})
+
+	// Test case: Unknown keys in the config file are rejected
+	t.Run("Load_FileUnknownKey", func(t *testing.T) {
+		setConfigEnv(t)
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(path, []byte(`{"http_prot": 8000}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, _, err := config.Load([]string{"-config", path}); err == nil {
+			t.Error("Expected an error for an unknown key")
+		}
+	})
+
+	// Test case: Secrets are read from the file named by <env>_FILE
+	t.Run("Load_SecretFile", func(t *testing.T) {
+		setConfigEnv(t)
+		t.Setenv("DB_PASSWORD", "")
+		path := filepath.Join(t.TempDir(), "db_password")
+		if err := os.WriteFile(path, []byte("s3cret\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("DB_PASSWORD_FILE", path)
+
+		cfg, _, err := config.Load(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Database.Password != "s3cret" {
+			t.Errorf("Expected the password from the file, but got %q", cfg.Database.Password)
+		}
+	})
+
+	// Test case: A secret can't be set both directly and from a file
+	t.Run("Load_SecretAndFile", func(t *testing.T) {
+		setConfigEnv(t)
+		t.Setenv("DB_PASSWORD_FILE", filepath.Join(t.TempDir(), "db_password"))
+
+		_, _, err := config.Load(nil)
+		if err == nil || !strings.Contains(err.Error(), "DB_PASSWORD_FILE") {
+			t.Errorf("Expected an error naming DB_PASSWORD_FILE, but got %v", err)
+		}
+	})
+
+	// Test case: Every missing or invalid value is reported at once
+	t.Run("Load_Invalid", func(t *testing.T) {
+		setConfigEnv(t)
+		t.Setenv("DB_HOST", "")
+		t.Setenv("API_KEYS", "")
+		t.Setenv("HTTP_PORT", "http")
+		t.Setenv("NOTIFIER", "smtp")
+		t.Setenv("LOG_LEVEL", "verbose")
+
+		_, _, err := config.Load(nil)
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+		for _, expected := range []string{"HTTP_PORT", "DB_HOST", "SMTP_HOST", "SMTP_FROM", "API_KEYS or AUTH_TOKEN_SECRET", "LOG_LEVEL"} {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("Expected the error to mention %s, but got %v", expected, err)
+			}
+		}
+	})
+
+	// Test case: Invalid API keys are reported without repeating the key
+	t.Run("Load_InvalidAPIKey", func(t *testing.T) {
+		setConfigEnv(t)
+		t.Setenv("API_KEYS", "topsecret:superuser")
+
+		_, _, err := config.Load(nil)
+		if err == nil || strings.Contains(err.Error(), "topsecret") {
+			t.Errorf("Expected an error without the key, but got %v", err)
+		}
+	})
+
+	// Test case: Timeouts and pool settings are read as durations and counts from every source
+	t.Run("Load_TimeoutsAndPool", func(t *testing.T) {
+		setConfigEnv(t)
+		t.Setenv("HTTP_WRITE_TIMEOUT", "45s")
+		t.Setenv("DB_MAX_OPEN_CONNS", "50")
+
+		path := filepath.Join(t.TempDir(), "config.json")
+		content := `{"shutdown_timeout": "1m", "database": {"conn_max_lifetime": "1h"}}`
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, _, err := config.Load([]string{"-config", path, "-db-max-idle-conns", "20"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.HTTPWriteTimeout != utils.Duration(45*time.Second) || cfg.HTTPReadTimeout != utils.Duration(15*time.Second) || cfg.ShutdownTimeout != utils.Duration(time.Minute) {
+			t.Errorf("Expected the write timeout from env and the shutdown timeout from the file, but got %+v", *cfg)
+		}
+		if cfg.Database.MaxOpenConns != 50 || cfg.Database.MaxIdleConns != 20 || cfg.Database.ConnMaxLifetime != utils.Duration(time.Hour) {
+			t.Errorf("Expected the pool settings from every source, but got %+v", cfg.Database)
+		}
+		if !strings.Contains(cfg.String(), "HTTP_WRITE_TIMEOUT=45s") {
+			t.Errorf("Expected the timeout to be printed as a duration, but got %s", cfg)
+		}
+	})
+
+	// Test case: Invalid timeouts and pool settings are rejected
+	t.Run("Load_InvalidTimeoutsAndPool", func(t *testing.T) {
+		setConfigEnv(t)
+		t.Setenv("HTTP_READ_TIMEOUT", "15")
+		t.Setenv("SHUTDOWN_TIMEOUT", "0s")
+		t.Setenv("DB_MAX_OPEN_CONNS", "5")
+		t.Setenv("DB_MAX_IDLE_CONNS", "10")
+
+		_, _, err := config.Load(nil)
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+		for _, expected := range []string{"HTTP_READ_TIMEOUT", "SHUTDOWN_TIMEOUT", "DB_MAX_IDLE_CONNS"} {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("Expected the error to mention %s, but got %v", expected, err)
+			}
+		}
+	})
+
+	// Test case: Request deadlines are read and checked against the write timeout
+	t.Run("Load_RequestTimeouts", func(t *testing.T) {
+		setConfigEnv(t)
+		t.Setenv("REQUEST_TIMEOUT", "5s")
+		t.Setenv("ROUTE_TIMEOUTS", "/api/retrievefornotifications=20s")
+
+		cfg, _, err := config.Load(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if time.Duration(cfg.RequestTimeout) != 5*time.Second || cfg.RouteTimeouts != "/api/retrievefornotifications=20s" {
+			t.Errorf("Expected the request timeouts to be read, but got %s and %q", time.Duration(cfg.RequestTimeout), cfg.RouteTimeouts)
+		}
+
+		t.Setenv("REQUEST_TIMEOUT", "1m")
+		t.Setenv("ROUTE_TIMEOUTS", "/api/register")
+		_, _, err = config.Load(nil)
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+		for _, expected := range []string{"REQUEST_TIMEOUT must not exceed HTTP_WRITE_TIMEOUT", "ROUTE_TIMEOUTS is invalid"} {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("Expected the error to mention %s, but got %v", expected, err)
+			}
+		}
+
+		t.Setenv("REQUEST_TIMEOUT", "")
+		t.Setenv("ROUTE_TIMEOUTS", "/api/retrievefornotifications=2m")
+		_, _, err = config.Load(nil)
+		if err == nil || !strings.Contains(err.Error(), "ROUTE_TIMEOUTS of /api/retrievefornotifications") {
+			t.Errorf("Expected the route timeout to be rejected, but got %v", err)
+		}
+	})
+
+	// Test case: Tracing settings are read from the file, env and flags, and invalid ones rejected
+	t.Run("Load_Tracing", func(t *testing.T) {
+		setConfigEnv(t)
+		t.Setenv("TRACING_EXPORTER", "otlp")
+		t.Setenv("TRACING_SAMPLE_RATIO", "0.25")
+
+		path := filepath.Join(t.TempDir(), "config.json")
+		content := `{"tracing": {"otlp_endpoint": "collector:4318", "service_name": "class-api"}}`
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, _, err := config.Load([]string{"-config", path, "-tracing-otlp-insecure", "true"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := tracing.Config{Exporter: "otlp", ServiceName: "class-api", SampleRatio: 0.25, OTLPEndpoint: "collector:4318", OTLPInsecure: true}
+		if cfg.Tracing != expected {
+			t.Errorf("Expected %+v, but got %+v", expected, cfg.Tracing)
+		}
+		if !strings.Contains(cfg.String(), "TRACING_SAMPLE_RATIO=0.25") {
+			t.Errorf("Expected the sample ratio to be printed, but got %s", cfg)
+		}
+
+		t.Setenv("TRACING_EXPORTER", "jaeger")
+		t.Setenv("TRACING_SAMPLE_RATIO", "2")
+		t.Setenv("TRACING_OTLP_ENDPOINT", "http://collector:4318")
+		_, _, err = config.Load(nil)
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+		for _, expected := range []string{"TRACING_EXPORTER", "TRACING_SAMPLE_RATIO", "TRACING_OTLP_ENDPOINT"} {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("Expected the error to mention %s, but got %v", expected, err)
+			}
+		}
+	})
+
+	// Test case: SQLite only needs a path
+	t.Run("Load_SQLite", func(t *testing.T) {
+		setConfigEnv(t)
+		t.Setenv("DB_HOST", "")
+		t.Setenv("DB_DRIVER", "sqlite")
+		t.Setenv("DB_PATH", database.SQLiteMemory)
+
+		if _, _, err := config.Load(nil); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// Test case: Secrets are redacted when the config is printed
+	t.Run("String_Redacted", func(t *testing.T) {
+		setConfigEnv(t)
+		t.Setenv("DB_PASSWORD", "s3cret")
+		t.Setenv("AUTH_TOKEN_SECRET", "token-secret")
+
+		cfg, _, err := config.Load(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, printed := range []string{cfg.String(), cfg.GoString()} {
+			if strings.Contains(printed, "s3cret") || strings.Contains(printed, "token-secret") || strings.Contains(printed, "dev-admin-key") {
+				t.Errorf("Expected secrets to be redacted, but got %s", printed)
+			}
+			if !strings.Contains(printed, "DB_HOST=db") || !strings.Contains(printed, "DB_PASSWORD=[REDACTED]") {
+				t.Errorf("Expected the settings to be listed, but got %s", printed)
+			}
+		}
+	})
+}
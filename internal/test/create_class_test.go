@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"bytes"
+	"class-management/internal/dto"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/class"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestCreateClass(t *testing.T) {
+	// Create a new instance of the class service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	teacherStudentRepo := &mocks.MockTeacherStudentsRepo{}
+	classRepo := &mocks.MockClassRepo{}
+	notificationRepo := &mocks.MockNotificationRepo{}
+	unitOfWork := mocks.NewMockUnitOfWork(teacherRepo, studentRepo, teacherStudentRepo, classRepo, notificationRepo)
+	classService := class.NewClassService(classRepo, teacherRepo, unitOfWork, zap.NewNop())
+	classHandler := handler.NewClassHandler(classService)
+
+	// Test case: Create a class with its teachers
+	t.Run("CreateClass_Success", func(t *testing.T) {
+		var assigned []uint
+		classRepo.AssignTeacherFn = func(_ context.Context, classTeacher *models.ClassTeacher) error {
+			assigned = append(assigned, classTeacher.TeacherID)
+			return nil
+		}
+		defer func() { classRepo.AssignTeacherFn = nil }()
+
+		// Prepare the request payload
+		payload := []byte(`{"name": "Math 3B", "teachers": ["teacherken@gmail.com"]}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/classes", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(classHandler.CreateClass)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusCreated, rr.Code)
+		}
+
+		// Check the response body
+		var response dto.ClassResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Name != "Math 3B" || len(response.Teachers) != 1 || len(assigned) != 1 {
+			t.Errorf("Expected Math 3B with one teacher, but got %+v", response)
+		}
+	})
+
+	// Test case: A teacher listed more than once is assigned once
+	t.Run("CreateClassDuplicateTeachers_Success", func(t *testing.T) {
+		var assigned []uint
+		classRepo.AssignTeacherFn = func(_ context.Context, classTeacher *models.ClassTeacher) error {
+			assigned = append(assigned, classTeacher.TeacherID)
+			return nil
+		}
+		defer func() { classRepo.AssignTeacherFn = nil }()
+
+		payload := []byte(`{"name": "Math 3B", "teachers": ["teacherken@gmail.com", "teacherken@gmail.com", "TeacherKen@gmail.com"]}`)
+		req, err := http.NewRequest("POST", "/api/classes", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler := asAdmin(classHandler.CreateClass)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusCreated, rr.Code)
+		}
+		var response dto.ClassResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Teachers) != 1 || len(assigned) != 1 {
+			t.Errorf("Expected the teacher to be assigned once, but got %+v and %v", response, assigned)
+		}
+	})
+
+	// Test case: Class name already taken
+	t.Run("CreateExistingClass_BadRequest", func(t *testing.T) {
+		classRepo.GetClassByNameFn = func(_ context.Context, name string) (*models.Class, error) {
+			return &models.Class{ID: 1, Name: name}, nil
+		}
+		defer func() { classRepo.GetClassByNameFn = nil }()
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/classes", bytes.NewBuffer([]byte(`{"name": "Math 3B"}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(classHandler.CreateClass)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusConflict {
+			t.Errorf("Expected status code %d, but got %d", http.StatusConflict, rr.Code)
+		}
+	})
+
+	// Test case: Missing class name
+	t.Run("CreateClassWithoutName_BadRequest", func(t *testing.T) {
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/classes", bytes.NewBuffer([]byte(`{"name": "  "}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(classHandler.CreateClass)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+}
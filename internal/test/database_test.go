@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"class-management/internal/database"
+	"class-management/internal/utils"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDatabaseOpen(t *testing.T) {
+	// Test case: The pool settings are applied to the sql.DB
+	t.Run("Open_PoolSettings", func(t *testing.T) {
+		db, err := database.Open(database.Config{
+			Driver:          database.SQLite,
+			Path:            filepath.Join(t.TempDir(), "pool.db"),
+			MaxOpenConns:    7,
+			MaxIdleConns:    3,
+			ConnMaxLifetime: utils.Duration(time.Minute),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sqlDB.Close()
+
+		if stats := sqlDB.Stats(); stats.MaxOpenConnections != 7 {
+			t.Errorf("Expected 7 max open connections, but got %d", stats.MaxOpenConnections)
+		}
+	})
+
+	// Test case: An in-memory SQLite database keeps a single connection whatever the pool settings
+	t.Run("Open_SQLiteMemory", func(t *testing.T) {
+		db, err := database.Open(database.Config{
+			Driver:       database.SQLite,
+			Path:         database.SQLiteMemory,
+			MaxOpenConns: 7,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sqlDB.Close()
+
+		if stats := sqlDB.Stats(); stats.MaxOpenConnections != 1 {
+			t.Errorf("Expected 1 max open connection, but got %d", stats.MaxOpenConnections)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"bytes"
+	"class-management/internal/deadline"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/teacher"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestDeadline(t *testing.T) {
+	//Serve a request through a router with the deadline middleware and return the time left on its context
+	timeLeft := func(routes map[string]time.Duration, defaultTimeout time.Duration, path string) (time.Duration, bool) {
+		var left time.Duration
+		var hasDeadline bool
+		router := mux.NewRouter()
+		router.Use(deadline.Middleware(defaultTimeout, routes))
+		record := func(writer http.ResponseWriter, request *http.Request) {
+			var at time.Time
+			at, hasDeadline = request.Context().Deadline()
+			left = time.Until(at)
+		}
+		router.HandleFunc("/api/retrievefornotifications", record)
+		router.HandleFunc("/api/students/{email}", record)
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, path, nil))
+		return left, hasDeadline
+	}
+
+	// Test case: A route with its own timeout gets it, other routes get the default
+	t.Run("Middleware_RouteTimeout", func(t *testing.T) {
+		routes := map[string]time.Duration{"/api/retrievefornotifications": time.Minute}
+
+		left, ok := timeLeft(routes, 5*time.Second, "/api/retrievefornotifications")
+		if !ok || left <= 50*time.Second || left > time.Minute {
+			t.Errorf("Expected a deadline about a minute away, but got %s %v", left, ok)
+		}
+		left, ok = timeLeft(routes, 5*time.Second, "/api/students/studentbob@gmail.com")
+		if !ok || left <= 0 || left > 5*time.Second {
+			t.Errorf("Expected the default deadline, but got %s %v", left, ok)
+		}
+	})
+
+	// Test case: A zero timeout leaves the request without a deadline
+	t.Run("Middleware_NoTimeout", func(t *testing.T) {
+		routes := map[string]time.Duration{"/api/retrievefornotifications": 0}
+
+		if _, ok := timeLeft(routes, 5*time.Second, "/api/retrievefornotifications"); ok {
+			t.Error("Expected no deadline for the route")
+		}
+		if _, ok := timeLeft(nil, 0, "/api/students/studentbob@gmail.com"); ok {
+			t.Error("Expected no deadline by default")
+		}
+	})
+
+	// Test case: Route timeouts are read from route=timeout pairs
+	t.Run("ParseRouteTimeouts", func(t *testing.T) {
+		timeouts, err := deadline.ParseRouteTimeouts(" /api/retrievefornotifications=30s, /api/students/{email}=2s ,")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(timeouts) != 2 || timeouts["/api/retrievefornotifications"] != 30*time.Second || timeouts["/api/students/{email}"] != 2*time.Second {
+			t.Errorf("Expected 2 route timeouts, but got %v", timeouts)
+		}
+
+		for _, value := range []string{"/api/register", "=30s", "/api/register=soon", "/api/register=-1s", "/api/register=1s,/api/register=2s"} {
+			if _, err := deadline.ParseRouteTimeouts(value); err == nil {
+				t.Errorf("Expected an error for %q", value)
+			}
+		}
+	})
+
+	// Test case: The request deadline reaches the repositories, and an expired one stops the work
+	t.Run("Context_ReachesRepositories", func(t *testing.T) {
+		var repoCtx context.Context
+		teacherRepo := &mocks.MockTeacherRepo{TeacherByEmailFn: func(ctx context.Context, email string) (*models.Teacher, error) {
+			repoCtx = ctx
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return &models.Teacher{ID: 1, Email: email}, nil
+		}}
+		studentRepo := &mocks.MockStudentRepo{}
+		teacherStudentRepo := &mocks.MockTeacherStudentsRepo{}
+		classRepo := &mocks.MockClassRepo{}
+		unitOfWork := mocks.NewMockUnitOfWork(teacherRepo, studentRepo, teacherStudentRepo, classRepo, &mocks.MockNotificationRepo{})
+		teacherHandler := handler.NewTeacherHandler(teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo, classRepo, unitOfWork, zap.NewNop()))
+
+		serve := func(timeout time.Duration) int {
+			router := mux.NewRouter()
+			router.Use(deadline.Middleware(timeout, nil))
+			router.Handle("/api/register", asAdmin(teacherHandler.RegisterStudents))
+
+			reqBody := []byte(`{"teacher": "teacherken@gmail.com", "students": ["studentjon@gmail.com"]}`)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/register", bytes.NewBuffer(reqBody)))
+			return rr.Code
+		}
+
+		if status := serve(time.Minute); status != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, status)
+		}
+		if _, ok := repoCtx.Deadline(); !ok {
+			t.Error("Expected the repository to get the request deadline")
+		}
+
+		if status := serve(time.Nanosecond); status == http.StatusOK {
+			t.Errorf("Expected the expired request to fail, but got %d", status)
+		}
+		if repoCtx.Err() != context.DeadlineExceeded {
+			t.Errorf("Expected the repository context to be past its deadline, but got %v", repoCtx.Err())
+		}
+	})
+}
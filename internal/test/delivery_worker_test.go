@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/notifier"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeliveryWorker(t *testing.T) {
+	dueDelivery := func(attempts int) models.NotificationDelivery {
+		lockedUntil := time.Now().Add(time.Minute)
+		return models.NotificationDelivery{
+			ID:             1,
+			NotificationID: 1,
+			Notification:   models.Notification{ID: 1, Teacher: models.Teacher{Email: "teacherken@gmail.com"}, Text: "Hey everybody"},
+			Email:          "studentbob@gmail.com",
+			Status:         models.DeliveryPending,
+			Attempts:       attempts,
+			ClaimToken:     "claim",
+			LockedUntil:    &lockedUntil,
+		}
+	}
+	options := notifier.WorkerOptions{MaxAttempts: 3, BaseBackoff: time.Minute, MaxBackoff: time.Hour}
+
+	// Test case: A due delivery is sent and marked SENT
+	t.Run("DeliverySent_Success", func(t *testing.T) {
+		var sent []notifier.Message
+		var updated []models.NotificationDelivery
+		deliveryRepo := &mocks.MockDeliveryRepo{
+			ClaimDueDeliveriesFn: func(_ context.Context, now time.Time, limit int, lease time.Duration) ([]models.NotificationDelivery, error) {
+				return []models.NotificationDelivery{dueDelivery(0)}, nil
+			},
+			UpdateDeliveryFn: func(_ context.Context, delivery *models.NotificationDelivery) error {
+				updated = append(updated, *delivery)
+				return nil
+			},
+		}
+		mockNotifier := &mocks.MockNotifier{SendFn: func(_ context.Context, message notifier.Message) error {
+			sent = append(sent, message)
+			return nil
+		}}
+
+		count, err := notifier.NewWorker(deliveryRepo, mockNotifier, options).ProcessDue(context.Background())
+		if err != nil || count != 1 {
+			t.Fatalf("Expected 1 delivery attempted, but got %d, %v", count, err)
+		}
+		if len(sent) != 1 || sent[0].To != "studentbob@gmail.com" || sent[0].From != "teacherken@gmail.com" || sent[0].Body != "Hey everybody" {
+			t.Errorf("Unexpected message %+v", sent)
+		}
+		if len(updated) != 1 || updated[0].Status != models.DeliverySent || updated[0].Attempts != 1 || updated[0].DeliveredAt == nil {
+			t.Errorf("Expected the delivery to be marked SENT, but got %+v", updated)
+		}
+	})
+
+	// Test case: A failed delivery stays PENDING and is retried with backoff
+	t.Run("DeliveryFailed_Retried", func(t *testing.T) {
+		var updated []models.NotificationDelivery
+		deliveryRepo := &mocks.MockDeliveryRepo{
+			ClaimDueDeliveriesFn: func(_ context.Context, now time.Time, limit int, lease time.Duration) ([]models.NotificationDelivery, error) {
+				return []models.NotificationDelivery{dueDelivery(1)}, nil
+			},
+			UpdateDeliveryFn: func(_ context.Context, delivery *models.NotificationDelivery) error {
+				updated = append(updated, *delivery)
+				return nil
+			},
+		}
+		mockNotifier := &mocks.MockNotifier{SendFn: func(_ context.Context, message notifier.Message) error {
+			return errors.New("connection refused")
+		}}
+
+		before := time.Now()
+		if _, err := notifier.NewWorker(deliveryRepo, mockNotifier, options).ProcessDue(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if len(updated) != 1 {
+			t.Fatalf("Expected 1 updated delivery, but got %d", len(updated))
+		}
+		delivery := updated[0]
+		if delivery.Status != models.DeliveryPending || delivery.Attempts != 2 || delivery.LastError != "connection refused" {
+			t.Errorf("Expected the delivery to stay PENDING, but got %+v", delivery)
+		}
+		// second attempt failed, so the retry waits twice the base backoff
+		if delay := delivery.NextAttemptAt.Sub(before); delay < 2*time.Minute || delay > 2*time.Minute+time.Second*10 {
+			t.Errorf("Expected a 2 minute backoff, but got %s", delay)
+		}
+	})
+
+	// Test case: A delivery that fails on the last attempt is marked FAILED
+	t.Run("DeliveryFailed_GivesUp", func(t *testing.T) {
+		var updated []models.NotificationDelivery
+		deliveryRepo := &mocks.MockDeliveryRepo{
+			ClaimDueDeliveriesFn: func(_ context.Context, now time.Time, limit int, lease time.Duration) ([]models.NotificationDelivery, error) {
+				return []models.NotificationDelivery{dueDelivery(2)}, nil
+			},
+			UpdateDeliveryFn: func(_ context.Context, delivery *models.NotificationDelivery) error {
+				updated = append(updated, *delivery)
+				return nil
+			},
+		}
+		mockNotifier := &mocks.MockNotifier{SendFn: func(_ context.Context, message notifier.Message) error {
+			return errors.New("mailbox unavailable")
+		}}
+
+		if _, err := notifier.NewWorker(deliveryRepo, mockNotifier, options).ProcessDue(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if len(updated) != 1 || updated[0].Status != models.DeliveryFailed || updated[0].Attempts != 3 {
+			t.Errorf("Expected the delivery to be marked FAILED, but got %+v", updated)
+		}
+	})
+
+	// Test case: Deliveries whose claim ran out are left to the next poll
+	t.Run("ClaimRanOut_Stops", func(t *testing.T) {
+		var updated []models.NotificationDelivery
+		deliveryRepo := &mocks.MockDeliveryRepo{
+			ClaimDueDeliveriesFn: func(_ context.Context, now time.Time, limit int, lease time.Duration) ([]models.NotificationDelivery, error) {
+				expired := dueDelivery(0)
+				lockedUntil := now.Add(-time.Second)
+				expired.LockedUntil = &lockedUntil
+				return []models.NotificationDelivery{expired}, nil
+			},
+			UpdateDeliveryFn: func(_ context.Context, delivery *models.NotificationDelivery) error {
+				updated = append(updated, *delivery)
+				return nil
+			},
+		}
+		mockNotifier := &mocks.MockNotifier{SendFn: func(_ context.Context, message notifier.Message) error {
+			t.Error("Expected no message to be sent")
+			return nil
+		}}
+
+		count, err := notifier.NewWorker(deliveryRepo, mockNotifier, options).ProcessDue(context.Background())
+		if err != nil || count != 0 || len(updated) != 0 {
+			t.Errorf("Expected no delivery attempted, but got %d, %v, %+v", count, err, updated)
+		}
+	})
+
+	// Test case: A send gets a deadline within the claim, and one interrupted by the worker context is released unrecorded
+	t.Run("Send_Deadline", func(t *testing.T) {
+		var updated []models.NotificationDelivery
+		var claimedUntil time.Time
+		deliveryRepo := &mocks.MockDeliveryRepo{
+			ClaimDueDeliveriesFn: func(_ context.Context, now time.Time, limit int, lease time.Duration) ([]models.NotificationDelivery, error) {
+				delivery := dueDelivery(0)
+				claimedUntil = *delivery.LockedUntil
+				return []models.NotificationDelivery{delivery}, nil
+			},
+			UpdateDeliveryFn: func(_ context.Context, delivery *models.NotificationDelivery) error {
+				updated = append(updated, *delivery)
+				return nil
+			},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		mockNotifier := &mocks.MockNotifier{SendFn: func(sendCtx context.Context, message notifier.Message) error {
+			if deadline, ok := sendCtx.Deadline(); !ok || deadline.After(claimedUntil) {
+				t.Errorf("Expected a deadline within the claim, but got %s %v", deadline, ok)
+			}
+			cancel()
+			<-sendCtx.Done()
+			return sendCtx.Err()
+		}}
+
+		count, err := notifier.NewWorker(deliveryRepo, mockNotifier, options).ProcessDue(ctx)
+		if err != context.Canceled || count != 0 {
+			t.Errorf("Expected no delivery attempted, but got %d, %v", count, err)
+		}
+		if len(updated) != 1 || updated[0].Attempts != 0 || updated[0].Status != models.DeliveryPending {
+			t.Errorf("Expected the interrupted delivery to be released unrecorded, but got %+v", updated)
+		}
+	})
+
+	// Test case: Stopping the worker lets the send in flight finish and releases the rest of the batch
+	t.Run("Stop_FinishesSendAndReleases", func(t *testing.T) {
+		var updated []models.NotificationDelivery
+		deliveryRepo := &mocks.MockDeliveryRepo{
+			ClaimDueDeliveriesFn: func(_ context.Context, now time.Time, limit int, lease time.Duration) ([]models.NotificationDelivery, error) {
+				second := dueDelivery(0)
+				second.ID = 2
+				return []models.NotificationDelivery{dueDelivery(0), second}, nil
+			},
+			UpdateDeliveryFn: func(_ context.Context, delivery *models.NotificationDelivery) error {
+				updated = append(updated, *delivery)
+				return nil
+			},
+		}
+
+		stop := make(chan struct{})
+		sent := 0
+		mockNotifier := &mocks.MockNotifier{SendFn: func(sendCtx context.Context, message notifier.Message) error {
+			sent++
+			close(stop)
+			return sendCtx.Err()
+		}}
+
+		done := make(chan struct{})
+		go func() {
+			notifier.NewWorker(deliveryRepo, mockNotifier, notifier.WorkerOptions{Interval: time.Hour}).Run(context.Background(), stop)
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected the worker to stop")
+		}
+
+		if sent != 1 {
+			t.Errorf("Expected 1 message sent, but got %d", sent)
+		}
+		if len(updated) != 2 || updated[0].ID != 1 || updated[0].Status != models.DeliverySent || updated[1].ID != 2 || updated[1].Attempts != 0 || updated[1].Status != models.DeliveryPending {
+			t.Errorf("Expected the first delivery SENT and the second released, but got %+v", updated)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"bytes"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/teacher"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestDeregisterStudents(t *testing.T) {
+	// Create a new instance of the teacher service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	teacherStudentRepo := &mocks.MockTeacherStudentsRepo{}
+	classRepo := &mocks.MockClassRepo{}
+	notificationRepo := &mocks.MockNotificationRepo{}
+	teacherService := teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo, classRepo, mocks.NewMockUnitOfWork(teacherRepo, studentRepo, teacherStudentRepo, classRepo, notificationRepo), zap.NewNop())
+	teacherHandler := handler.NewTeacherHandler(teacherService)
+
+	// Test case: Deregister only the students registered with the teacher
+	t.Run("DeregisterStudents_Success", func(t *testing.T) {
+		studentRepo.GetStudentByEmailFn = func(_ context.Context, email string) (*models.Student, error) {
+			if email == "studentjon@gmail.com" {
+				return &models.Student{ID: 1, Email: email, Status: models.StatusActive}, nil
+			}
+			return &models.Student{ID: 2, Email: email, Status: models.StatusActive}, nil
+		}
+		teacherStudentRepo.IsStudentRegisteredForTeacherFn = func(_ context.Context, teacherID uint, studentID uint) (*models.TeacherStudent, error) {
+			if studentID == 1 {
+				return &models.TeacherStudent{ID: 1, TeacherID: teacherID, StudentID: studentID}, nil
+			}
+			return nil, nil
+		}
+
+		var deleted []uint
+		teacherStudentRepo.DeleteTeacherStudentFn = func(_ context.Context, teacherID uint, studentID uint) error {
+			deleted = append(deleted, studentID)
+			return nil
+		}
+
+		// Prepare the request payload
+		payload := []byte(`{"teacher": "teacherken@gmail.com", "students": ["studentjon@gmail.com", "studenthon@gmail.com"]}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/deregister", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.DeregisterStudents)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusNoContent {
+			t.Errorf("Expected status code %d, but got %d", http.StatusNoContent, rr.Code)
+		}
+
+		// Only the registered student is removed
+		if len(deleted) != 1 || deleted[0] != 1 {
+			t.Errorf("Expected only student 1 to be deregistered, but got %v", deleted)
+		}
+	})
+
+	// Test case: Deregistering with missing student emails
+	t.Run("DeregisterMissingStudentEmails_BadRequest", func(t *testing.T) {
+		// Prepare the request payload
+		payload := []byte(`{"teacher": "teacherken@gmail.com", "students": []}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/deregister", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.DeregisterStudents)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code and error code
+		if rr.Code != http.StatusUnprocessableEntity || !strings.Contains(rr.Body.String(), `"code":"STUDENTS_REQUIRED_FOR_DEREGISTRATION"`) {
+			t.Errorf("Expected a STUDENTS_REQUIRED_FOR_DEREGISTRATION error, but got %d %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	// Test case: Deregistering with an invalid teacher email
+	t.Run("DeregisterInvalidTeacherEmail_BadRequest", func(t *testing.T) {
+		// Prepare the request payload
+		payload := []byte(`{"teacher": "invalid_email", "students": ["studentjon@gmail.com"]}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/deregister", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.DeregisterStudents)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"bytes"
+	"class-management/internal/dto"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/class"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestEnrolClassStudents(t *testing.T) {
+	// Create a new instance of the class service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	teacherStudentRepo := &mocks.MockTeacherStudentsRepo{}
+	classRepo := &mocks.MockClassRepo{}
+	notificationRepo := &mocks.MockNotificationRepo{}
+	unitOfWork := mocks.NewMockUnitOfWork(teacherRepo, studentRepo, teacherStudentRepo, classRepo, notificationRepo)
+	classService := class.NewClassService(classRepo, teacherRepo, unitOfWork, zap.NewNop())
+	classHandler := handler.NewClassHandler(classService)
+
+	// Test case: Enrol students and report the result of every email
+	t.Run("EnrolStudents_Success", func(t *testing.T) {
+		classRepo.IsStudentEnrolledInClassFn = func(_ context.Context, classID uint, studentID uint) (*models.ClassStudent, error) {
+			return nil, nil
+		}
+
+		// Prepare the request payload
+		payload := []byte(`{"students": ["studentjon@gmail.com", "not-an-email"]}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/classes/1/students", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(classHandler.EnrolClassStudents)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+
+		// Check the result of every email
+		var response dto.RegistrationResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Results) != 2 || response.Results[0].Status != dto.RegistrationLinked || response.Results[1].Status != dto.RegistrationInvalid {
+			t.Errorf("Expected linked and invalid results, but got %+v", response.Results)
+		}
+	})
+
+	// Test case: Unknown class
+	t.Run("EnrolStudentsUnknownClass_NotFound", func(t *testing.T) {
+		classRepo.GetClassByIDFn = func(_ context.Context, id uint) (*models.Class, error) {
+			return nil, nil
+		}
+		defer func() { classRepo.GetClassByIDFn = nil }()
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/classes/9/students", bytes.NewBuffer([]byte(`{"students": ["studentjon@gmail.com"]}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"id": "9"})
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(classHandler.EnrolClassStudents)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d, but got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+
+	// Test case: Invalid class id
+	t.Run("EnrolStudentsInvalidClassID_BadRequest", func(t *testing.T) {
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/classes/abc/students", bytes.NewBuffer([]byte(`{"students": ["studentjon@gmail.com"]}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"id": "abc"})
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(classHandler.EnrolClassStudents)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+}
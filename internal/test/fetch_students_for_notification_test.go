@@ -2,12 +2,19 @@ package handler
 
 import (
 	"bytes"
+	"class-management/internal/dto"
 	"class-management/internal/handler"
 	"class-management/internal/mocks"
+	"class-management/internal/models"
 	"class-management/internal/service/teacher"
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
+
+	"go.uber.org/zap"
 )
 
 func TestRetrieveNotifications(t *testing.T) {
@@ -15,7 +22,12 @@ func TestRetrieveNotifications(t *testing.T) {
 	teacherRepo := &mocks.MockTeacherRepo{}
 	studentRepo := &mocks.MockStudentRepo{}
 	teacherStudentRepo := &mocks.MockTeacherStudentsRepo{}
-	teacherService := teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo)
+	classRepo := &mocks.MockClassRepo{}
+	notificationRepo := &mocks.MockNotificationRepo{}
+	deliveryRepo := &mocks.MockDeliveryRepo{}
+	unitOfWork := mocks.NewMockUnitOfWork(teacherRepo, studentRepo, teacherStudentRepo, classRepo, notificationRepo)
+	unitOfWork.Repos.Deliveries = deliveryRepo
+	teacherService := teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo, classRepo, unitOfWork, zap.NewNop())
 	teacherHandler := handler.NewTeacherHandler(teacherService)
 
 	// Test case: Empty request body
@@ -34,7 +46,7 @@ func TestRetrieveNotifications(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.FetchStudentsForNotification)
+		handler := asAdmin(teacherHandler.FetchStudentsForNotification)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
@@ -59,7 +71,7 @@ func TestRetrieveNotifications(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.FetchStudentsForNotification)
+		handler := asAdmin(teacherHandler.FetchStudentsForNotification)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
@@ -84,7 +96,7 @@ func TestRetrieveNotifications(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.FetchStudentsForNotification)
+		handler := asAdmin(teacherHandler.FetchStudentsForNotification)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
@@ -109,7 +121,7 @@ func TestRetrieveNotifications(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.FetchStudentsForNotification)
+		handler := asAdmin(teacherHandler.FetchStudentsForNotification)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
@@ -134,7 +146,7 @@ func TestRetrieveNotifications(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.FetchStudentsForNotification)
+		handler := asAdmin(teacherHandler.FetchStudentsForNotification)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
@@ -159,7 +171,216 @@ func TestRetrieveNotifications(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.FetchStudentsForNotification)
+		handler := asAdmin(teacherHandler.FetchStudentsForNotification)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+
+	// Test case: Notification scoped to a class goes to the class students who are not suspended
+	t.Run("ClassScopedNotification_Success", func(t *testing.T) {
+		classRepo.GetClassStudentsFn = func(_ context.Context, classID uint, includeGraduated bool) ([]models.Student, error) {
+			return []models.Student{
+				{ID: 1, Email: "studentjon@gmail.com", Status: models.StatusActive},
+				{ID: 2, Email: "studentmary@gmail.com", Status: models.StatusSuspended},
+			}, nil
+		}
+		teacherStudentRepo.GetAllStudentsByTeacherFn = func(_ context.Context, teacher string, includeGraduated bool) ([]models.Student, error) {
+			t.Error("Expected teacher registrations not to be used for a class scoped notification")
+			return nil, nil
+		}
+		defer func() { teacherStudentRepo.GetAllStudentsByTeacherFn = nil }()
+
+		// Prepare the request URL and body
+		reqURL := "/api/retrievefornotifications"
+		reqBody := []byte(`{"teacher": "teacherken@gmail.com", "notification": "Hello class!", "class_id": 1}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", reqURL, bytes.NewBuffer(reqBody))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.FetchStudentsForNotification)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+
+		// Check the recipients
+		var response struct {
+			Recipients []string `json:"recipients"`
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Recipients) != 1 || response.Recipients[0] != "studentjon@gmail.com" {
+			t.Errorf("Expected only studentjon@gmail.com, but got %v", response.Recipients)
+		}
+	})
+
+	// Test case: Teacher not assigned to the class
+	// Test case: The notification is persisted with its resolved recipients
+	t.Run("NotificationPersisted_Success", func(t *testing.T) {
+		teacherStudentRepo.GetAllStudentsByTeacherFn = func(_ context.Context, teacher string, includeGraduated bool) ([]models.Student, error) {
+			return []models.Student{{ID: 1, Email: "studentbob@gmail.com", Status: models.StatusActive}}, nil
+		}
+		defer func() { teacherStudentRepo.GetAllStudentsByTeacherFn = nil }()
+		var saved *models.Notification
+		notificationRepo.CreateNotificationFn = func(_ context.Context, notification *models.Notification) error {
+			notification.ID = 7
+			saved = notification
+			return nil
+		}
+		defer func() { notificationRepo.CreateNotificationFn = nil }()
+		var queued []models.NotificationDelivery
+		deliveryRepo.CreateDeliveriesFn = func(_ context.Context, deliveries []models.NotificationDelivery) error {
+			queued = deliveries
+			return nil
+		}
+		defer func() { deliveryRepo.CreateDeliveriesFn = nil }()
+
+		// Prepare the request URL and body
+		reqURL := "/api/retrievefornotifications"
+		reqBody := []byte(`{"teacher": "teacherken@gmail.com", "notification": "Hello @studentbob@gmail.com @studentagnes@gmail.com"}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", reqURL, bytes.NewBuffer(reqBody))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.FetchStudentsForNotification)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+
+		// Check the response refers to the stored notification
+		var response dto.FetchStudentsForNotificationResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if response.NotificationID != 7 || len(response.Recipients) != 2 {
+			t.Errorf("Unexpected response %+v", response)
+		}
+
+		// Check the stored recipients
+		if saved == nil || len(saved.Recipients) != 2 {
+			t.Fatalf("Expected the notification to be stored with 2 recipients, but got %+v", saved)
+		}
+		bob, agnes := saved.Recipients[0], saved.Recipients[1]
+		if bob.Email != "studentbob@gmail.com" || !bob.Registered || !bob.Mentioned {
+			t.Errorf("Expected studentbob@gmail.com to be registered and mentioned, but got %+v", bob)
+		}
+		if agnes.Email != "studentagnes@gmail.com" || agnes.Registered || !agnes.Mentioned {
+			t.Errorf("Expected studentagnes@gmail.com to be mentioned only, but got %+v", agnes)
+		}
+
+		// Check a pending delivery is queued for every recipient
+		if len(queued) != 2 {
+			t.Fatalf("Expected 2 queued deliveries, but got %+v", queued)
+		}
+		for _, delivery := range queued {
+			if delivery.NotificationID != 7 || delivery.Status != models.DeliveryPending {
+				t.Errorf("Unexpected delivery %+v", delivery)
+			}
+		}
+	})
+
+	// Test case: Only @mentions of students who can receive the notification are recipients
+	t.Run("StrictMentions_Success", func(t *testing.T) {
+		studentRepo.GetStudentByEmailFn = func(_ context.Context, email string) (*models.Student, error) {
+			switch email {
+			case "studentagnes@gmail.com":
+				return &models.Student{ID: 1, Email: email, Status: models.StatusActive}, nil
+			case "studentmary@gmail.com":
+				return &models.Student{ID: 2, Email: email, Status: models.StatusSuspended}, nil
+			case "studentjon@gmail.com":
+				return &models.Student{ID: 3, Email: email, Status: models.StatusGraduated}, nil
+			case "studentplain@gmail.com", "admin@school.com":
+				t.Errorf("Expected %s not to be treated as a mention", email)
+			}
+			return nil, nil
+		}
+		defer func() { studentRepo.GetStudentByEmailFn = nil }()
+
+		// Prepare the request URL and body
+		reqURL := "/api/retrievefornotifications"
+		reqBody := []byte(`{"teacher": "teacherken@gmail.com", "notification": "Contact admin@school.com. Hi @studentagnes@gmail.com, @studentagnes@gmail.com @studentgone@gmail.com @studentmary@gmail.com @studentjon@gmail.com email@studentplain@gmail.com"}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", reqURL, bytes.NewBuffer(reqBody))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.FetchStudentsForNotification)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+
+		// Check the recipients and the dropped mentions
+		var response dto.FetchStudentsForNotificationResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Recipients) != 1 || response.Recipients[0] != "studentagnes@gmail.com" {
+			t.Errorf("Expected only studentagnes@gmail.com, but got %v", response.Recipients)
+		}
+		expected := []dto.DroppedMention{
+			{Email: "studentgone@gmail.com", Reason: dto.MentionNotFound},
+			{Email: "studentmary@gmail.com", Reason: dto.MentionSuspended},
+			{Email: "studentjon@gmail.com", Reason: dto.MentionGraduated},
+		}
+		if !reflect.DeepEqual(response.DroppedMentions, expected) {
+			t.Errorf("Expected dropped mentions %+v, but got %+v", expected, response.DroppedMentions)
+		}
+	})
+
+	t.Run("ClassScopedNotificationTeacherNotInClass_BadRequest", func(t *testing.T) {
+		classRepo.IsTeacherAssignedToClassFn = func(_ context.Context, classID uint, teacherID uint) (*models.ClassTeacher, error) {
+			return nil, nil
+		}
+		defer func() { classRepo.IsTeacherAssignedToClassFn = nil }()
+
+		// Prepare the request URL and body
+		reqURL := "/api/retrievefornotifications"
+		reqBody := []byte(`{"teacher": "teacherken@gmail.com", "notification": "Hello class!", "class_id": 1}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", reqURL, bytes.NewBuffer(reqBody))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.FetchStudentsForNotification)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"class-management/internal/auth"
+	"class-management/internal/dto"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/notification"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestGetNotification(t *testing.T) {
+	// Create a new instance of the notification service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	notificationRepo := &mocks.MockNotificationRepo{}
+	notificationService := notification.NewNotificationService(teacherRepo, notificationRepo)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+
+	notificationRepo.GetNotificationByIDFn = func(_ context.Context, id uint) (*models.Notification, error) {
+		if id != 1 {
+			return nil, nil
+		}
+		return &models.Notification{
+			ID:      1,
+			Teacher: models.Teacher{ID: 1, Email: "teacherken@gmail.com"},
+			Text:    "Hello @studentagnes@gmail.com",
+			Recipients: []models.NotificationRecipient{
+				{Email: "studentagnes@gmail.com", Mentioned: true},
+				{Email: "studentbob@gmail.com", Registered: true},
+			},
+		}, nil
+	}
+
+	// Test case: Retrieve a notification with its recipients
+	t.Run("GetNotification_Success", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/api/notifications/1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+		rr := httptest.NewRecorder()
+		handler := asIdentity(notificationHandler.GetNotification, auth.Identity{Email: "teacherken@gmail.com", Role: auth.RoleTeacher})
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+
+		var response dto.NotificationResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Teacher != "teacherken@gmail.com" || len(response.Recipients) != 2 || !response.Recipients[0].Mentioned {
+			t.Errorf("Unexpected notification %+v", response)
+		}
+	})
+
+	// Test case: Notification doesn't exist or id is invalid
+	tests := map[string]struct {
+		id     string
+		status int
+	}{
+		"GetNotification_NotExists": {"2", http.StatusNotFound},
+		"GetNotification_InvalidID": {"abc", http.StatusUnprocessableEntity},
+	}
+	for name, test := range tests {
+		id := test.id
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/api/notifications/"+id, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = mux.SetURLVars(req, map[string]string{"id": id})
+
+			rr := httptest.NewRecorder()
+			handler := asAdmin(notificationHandler.GetNotification)
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != test.status {
+				t.Errorf("Expected status code %d, but got %d", test.status, rr.Code)
+			}
+		})
+	}
+
+	// Test case: Another teacher's notification is reported as not existing
+	t.Run("GetNotification_OtherTeacher", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/api/notifications/1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+		rr := httptest.NewRecorder()
+		handler := asIdentity(notificationHandler.GetNotification, auth.Identity{Email: "teacherjoe@gmail.com", Role: auth.RoleTeacher})
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusNotFound || !strings.Contains(rr.Body.String(), "NOTIFICATION_NOT_FOUND") {
+			t.Errorf("Expected a NOTIFICATION_NOT_FOUND error, but got %d %s", rr.Code, rr.Body.String())
+		}
+	})
+}
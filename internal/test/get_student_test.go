@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"class-management/internal/dto"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/directory"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestGetStudent(t *testing.T) {
+	// Create a new instance of the directory service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	directoryService := directory.NewDirectoryService(teacherRepo, studentRepo, &mocks.MockTeacherStudentsRepo{}, zap.NewNop())
+	directoryHandler := handler.NewDirectoryHandler(directoryService)
+
+	// Test case: Retrieve a student with status and teachers
+	t.Run("GetStudent_Success", func(t *testing.T) {
+		studentRepo.GetStudentByEmailFn = func(_ context.Context, email string) (*models.Student, error) {
+			return &models.Student{ID: 7, Email: email, Status: models.StatusSuspended}, nil
+		}
+		teacherRepo.GetTeachersByStudentFn = func(_ context.Context, studentID uint) ([]models.Teacher, error) {
+			if studentID != 7 {
+				t.Errorf("Expected teachers of student 7, but got %d", studentID)
+			}
+			return []models.Teacher{{ID: 2, Email: "teacherjoe@gmail.com"}, {ID: 1, Email: "teacherken@gmail.com"}}, nil
+		}
+
+		req, err := http.NewRequest("GET", "/api/students/studentmary@gmail.com", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "studentmary@gmail.com"})
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(directoryHandler.GetStudent)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+		var response dto.StudentResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Email != "studentmary@gmail.com" || response.Status != "SUSPENDED" || !reflect.DeepEqual(response.Teachers, []string{"teacherjoe@gmail.com", "teacherken@gmail.com"}) {
+			t.Errorf("Unexpected response %+v", response)
+		}
+	})
+
+	// Test case: Unknown student
+	t.Run("GetStudent_NotExists", func(t *testing.T) {
+		studentRepo.GetStudentByEmailFn = func(_ context.Context, email string) (*models.Student, error) {
+			return nil, nil
+		}
+
+		req, err := http.NewRequest("GET", "/api/students/studentgone@gmail.com", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "studentgone@gmail.com"})
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(directoryHandler.GetStudent)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d, but got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"class-management/internal/dto"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/directory"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestGetTeacher(t *testing.T) {
+	// Create a new instance of the directory service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	directoryService := directory.NewDirectoryService(teacherRepo, studentRepo, &mocks.MockTeacherStudentsRepo{}, zap.NewNop())
+	directoryHandler := handler.NewDirectoryHandler(directoryService)
+
+	// Test case: Retrieve an existing teacher
+	t.Run("GetTeacher_Success", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/api/teachers/teacherken@gmail.com", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "teacherken@gmail.com"})
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(directoryHandler.GetTeacher)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+		var response dto.TeacherResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Email != "teacherken@gmail.com" {
+			t.Errorf("Unexpected response %+v", response)
+		}
+	})
+
+	// Test case: Unknown teacher or invalid email
+	tests := map[string]struct {
+		email  string
+		status int
+	}{
+		"GetTeacher_NotExists":    {"teachergone@gmail.com", http.StatusNotFound},
+		"GetTeacher_InvalidEmail": {"teacher", http.StatusUnprocessableEntity},
+	}
+	for name, test := range tests {
+		email := test.email
+		t.Run(name, func(t *testing.T) {
+			teacherRepo.TeacherByEmailFn = func(_ context.Context, email string) (*models.Teacher, error) {
+				return nil, nil
+			}
+			defer func() { teacherRepo.TeacherByEmailFn = nil }()
+
+			req, err := http.NewRequest("GET", "/api/teachers/"+email, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = mux.SetURLVars(req, map[string]string{"email": email})
+			rr := httptest.NewRecorder()
+			handler := http.HandlerFunc(directoryHandler.GetTeacher)
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != test.status {
+				t.Errorf("Expected status code %d, but got %d", test.status, rr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"bytes"
+	"class-management/internal/auth"
+	"class-management/internal/dto"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/teacher"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestGraduateStudents(t *testing.T) {
+
+	// Create a new instance of the teacher service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	teacherStudentRepo := &mocks.MockTeacherStudentsRepo{}
+	classRepo := &mocks.MockClassRepo{}
+	notificationRepo := &mocks.MockNotificationRepo{}
+	teacherService := teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo, classRepo, mocks.NewMockUnitOfWork(teacherRepo, studentRepo, teacherStudentRepo, classRepo, notificationRepo), zap.NewNop())
+	teacherHandler := handler.NewTeacherHandler(teacherService)
+
+	// Test case: Graduate all active students of a teacher and skip the ones who cannot graduate
+	t.Run("GraduateByTeacher_Success", func(t *testing.T) {
+		teacherStudentRepo.GetAllStudentsByTeacherFn = func(_ context.Context, teacher string, includeGraduated bool) ([]models.Student, error) {
+			return []models.Student{{ID: 1, Email: "studentjon@gmail.com"}, {ID: 2, Email: "studenthon@gmail.com"}}, nil
+		}
+		studentRepo.GetStudentByEmailFn = func(_ context.Context, email string) (*models.Student, error) {
+			if email == "studentmary@gmail.com" {
+				return &models.Student{ID: 3, Email: email, Status: models.StatusSuspended}, nil
+			}
+			return &models.Student{ID: 1, Email: email, Status: models.StatusActive}, nil
+		}
+
+		graduated := 0
+		studentRepo.GraduateStudentFn = func(_ context.Context, student *models.Student, change *models.StudentStatusChange) error {
+			if student.Status != models.StatusGraduated || change.ToStatus != models.StatusGraduated {
+				t.Errorf("Expected student to be moved to %s", models.StatusGraduated)
+			}
+			graduated++
+			return nil
+		}
+
+		// Prepare the request payload
+		payload := []byte(`{"teacher": "teacherken@gmail.com", "students": ["studentmary@gmail.com", "studentjon@gmail.com"]}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/graduate", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.GraduateStudents)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+
+		// Check the response body
+		var response dto.GraduateStudentsResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Graduated) != 2 || graduated != 2 {
+			t.Errorf("Expected 2 graduated students, but got %v", response.Graduated)
+		}
+		if len(response.Skipped) != 1 || response.Skipped[0].Student != "studentmary@gmail.com" {
+			t.Errorf("Expected studentmary@gmail.com to be skipped, but got %v", response.Skipped)
+		}
+	})
+
+	// Test case: Graduate the active students of a grade level
+	t.Run("GraduateByGradeLevel_Success", func(t *testing.T) {
+		studentRepo.GetStudentsByGradeLevelFn = func(_ context.Context, gradeLevel string, includeGraduated bool) ([]models.Student, error) {
+			if gradeLevel != "Year 12" || includeGraduated {
+				t.Errorf("Expected the students of Year 12 who haven't graduated, but got %q %v", gradeLevel, includeGraduated)
+			}
+			return []models.Student{{ID: 1, Email: "studentjon@gmail.com"}, {ID: 2, Email: "studenthon@gmail.com"}}, nil
+		}
+		defer func() { studentRepo.GetStudentsByGradeLevelFn = nil }()
+		studentRepo.GetStudentByEmailFn = func(_ context.Context, email string) (*models.Student, error) {
+			return &models.Student{ID: 1, Email: email, Status: models.StatusActive}, nil
+		}
+		studentRepo.GraduateStudentFn = func(_ context.Context, student *models.Student, change *models.StudentStatusChange) error {
+			return nil
+		}
+
+		req, err := http.NewRequest("POST", "/api/graduate", bytes.NewBuffer([]byte(`{"grade_level": " Year 12 "}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler := asAdmin(teacherHandler.GraduateStudents)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+		var response dto.GraduateStudentsResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Graduated) != 2 || len(response.Skipped) != 0 {
+			t.Errorf("Expected 2 graduated students, but got %+v", response)
+		}
+	})
+
+	// Test case: Only an admin can graduate a grade level
+	t.Run("GraduateByGradeLevel_Forbidden", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/api/graduate", bytes.NewBuffer([]byte(`{"grade_level": "Year 12"}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler := asIdentity(teacherHandler.GraduateStudents, auth.Identity{Email: "teacherken@gmail.com", Role: auth.RoleTeacher})
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d, but got %d", http.StatusForbidden, rr.Code)
+		}
+	})
+
+	// Test case: Neither students, teacher nor grade level provided
+	t.Run("GraduateWithoutTarget_BadRequest", func(t *testing.T) {
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/graduate", bytes.NewBuffer([]byte(`{}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.GraduateStudents)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+
+	// Test case: Reason longer than the status history keeps
+	t.Run("GraduateWithLongReason_BadRequest", func(t *testing.T) {
+		// Create a new HTTP request
+		reqBody := []byte(`{"students": ["studentjon@gmail.com"], "reason": "` + strings.Repeat("x", 1025) + `"}`)
+		req, err := http.NewRequest("POST", "/api/graduate", bytes.NewBuffer(reqBody))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.GraduateStudents)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+
+	// Test case: Unknown teacher
+	t.Run("GraduateByUnknownTeacher_NotFound", func(t *testing.T) {
+		teacherRepo.TeacherByEmailFn = func(_ context.Context, email string) (*models.Teacher, error) {
+			return nil, nil
+		}
+		defer func() { teacherRepo.TeacherByEmailFn = nil }()
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/graduate", bytes.NewBuffer([]byte(`{"teacher": "unknown@gmail.com"}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.GraduateStudents)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d, but got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"class-management/db"
+	"class-management/internal/database"
+	"class-management/internal/handler"
+	"class-management/internal/health"
+	"class-management/internal/migrate"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/notifier"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealth(t *testing.T) {
+	// Test case: The report is ok when every check passes, with a result per check
+	t.Run("Checker_Success", func(t *testing.T) {
+		checker := health.NewChecker(time.Second)
+		checker.Add("database", func(ctx context.Context) error { return nil })
+		checker.Add("migrations", func(ctx context.Context) error { return nil })
+
+		report := checker.Run(context.Background())
+		if !report.OK() || len(report.Checks) != 2 || report.Checks["database"].Status != health.StatusOK {
+			t.Errorf("Expected both checks to pass, but got %+v", report)
+		}
+	})
+
+	// Test case: A failing or hanging check fails the report with its error
+	t.Run("Checker_Failure", func(t *testing.T) {
+		checker := health.NewChecker(50 * time.Millisecond)
+		checker.Add("database", func(ctx context.Context) error { return nil })
+		checker.Add("migrations", func(ctx context.Context) error { return errors.New("2 pending migrations") })
+		checker.Add("hanging", func(ctx context.Context) error { select {} })
+
+		report := checker.Run(context.Background())
+		if report.OK() {
+			t.Fatalf("Expected the report to fail, but got %+v", report)
+		}
+		if result := report.Checks["migrations"]; result.Status != health.StatusFail || result.Error != "2 pending migrations" {
+			t.Errorf("Expected the migrations check to fail with its error, but got %+v", result)
+		}
+		if result := report.Checks["hanging"]; result.Status != health.StatusFail || result.LatencyMs < 50 {
+			t.Errorf("Expected the hanging check to time out, but got %+v", result)
+		}
+		if result := report.Checks["database"]; result.Status != health.StatusOK {
+			t.Errorf("Expected the database check to pass, but got %+v", result)
+		}
+	})
+
+	// Test case: Liveness is ok without running the readiness checks
+	t.Run("Liveness_Success", func(t *testing.T) {
+		checker := health.NewChecker(time.Second)
+		checker.Add("database", func(ctx context.Context) error { return errors.New("connection refused") })
+
+		recorder := httptest.NewRecorder()
+		handler.NewHealthHandler(checker).Liveness(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		if recorder.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, but got %d", http.StatusOK, recorder.Code)
+		}
+	})
+
+	// Test case: Readiness returns 503 and the per check results when a check fails
+	t.Run("Readiness_Failure", func(t *testing.T) {
+		checker := health.NewChecker(time.Second)
+		checker.Add("database", func(ctx context.Context) error { return errors.New("connection refused") })
+		checker.Add("delivery_worker", func(ctx context.Context) error { return nil })
+
+		recorder := httptest.NewRecorder()
+		handler.NewHealthHandler(checker).Readiness(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		if recorder.Code != http.StatusServiceUnavailable {
+			t.Errorf("Expected status code %d, but got %d", http.StatusServiceUnavailable, recorder.Code)
+		}
+
+		var report health.Report
+		if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		if report.Status != health.StatusFail || report.Checks["database"].Error != "connection refused" || report.Checks["delivery_worker"].Status != health.StatusOK {
+			t.Errorf("Expected the database check to fail, but got %+v", report)
+		}
+	})
+
+	// Test case: The worker check passes only while the worker is polling
+	t.Run("WorkerCheck", func(t *testing.T) {
+		deliveryRepo := &mocks.MockDeliveryRepo{
+			ClaimDueDeliveriesFn: func(_ context.Context, now time.Time, limit int, lease time.Duration) ([]models.NotificationDelivery, error) {
+				return nil, nil
+			},
+		}
+		worker := notifier.NewWorker(deliveryRepo, &mocks.MockNotifier{}, notifier.WorkerOptions{Interval: time.Hour})
+		if err := worker.Check(context.Background()); err == nil {
+			t.Error("Expected an error before the worker runs")
+		}
+
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			worker.Run(context.Background(), stop)
+			close(done)
+		}()
+
+		deadline := time.Now().Add(time.Second)
+		for worker.Check(context.Background()) != nil && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if err := worker.Check(context.Background()); err != nil {
+			t.Errorf("Expected the running worker to pass, but got %v", err)
+		}
+
+		close(stop)
+		<-done
+		if err := worker.Check(context.Background()); err == nil {
+			t.Error("Expected an error after the worker stopped")
+		}
+	})
+
+	// Test case: Pending lists the migrations the database is missing
+	t.Run("Migrations_Pending", func(t *testing.T) {
+		sqlDB, err := newSQLiteDb(t).DB()
+		if err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := migrate.Load(db.Migrations, "migrations/sqlite")
+		if err != nil {
+			t.Fatal(err)
+		}
+		migrator := migrate.NewMigrator(sqlDB, loaded, migrate.Options{Driver: database.SQLite})
+
+		pending, err := migrator.Pending(context.Background())
+		if err != nil || len(pending) != 0 {
+			t.Fatalf("Expected no pending migrations, but got %v, %v", pending, err)
+		}
+
+		if _, err := migrator.Down(context.Background(), 1); err != nil {
+			t.Fatal(err)
+		}
+		pending, err = migrator.Pending(context.Background())
+		if err != nil || len(pending) != 1 || pending[0].Version != loaded[len(loaded)-1].Version {
+			t.Errorf("Expected the last migration to be pending, but got %v, %v", pending, err)
+		}
+	})
+}
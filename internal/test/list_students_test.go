@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"class-management/internal/dto"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/directory"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestListStudents(t *testing.T) {
+	// Create a new instance of the directory service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	directoryService := directory.NewDirectoryService(teacherRepo, studentRepo, &mocks.MockTeacherStudentsRepo{}, zap.NewNop())
+	directoryHandler := handler.NewDirectoryHandler(directoryService)
+
+	// Test case: List students with a status filter
+	t.Run("ListStudents_Success", func(t *testing.T) {
+		var receivedStatuses []models.StatusStudent
+		var receivedPage models.Page
+		studentRepo.ListStudentsFn = func(_ context.Context, statuses []models.StatusStudent, page models.Page) ([]models.Student, int64, error) {
+			receivedStatuses, receivedPage = statuses, page
+			return []models.Student{{ID: 1, Email: "studentmary@gmail.com", Status: models.StatusSuspended}}, 1, nil
+		}
+
+		req, err := http.NewRequest("GET", "/api/students?status=suspended", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(directoryHandler.ListStudents)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+		if !reflect.DeepEqual(receivedStatuses, []models.StatusStudent{models.StatusSuspended}) || receivedPage.Limit != 100 || receivedPage.Offset != 0 {
+			t.Errorf("Unexpected filter %v %+v", receivedStatuses, receivedPage)
+		}
+
+		var response dto.StudentListResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Students) != 1 || response.Students[0].Status != "SUSPENDED" || response.Total != 1 {
+			t.Errorf("Unexpected response %+v", response)
+		}
+	})
+
+	// Test case: Invalid status
+	t.Run("ListStudents_InvalidStatus", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/api/students?status=EXPELLED", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(directoryHandler.ListStudents)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"class-management/internal/dto"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/directory"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestListTeachers(t *testing.T) {
+	// Create a new instance of the directory service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	directoryService := directory.NewDirectoryService(teacherRepo, studentRepo, &mocks.MockTeacherStudentsRepo{}, zap.NewNop())
+	directoryHandler := handler.NewDirectoryHandler(directoryService)
+
+	// Test case: List a page of teachers
+	t.Run("ListTeachers_Success", func(t *testing.T) {
+		var received models.Page
+		teacherRepo.ListTeachersFn = func(_ context.Context, page models.Page) ([]models.Teacher, int64, error) {
+			received = page
+			return []models.Teacher{{ID: 2, Email: "teacherjoe@gmail.com"}, {ID: 1, Email: "teacherken@gmail.com"}}, 5, nil
+		}
+
+		req, err := http.NewRequest("GET", "/api/teachers?limit=2&offset=2", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(directoryHandler.ListTeachers)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+		if received.Limit != 2 || received.Offset != 2 {
+			t.Errorf("Expected page limit 2 offset 2, but got %+v", received)
+		}
+
+		var response dto.TeacherListResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Teachers) != 2 || response.Teachers[0].Email != "teacherjoe@gmail.com" || response.Total != 5 || response.Limit != 2 || response.Offset != 2 {
+			t.Errorf("Unexpected response %+v", response)
+		}
+	})
+
+	// Test case: Invalid page
+	t.Run("ListTeachers_InvalidPage", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/api/teachers?limit=5000", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(directoryHandler.ListTeachers)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"bytes"
+	"class-management/errors"
+	"class-management/internal/logging"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogging(t *testing.T) {
+	//Serve one request through the request ID and access log middlewares and return the recorded log entries
+	serve := func(requestID string, handler http.HandlerFunc) (*httptest.ResponseRecorder, []observer.LoggedEntry) {
+		core, logs := observer.New(zapcore.DebugLevel)
+		request := httptest.NewRequest(http.MethodGet, "/api/students/studentbob@gmail.com", nil)
+		if requestID != "" {
+			request.Header.Set(logging.RequestIDHeader, requestID)
+		}
+		recorder := httptest.NewRecorder()
+		logging.RequestID(zap.New(core))(logging.AccessLog(handler)).ServeHTTP(recorder, request)
+		return recorder, logs.AllUntimed()
+	}
+
+	// Test case: A request ID is generated, echoed and added to every log line
+	t.Run("RequestID_Generated", func(t *testing.T) {
+		recorder, entries := serve("", func(writer http.ResponseWriter, request *http.Request) {
+			logging.FromContext(request.Context()).Info("handled")
+		})
+
+		requestID := recorder.Header().Get(logging.RequestIDHeader)
+		if !regexp.MustCompile(`^[0-9a-f]{32}$`).MatchString(requestID) {
+			t.Fatalf("Expected a generated request ID, but got %q", requestID)
+		}
+		if len(entries) != 2 {
+			t.Fatalf("Expected the handler line and the access log line, but got %+v", entries)
+		}
+		for _, entry := range entries {
+			if entry.ContextMap()["request_id"] != requestID {
+				t.Errorf("Expected request ID %s on %q, but got %v", requestID, entry.Message, entry.ContextMap())
+			}
+		}
+	})
+
+	// Test case: The caller's request ID is kept, and an unsafe one replaced
+	t.Run("RequestID_Propagated", func(t *testing.T) {
+		recorder, _ := serve("checkout-42", func(writer http.ResponseWriter, request *http.Request) {
+			if logging.RequestIDFromContext(request.Context()) != "checkout-42" {
+				t.Errorf("Expected the request ID in the context, but got %q", logging.RequestIDFromContext(request.Context()))
+			}
+		})
+		if requestID := recorder.Header().Get(logging.RequestIDHeader); requestID != "checkout-42" {
+			t.Errorf("Expected request ID checkout-42, but got %q", requestID)
+		}
+
+		recorder, _ = serve("forged\" level=\"error", func(writer http.ResponseWriter, request *http.Request) {})
+		if requestID := recorder.Header().Get(logging.RequestIDHeader); requestID == "" || requestID == "forged\" level=\"error" {
+			t.Errorf("Expected the unsafe request ID to be replaced, but got %q", requestID)
+		}
+	})
+
+	// Test case: Error responses carry the request ID
+	t.Run("ErrorResponse_RequestID", func(t *testing.T) {
+		recorder, _ := serve("checkout-42", func(writer http.ResponseWriter, request *http.Request) {
+			errors.WriteError(writer, request, errors.ErrStudentNotExists)
+		})
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if body["request_id"] != "checkout-42" || body["detail"] != errors.ErrStudentNotExists.Message {
+			t.Errorf("Expected the error with the request ID, but got %v", body)
+		}
+	})
+
+	// Test case: The access log records the request and its outcome
+	t.Run("AccessLog_Success", func(t *testing.T) {
+		_, entries := serve("checkout-42", func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			writer.Write([]byte("down"))
+		})
+		if len(entries) != 1 {
+			t.Fatalf("Expected 1 access log line, but got %+v", entries)
+		}
+
+		fields := entries[0].ContextMap()
+		if entries[0].Level != zapcore.ErrorLevel || fields["status"] != int64(503) || fields["bytes"] != int64(4) ||
+			fields["method"] != "GET" || fields["path"] != "/api/students/studentbob@gmail.com" {
+			t.Errorf("Expected an error level line for the 503, but got %v %v", entries[0].Level, fields)
+		}
+	})
+
+	// Test case: Failed queries are logged as errors with the logger of the query context
+	t.Run("GormLogger_Error", func(t *testing.T) {
+		core, logs := observer.New(zapcore.DebugLevel)
+		db := newSQLiteDb(t)
+		db.Logger = logging.NewGormLogger(zap.NewNop())
+
+		ctx := logging.WithLogger(context.Background(), zap.New(core).With(zap.String("request_id", "checkout-42")))
+		if err := db.WithContext(ctx).Exec("SELECT * FROM missing_table").Error; err == nil {
+			t.Fatal("Expected an error for a missing table")
+		}
+
+		failed := logs.FilterMessage("query failed").AllUntimed()
+		if len(failed) != 1 || failed[0].Level != zapcore.ErrorLevel || failed[0].ContextMap()["request_id"] != "checkout-42" {
+			t.Errorf("Expected 1 failed query logged with the request ID, but got %+v", logs.AllUntimed())
+		}
+	})
+
+	// Test case: The logger writes JSON lines at or above its level
+	t.Run("New_Level", func(t *testing.T) {
+		var buffer bytes.Buffer
+		logger, err := logging.New(&buffer, "warn")
+		if err != nil {
+			t.Fatal(err)
+		}
+		logger.Info("dropped")
+		logger.Warn("kept", zap.String("email", "studentbob@gmail.com"))
+
+		var line map[string]interface{}
+		if err := json.Unmarshal(buffer.Bytes(), &line); err != nil {
+			t.Fatalf("Expected a single JSON line, but got %q", buffer.String())
+		}
+		if line["level"] != "warn" || line["message"] != "kept" || line["email"] != "studentbob@gmail.com" {
+			t.Errorf("Expected the warn line, but got %v", line)
+		}
+
+		if _, err := logging.New(&buffer, "verbose"); err == nil {
+			t.Error("Expected an error for an unknown level")
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/dto"
+	"class-management/internal/metrics"
+	"class-management/internal/models"
+	"class-management/internal/service/teacher"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//Sum of the counter values, or of the histogram sample counts, of the series of name matching labels
+func metricValue(t *testing.T, registry *prometheus.Registry, name string, labels map[string]string) float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var total float64
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	series:
+		for _, metric := range family.GetMetric() {
+			for key, value := range labels {
+				found := false
+				for _, pair := range metric.GetLabel() {
+					if pair.GetName() == key && pair.GetValue() == value {
+						found = true
+					}
+				}
+				if !found {
+					continue series
+				}
+			}
+			if metric.GetCounter() != nil {
+				total += metric.GetCounter().GetValue()
+			}
+			if metric.GetHistogram() != nil {
+				total += float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return total
+}
+
+//TeacherService returning canned results, methods not overridden panic
+type stubTeacherService struct {
+	teacher.TeacherService
+	registerResponse dto.RegistrationResponse
+	fetchResponse    dto.FetchStudentsForNotificationResponse
+	err              error
+}
+
+func (s *stubTeacherService) RegisterStudents(context.Context, dto.RegisterStudentsRequest) (dto.RegistrationResponse, error) {
+	return s.registerResponse, s.err
+}
+
+func (s *stubTeacherService) SuspendStudent(context.Context, dto.SuspendRequest) error {
+	return s.err
+}
+
+func (s *stubTeacherService) ChangeStudentStatus(context.Context, dto.ChangeStudentStatusRequest) error {
+	return s.err
+}
+
+func (s *stubTeacherService) FetchStudentsForNotification(context.Context, dto.FetchStudentsForNotificationRequest) (dto.FetchStudentsForNotificationResponse, error) {
+	return s.fetchResponse, s.err
+}
+
+func TestMetrics(t *testing.T) {
+	// Test case: Requests are counted by route template, method and status
+	t.Run("Middleware_Success", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		router := mux.NewRouter()
+		router.Use(metrics.Middleware(metrics.New(registry)))
+		router.HandleFunc("/api/students/{email}", func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusNotFound)
+		}).Methods(http.MethodGet)
+
+		for _, email := range []string{"studentbob@gmail.com", "studentjon@gmail.com"} {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/students/"+email, nil))
+		}
+
+		labels := map[string]string{"route": "/api/students/{email}", "method": "GET", "status": "404"}
+		if count := metricValue(t, registry, "http_requests_total", labels); count != 2 {
+			t.Errorf("Expected 2 requests counted under the route template, but got %v", count)
+		}
+		if count := metricValue(t, registry, "http_request_duration_seconds", labels); count != 2 {
+			t.Errorf("Expected 2 latency samples, but got %v", count)
+		}
+	})
+
+	// Test case: Service calls are timed and successful ones counted as business events
+	t.Run("TeacherService_Success", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		service := metrics.NewTeacherService(&stubTeacherService{
+			registerResponse: dto.RegistrationResponse{Results: []dto.RegistrationResult{
+				{Email: "studentbob@gmail.com", Status: dto.RegistrationCreated},
+				{Email: "studentjon@gmail.com", Status: dto.RegistrationLinked},
+				{Email: "studenthon@gmail.com", Status: dto.RegistrationAlreadyRegistered},
+			}},
+			fetchResponse: dto.FetchStudentsForNotificationResponse{Recipients: []string{"studentbob@gmail.com", "studentjon@gmail.com"}},
+		}, metrics.New(registry))
+
+		service.RegisterStudents(context.Background(), dto.RegisterStudentsRequest{})
+		service.SuspendStudent(context.Background(), dto.SuspendRequest{})
+		service.ChangeStudentStatus(context.Background(), dto.ChangeStudentStatusRequest{Status: string(models.StatusSuspended)})
+		service.ChangeStudentStatus(context.Background(), dto.ChangeStudentStatusRequest{Status: string(models.StatusActive)})
+		service.FetchStudentsForNotification(context.Background(), dto.FetchStudentsForNotificationRequest{})
+
+		expected := map[string]float64{
+			"students_registered_total":              2,
+			"student_suspensions_total":              2,
+			"notification_recipients_resolved_total": 2,
+		}
+		for name, value := range expected {
+			if count := metricValue(t, registry, name, nil); count != value {
+				t.Errorf("Expected %s to be %v, but got %v", name, value, count)
+			}
+		}
+		if count := metricValue(t, registry, "teacher_service_duration_seconds", map[string]string{"method": "ChangeStudentStatus", "outcome": "success"}); count != 2 {
+			t.Errorf("Expected 2 ChangeStudentStatus samples, but got %v", count)
+		}
+	})
+
+	// Test case: Failed calls are timed with the error outcome and count no business events
+	t.Run("TeacherService_Error", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		service := metrics.NewTeacherService(&stubTeacherService{err: errors.ErrStudentNotExists}, metrics.New(registry))
+
+		service.SuspendStudent(context.Background(), dto.SuspendRequest{})
+
+		if count := metricValue(t, registry, "student_suspensions_total", nil); count != 0 {
+			t.Errorf("Expected no suspension counted, but got %v", count)
+		}
+		if count := metricValue(t, registry, "teacher_service_duration_seconds", map[string]string{"method": "SuspendStudent", "outcome": "error"}); count != 1 {
+			t.Errorf("Expected 1 failed SuspendStudent sample, but got %v", count)
+		}
+	})
+
+	// Test case: GORM queries are timed by operation and table
+	t.Run("GormPlugin_Success", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		db := newSQLiteDb(t)
+		if err := db.Use(metrics.New(registry).GormPlugin()); err != nil {
+			t.Fatal(err)
+		}
+
+		repo := models.NewTeacherRepo(db)
+		if _, err := repo.CreateTeacher(context.Background(), &models.Teacher{Email: "teacherken@gmail.com"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.GetTeacherByEmail(context.Background(), "teacherken@gmail.com"); err != nil {
+			t.Fatal(err)
+		}
+
+		if count := metricValue(t, registry, "db_query_duration_seconds", map[string]string{"operation": "create", "table": "teachers", "outcome": "success"}); count != 1 {
+			t.Errorf("Expected 1 create on teachers, but got %v", count)
+		}
+		if count := metricValue(t, registry, "db_query_duration_seconds", map[string]string{"operation": "query", "table": "teachers"}); count < 1 {
+			t.Errorf("Expected a query on teachers, but got %v", count)
+		}
+	})
+}
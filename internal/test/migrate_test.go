@@ -0,0 +1,297 @@
+package handler
+
+import (
+	"bytes"
+	"class-management/db"
+	"class-management/internal/database"
+	"class-management/internal/migrate"
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations := []migrate.Migration{
+		{Version: 1, Name: "create_teachers", Up: "CREATE TABLE teachers (id INT);", Down: "DROP TABLE teachers;"},
+		{Version: 2, Name: "create_students", Up: "CREATE TABLE students (id INT);\nCREATE INDEX index_students_id ON students (id);", Down: "DROP TABLE students;"},
+	}
+
+	tableExists := regexp.QuoteMeta("SELECT COUNT(*) FROM information_schema.tables")
+	appliedVersions := regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")
+
+	// Test case: The embedded migrations of every driver load in version order with both directions and the same versions
+	t.Run("Load_Embedded", func(t *testing.T) {
+		var expected []string
+		for _, driver := range []database.Driver{database.MySQL, database.Postgres, database.SQLite} {
+			loaded, err := migrate.Load(db.Migrations, "migrations/"+string(driver))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(loaded) == 0 {
+				t.Fatalf("Expected embedded %s migrations", driver)
+			}
+
+			var labels []string
+			for i, migration := range loaded {
+				if i > 0 && loaded[i-1].Version >= migration.Version {
+					t.Errorf("Expected ascending versions, but got %d after %d", migration.Version, loaded[i-1].Version)
+				}
+				if migration.Up == "" || migration.Down == "" {
+					t.Errorf("Expected %s %s to have up and down SQL", driver, migration.Label())
+				}
+				labels = append(labels, migration.Label())
+			}
+
+			if expected == nil {
+				expected = labels
+			} else if !reflect.DeepEqual(labels, expected) {
+				t.Errorf("Expected %s migrations %v, but got %v", driver, expected, labels)
+			}
+		}
+	})
+
+	// Test case: Files sharing a version with different names are rejected
+	t.Run("Load_DuplicateVersion", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"migrations/0001_create_teachers.up.sql": {Data: []byte("CREATE TABLE teachers (id INT);")},
+			"migrations/0001_create_students.up.sql": {Data: []byte("CREATE TABLE students (id INT);")},
+		}
+		if _, err := migrate.Load(fsys, "migrations"); err == nil {
+			t.Error("Expected an error for a duplicate version")
+		}
+	})
+
+	// Test case: A down file without an up file is rejected
+	t.Run("Load_MissingUp", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"migrations/0001_create_teachers.down.sql": {Data: []byte("DROP TABLE teachers;")},
+		}
+		if _, err := migrate.Load(fsys, "migrations"); err == nil {
+			t.Error("Expected an error for a missing up file")
+		}
+	})
+
+	// Test case: Unrecognised file names are rejected
+	t.Run("Load_InvalidName", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"migrations/create_teachers.sql": {Data: []byte("CREATE TABLE teachers (id INT);")},
+		}
+		if _, err := migrate.Load(fsys, "migrations"); err == nil {
+			t.Error("Expected an error for an invalid file name")
+		}
+	})
+
+	// Test case: Statements split on semicolons outside quotes and comments
+	t.Run("SplitStatements", func(t *testing.T) {
+		sql := "-- create; the table\nCREATE TABLE t (name VARCHAR(10) DEFAULT 'a;b');\n/* ; */INSERT INTO t VALUES ('it''s; fine');\n\n"
+		expected := []string{
+			"CREATE TABLE t (name VARCHAR(10) DEFAULT 'a;b')",
+			"INSERT INTO t VALUES ('it''s; fine')",
+		}
+
+		statements := migrate.SplitStatements(sql)
+		if !reflect.DeepEqual(statements, expected) {
+			t.Errorf("Expected %q, but got %q", expected, statements)
+		}
+	})
+
+	// Test case: Up applies pending migrations under the lock and records them
+	t.Run("Up_Success", func(t *testing.T) {
+		sqlDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sqlDB.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT GET_LOCK(?, ?)")).WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(1))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migrations")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(tableExists).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(appliedVersions).WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE students (id INT)")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE INDEX index_students_id ON students (id)")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version, name) VALUES (?, ?)")).WithArgs(2, "create_students").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT RELEASE_LOCK(?)")).WillReturnRows(sqlmock.NewRows([]string{"released"}).AddRow(1))
+
+		done, err := migrate.NewMigrator(sqlDB, migrations, migrate.Options{}).Up(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(done) != 1 || done[0].Version != 2 {
+			t.Errorf("Expected only version 2 to be applied, but got %+v", done)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// Test case: Up refuses to run while another run holds the lock
+	t.Run("Up_Locked", func(t *testing.T) {
+		sqlDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sqlDB.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT GET_LOCK(?, ?)")).WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(0))
+
+		_, err = migrate.NewMigrator(sqlDB, migrations, migrate.Options{}).Up(context.Background())
+		if err != migrate.ErrLocked {
+			t.Errorf("Expected %v, but got %v", migrate.ErrLocked, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// Test case: Dry-run prints the pending SQL without locking, creating or executing anything
+	t.Run("Up_DryRun", func(t *testing.T) {
+		sqlDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sqlDB.Close()
+
+		mock.ExpectQuery(tableExists).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		var out bytes.Buffer
+		done, err := migrate.NewMigrator(sqlDB, migrations, migrate.Options{DryRun: true, Out: &out}).Up(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(done) != 2 {
+			t.Errorf("Expected 2 pending migrations, but got %d", len(done))
+		}
+		for _, expected := range []string{"-- 0001_create_teachers (up)", "CREATE TABLE teachers (id INT);", "CREATE INDEX index_students_id ON students (id);"} {
+			if !strings.Contains(out.String(), expected) {
+				t.Errorf("Expected dry-run output to contain %q, but got %q", expected, out.String())
+			}
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// Test case: Down reverts the newest applied migration and removes its record
+	t.Run("Down_Success", func(t *testing.T) {
+		sqlDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sqlDB.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT GET_LOCK(?, ?)")).WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(1))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migrations")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(tableExists).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(appliedVersions).WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()).AddRow(2, time.Now()))
+		mock.ExpectExec(regexp.QuoteMeta("DROP TABLE students")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations WHERE version = ?")).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT RELEASE_LOCK(?)")).WillReturnRows(sqlmock.NewRows([]string{"released"}).AddRow(1))
+
+		done, err := migrate.NewMigrator(sqlDB, migrations, migrate.Options{}).Down(context.Background(), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(done) != 1 || done[0].Version != 2 {
+			t.Errorf("Expected only version 2 to be reverted, but got %+v", done)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// Test case: Status reports applied and pending migrations
+	t.Run("Status_Success", func(t *testing.T) {
+		sqlDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sqlDB.Close()
+
+		mock.ExpectQuery(tableExists).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(appliedVersions).WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+
+		statuses, err := migrate.NewMigrator(sqlDB, migrations, migrate.Options{}).Status(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(statuses) != 2 || !statuses[0].Applied || statuses[1].Applied {
+			t.Errorf("Expected version 1 applied and version 2 pending, but got %+v", statuses)
+		}
+	})
+
+	// Test case: The SQLite migrations apply and revert against a real database
+	t.Run("SQLite_UpDown", func(t *testing.T) {
+		gormDB := newSQLiteDb(t)
+		sqlDB, err := gormDB.DB()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		loaded, err := migrate.Load(db.Migrations, "migrations/sqlite")
+		if err != nil {
+			t.Fatal(err)
+		}
+		migrator := migrate.NewMigrator(sqlDB, loaded, migrate.Options{Driver: database.SQLite})
+
+		done, err := migrator.Down(context.Background(), len(loaded))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(done) != len(loaded) {
+			t.Errorf("Expected %d reverted migrations, but got %d", len(loaded), len(done))
+		}
+
+		statuses, err := migrator.Status(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, status := range statuses {
+			if status.Applied {
+				t.Errorf("Expected %s to be reverted", status.Label())
+			}
+		}
+	})
+
+	// Test case: A database created before migrations, with only the baseline tables, adopts every migration
+	t.Run("SQLite_AdoptBaseline", func(t *testing.T) {
+		gormDB, err := database.Open(database.Config{Driver: database.SQLite, Path: database.SQLiteMemory})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sqlDB, err := gormDB.DB()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sqlDB.Close()
+
+		loaded, err := migrate.Load(db.Migrations, "migrations/sqlite")
+		if err != nil {
+			t.Fatal(err)
+		}
+		//the baseline schema and data, as db/init.sql left them, without schema_migrations
+		baseline := loaded[0].Up + "\nINSERT INTO students (email) VALUES ('studentjon@gmail.com');"
+		if _, err := sqlDB.Exec(baseline); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := migrate.NewMigrator(sqlDB, loaded, migrate.Options{Driver: database.SQLite}).Up(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		var studentNumber *string
+		var gradeLevel string
+		row := sqlDB.QueryRow("SELECT student_number, grade_level FROM students WHERE email = 'studentjon@gmail.com'")
+		if err := row.Scan(&studentNumber, &gradeLevel); err != nil {
+			t.Fatalf("Expected the profile columns to be added, but got %v", err)
+		}
+		if _, err := sqlDB.Exec("SELECT archived_at, deleted_at FROM teacher_students"); err != nil {
+			t.Errorf("Expected the enrolment history columns to be added, but got %v", err)
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"bytes"
+	"class-management/internal/notifier"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotifiers(t *testing.T) {
+	message := notifier.Message{
+		NotificationID: 1,
+		From:           "teacherken@gmail.com",
+		To:             "studentbob@gmail.com",
+		Subject:        "New notification from teacherken@gmail.com",
+		Body:           "Hey everybody",
+	}
+
+	// Test case: The log notifier writes the message as a JSON line
+	t.Run("LogNotifier_Success", func(t *testing.T) {
+		var buffer bytes.Buffer
+		if err := notifier.NewLogNotifier(&buffer).Send(context.Background(), message); err != nil {
+			t.Fatal(err)
+		}
+
+		var logged notifier.Message
+		if err := json.Unmarshal(buffer.Bytes(), &logged); err != nil {
+			t.Fatal(err)
+		}
+		if logged != message {
+			t.Errorf("Expected %+v, but got %+v", message, logged)
+		}
+	})
+
+	// Test case: The webhook notifier posts the message
+	t.Run("WebhookNotifier_Success", func(t *testing.T) {
+		var received notifier.Message
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			json.NewDecoder(request.Body).Decode(&received)
+			writer.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		if err := notifier.NewWebhookNotifier(server.URL, nil).Send(context.Background(), message); err != nil {
+			t.Fatal(err)
+		}
+		if received != message {
+			t.Errorf("Expected %+v, but got %+v", message, received)
+		}
+	})
+
+	// Test case: A non 2xx webhook response is a failed delivery
+	t.Run("WebhookNotifier_ErrorStatus", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		if err := notifier.NewWebhookNotifier(server.URL, nil).Send(context.Background(), message); err == nil {
+			t.Error("Expected an error for a 503 response")
+		}
+	})
+
+	// Test case: The webhook notifier gives up once the context is done
+	t.Run("WebhookNotifier_Deadline", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		if err := notifier.NewWebhookNotifier(server.URL, nil).Send(ctx, message); err == nil {
+			t.Error("Expected an error for a webhook that doesn't respond")
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("Expected the send to stop at the deadline, but it took %s", elapsed)
+		}
+	})
+
+	// Test case: The SMTP notifier sends the message as an email
+	t.Run("SMTPNotifier_Success", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer listener.Close()
+
+		received := make(chan string, 1)
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			text := textproto.NewConn(conn)
+			text.PrintfLine("220 localhost ready")
+			for {
+				line, err := text.ReadLine()
+				if err != nil {
+					return
+				}
+				switch command := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); command {
+				case "EHLO", "HELO", "MAIL", "RCPT":
+					text.PrintfLine("250 OK")
+				case "DATA":
+					text.PrintfLine("354 Go ahead")
+					data, _ := text.ReadDotLines()
+					received <- strings.Join(data, "\n")
+					text.PrintfLine("250 OK")
+				case "QUIT":
+					text.PrintfLine("221 Bye")
+					return
+				default:
+					text.PrintfLine("502 Unknown command")
+				}
+			}
+		}()
+
+		host, port, _ := net.SplitHostPort(listener.Addr().String())
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := notifier.NewSMTPNotifier(host, port, "", "", "school@gmail.com").Send(ctx, message); err != nil {
+			t.Fatal(err)
+		}
+		if email := <-received; !strings.Contains(email, "To: studentbob@gmail.com") || !strings.Contains(email, "Hey everybody") {
+			t.Errorf("Unexpected email %q", email)
+		}
+	})
+
+	// Test case: The SMTP notifier gives up on a server that doesn't answer once the context is done
+	t.Run("SMTPNotifier_Deadline", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer listener.Close()
+		go func() {
+			//accept the connection but never greet
+			conn, err := listener.Accept()
+			if err == nil {
+				defer conn.Close()
+				time.Sleep(5 * time.Second)
+			}
+		}()
+
+		host, port, _ := net.SplitHostPort(listener.Addr().String())
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		if err := notifier.NewSMTPNotifier(host, port, "", "", "school@gmail.com").Send(ctx, message); err == nil {
+			t.Error("Expected an error for a server that doesn't answer")
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("Expected the send to stop at the deadline, but it took %s", elapsed)
+		}
+	})
+
+	// Test case: Unknown or incomplete notifier config is rejected
+	t.Run("NewNotifier_InvalidConfig", func(t *testing.T) {
+		for _, config := range []notifier.Config{{Kind: "pigeon"}, {Kind: "smtp"}, {Kind: "webhook"}} {
+			if _, err := notifier.New(config); err == nil {
+				t.Errorf("Expected an error for config %+v", config)
+			}
+		}
+	})
+}
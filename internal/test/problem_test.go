@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"class-management/errors"
+	"class-management/internal/handler"
+	"class-management/internal/logging"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/directory"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestProblem(t *testing.T) {
+	//Write err for a request with the given request ID and decode the problem details body
+	write := func(err error) (*httptest.ResponseRecorder, errors.Problem) {
+		request := httptest.NewRequest(http.MethodGet, "/api/teachers/teacherken@gmail.com?limit=5", nil)
+		request.Header.Set(logging.RequestIDHeader, "checkout-42")
+		recorder := httptest.NewRecorder()
+		logging.RequestID(zap.NewNop())(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			errors.WriteError(writer, request, err)
+		})).ServeHTTP(recorder, request)
+
+		var problem errors.Problem
+		if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+			t.Fatal(err)
+		}
+		return recorder, problem
+	}
+
+	// Test case: A domain error is written as problem details with its status and code
+	t.Run("WriteError_DomainError", func(t *testing.T) {
+		recorder, problem := write(errors.ErrTeacherNotExists)
+
+		if recorder.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d, but got %d", http.StatusNotFound, recorder.Code)
+		}
+		if contentType := recorder.Header().Get("Content-Type"); contentType != "application/problem+json" {
+			t.Errorf("Expected content type application/problem+json, but got %s", contentType)
+		}
+		expected := errors.Problem{
+			Type:      "about:blank",
+			Title:     "Not Found",
+			Status:    http.StatusNotFound,
+			Detail:    errors.ErrTeacherNotExists.Message,
+			Instance:  "/api/teachers/teacherken@gmail.com",
+			Code:      "TEACHER_NOT_FOUND",
+			RequestID: "checkout-42",
+		}
+		if problem != expected {
+			t.Errorf("Expected %+v, but got %+v", expected, problem)
+		}
+	})
+
+	// Test case: Internal errors are redacted and context errors get their own status
+	t.Run("WriteError_Classification", func(t *testing.T) {
+		tests := []struct {
+			err    error
+			status int
+			code   string
+		}{
+			{fmt.Errorf("Error 1045 (28000): Access denied for user 'root'@'10.0.0.5'"), http.StatusInternalServerError, "INTERNAL_ERROR"},
+			{fmt.Errorf("listing students: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, "REQUEST_TIMEOUT"},
+			{context.Canceled, errors.StatusClientClosedRequest, "REQUEST_CANCELLED"},
+			{fmt.Errorf("changing status: %w", errors.ErrInvalidStatusTransition), http.StatusConflict, "INVALID_STATUS_TRANSITION"},
+		}
+		for _, test := range tests {
+			recorder, problem := write(test.err)
+			if recorder.Code != test.status || problem.Status != test.status || problem.Code != test.code {
+				t.Errorf("Expected %d %s for %v, but got %d %+v", test.status, test.code, test.err, recorder.Code, problem)
+			}
+			if problem.Title == "" {
+				t.Errorf("Expected a title for %v", test.err)
+			}
+			if strings.Contains(recorder.Body.String(), "Access denied") {
+				t.Errorf("Expected the internal error to be redacted, but got %s", recorder.Body.String())
+			}
+		}
+	})
+
+	// Test case: A repository failure reaches the client as a redacted internal error
+	t.Run("Handler_RepositoryFailure", func(t *testing.T) {
+		teacherRepo := &mocks.MockTeacherRepo{TeacherByEmailFn: func(_ context.Context, email string) (*models.Teacher, error) {
+			return nil, fmt.Errorf("dial tcp 10.0.0.5:3306: connect: connection refused")
+		}}
+		directoryHandler := handler.NewDirectoryHandler(directory.NewDirectoryService(teacherRepo, &mocks.MockStudentRepo{}, &mocks.MockTeacherStudentsRepo{}, zap.NewNop()))
+
+		req, err := http.NewRequest("GET", "/api/teachers/teacherken@gmail.com", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "teacherken@gmail.com"})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(directoryHandler.GetTeacher).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusInternalServerError {
+			t.Errorf("Expected status code %d, but got %d", http.StatusInternalServerError, rr.Code)
+		}
+		if strings.Contains(rr.Body.String(), "10.0.0.5") || !strings.Contains(rr.Body.String(), `"code":"INTERNAL_ERROR"`) {
+			t.Errorf("Expected a redacted internal error, but got %s", rr.Body.String())
+		}
+	})
+
+	// Test case: Unknown endpoints and methods are answered with problem details
+	t.Run("Router_NotFoundAndMethodNotAllowed", func(t *testing.T) {
+		router := mux.NewRouter()
+		router.NotFoundHandler = http.HandlerFunc(handler.NotFound)
+		router.MethodNotAllowedHandler = http.HandlerFunc(handler.MethodNotAllowed)
+		router.HandleFunc("/api/teachers", func(writer http.ResponseWriter, request *http.Request) {}).Methods(http.MethodGet)
+
+		for path, expected := range map[string]string{"/api/unknown": "ROUTE_NOT_FOUND", "/api/teachers": "METHOD_NOT_ALLOWED"} {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, path, nil))
+
+			var problem errors.Problem
+			if err := json.Unmarshal(rr.Body.Bytes(), &problem); err != nil {
+				t.Fatal(err)
+			}
+			if problem.Code != expected || rr.Code != problem.Status {
+				t.Errorf("Expected %s for %s, but got %d %+v", expected, path, rr.Code, problem)
+			}
+		}
+	})
+}
@@ -2,19 +2,21 @@ package handler
 
 import (
 	"bytes"
+	"class-management/internal/auth"
+	"class-management/internal/dto"
 	"class-management/internal/handler"
 	"class-management/internal/mocks"
 	"class-management/internal/models"
 	"class-management/internal/service/teacher"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
-	"github.com/DATA-DOG/go-sqlmock"
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
+	"go.uber.org/zap"
 )
 
 func toJSON(data interface{}) string {
@@ -22,41 +24,31 @@ func toJSON(data interface{}) string {
 	return string(jsonBytes)
 }
 
-func TestRegisterStudent(t *testing.T) {
-	mockDB, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("Failed to create mock database: %v", err)
-	}
-	defer mockDB.Close()
-
-	// Set up the mock expectations
-	mock.ExpectExec(".*").WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectCommit()
-
-	// Create a new gorm.DB instance using the mockDB connection
-	db, err := gorm.Open(mysql.New(mysql.Config{
-		Conn:                      mockDB,
-		SkipInitializeWithVersion: true,
-	}), &gorm.Config{})
-	if err != nil {
-		t.Fatalf("Failed to create gorm.DB instance: %v", err)
-	}
+// asAdmin runs the handler with an admin identity, as set by the auth middleware
+func asAdmin(handlerFn http.HandlerFunc) http.HandlerFunc {
+	return asIdentity(handlerFn, auth.Identity{Role: auth.RoleAdmin})
+}
 
-	// Set the DB instance in the model package
-	models.DB = db
+// asIdentity runs the handler with the given caller identity, as set by the auth middleware
+func asIdentity(handlerFn http.HandlerFunc, identity auth.Identity) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		handlerFn(writer, request.WithContext(auth.WithIdentity(request.Context(), identity)))
+	}
+}
 
+func TestRegisterStudent(t *testing.T) {
 	// Create a new instance of the teacher service and mock the dependencies
 	teacherRepo := &mocks.MockTeacherRepo{}
 	studentRepo := &mocks.MockStudentRepo{}
 	teacherStudentRepo := &mocks.MockTeacherStudentsRepo{}
-	teacherService := teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo)
+	classRepo := &mocks.MockClassRepo{}
+	notificationRepo := &mocks.MockNotificationRepo{}
+	unitOfWork := mocks.NewMockUnitOfWork(teacherRepo, studentRepo, teacherStudentRepo, classRepo, notificationRepo)
+	teacherService := teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo, classRepo, unitOfWork, zap.NewNop())
 	teacherHandler := handler.NewTeacherHandler(teacherService)
 
 	// Test case: Registering one student successfully
 	t.Run("RegisterStudents_Success", func(t *testing.T) {
-		// Reset the expectations on the mock DB
-		mock.ExpectationsWereMet()
-
 		// Prepare the request payload
 		teacherEmail := "teacher@example.com"
 		studentEmails := []string{"student1@example.com", "student2@example.com"}
@@ -72,12 +64,12 @@ func TestRegisterStudent(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.RegisterStudents)
+		handler := asAdmin(teacherHandler.RegisterStudents)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
-		if rr.Code != http.StatusNoContent {
-			t.Errorf("Expected status code %d, but got %d", http.StatusNoContent, rr.Code)
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
 		}
 	})
 
@@ -85,7 +77,7 @@ func TestRegisterStudent(t *testing.T) {
 	t.Run("RegisterMultipleStudents_Success", func(t *testing.T) {
 		// Prepare the request payload
 		teacherEmail := "t1@gmail.com"
-		_, err := teacherRepo.CreateTeacher(&models.Teacher{
+		_, err := teacherRepo.CreateTeacher(context.Background(), &models.Teacher{
 			Email: teacherEmail,
 		})
 		if err != nil {
@@ -105,11 +97,11 @@ func TestRegisterStudent(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.RegisterStudents)
+		handler := asAdmin(teacherHandler.RegisterStudents)
 		handler.ServeHTTP(rr, req)
 		// Check the response status code
-		if rr.Code != http.StatusNoContent {
-			t.Errorf("Expected status code %d, but got %d", http.StatusNoContent, rr.Code)
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
 		}
 	})
 
@@ -129,7 +121,7 @@ func TestRegisterStudent(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.RegisterStudents)
+		handler := asAdmin(teacherHandler.RegisterStudents)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
@@ -154,7 +146,7 @@ func TestRegisterStudent(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.RegisterStudents)
+		handler := asAdmin(teacherHandler.RegisterStudents)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
@@ -167,7 +159,7 @@ func TestRegisterStudent(t *testing.T) {
 	t.Run("RegisterMissingStudentEmails_BadRequest", func(t *testing.T) {
 		// Prepare the request payload
 		teacherEmail := "t1@gmail.com"
-		_, err := teacherRepo.CreateTeacher(&models.Teacher{
+		_, err := teacherRepo.CreateTeacher(context.Background(), &models.Teacher{
 			Email: teacherEmail,
 			// Set other properties of the teacher
 		})
@@ -186,7 +178,7 @@ func TestRegisterStudent(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.RegisterStudents)
+		handler := asAdmin(teacherHandler.RegisterStudents)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
@@ -212,7 +204,7 @@ func TestRegisterStudent(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.RegisterStudents)
+		handler := asAdmin(teacherHandler.RegisterStudents)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
@@ -238,7 +230,7 @@ func TestRegisterStudent(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.RegisterStudents)
+		handler := asAdmin(teacherHandler.RegisterStudents)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
@@ -251,7 +243,7 @@ func TestRegisterStudent(t *testing.T) {
 	t.Run("RegisterEmptyStudentEmails_BadRequest", func(t *testing.T) {
 		// Prepare the request payload with empty student emails
 		teacherEmail := "t1@gmail.com"
-		_, err := teacherRepo.CreateTeacher(&models.Teacher{
+		_, err := teacherRepo.CreateTeacher(context.Background(), &models.Teacher{
 			Email: teacherEmail,
 			// Set other properties of the teacher
 		})
@@ -270,12 +262,256 @@ func TestRegisterStudent(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.RegisterStudents)
+		handler := asAdmin(teacherHandler.RegisterStudents)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+
+	// Test case: Every student is reported with its registration outcome
+	t.Run("RegisterStudents_ReportsResultPerEmail", func(t *testing.T) {
+		studentRepo.GetStudentByEmailFn = func(_ context.Context, email string) (*models.Student, error) {
+			switch email {
+			case "newstudent@gmail.com":
+				return nil, nil
+			case "brokenstudent@gmail.com":
+				return nil, errors.New("connection reset")
+			case "linkedstudent@gmail.com":
+				return &models.Student{ID: 2, Email: email, Status: models.StatusActive}, nil
+			}
+			return &models.Student{ID: 3, Email: email, Status: models.StatusActive}, nil
+		}
+		studentRepo.CreateStudentFn = func(_ context.Context, student *models.Student) (*models.Student, error) {
+			student.ID = 1
+			return student, nil
+		}
+		teacherStudentRepo.IsStudentRegisteredForTeacherFn = func(_ context.Context, teacherID uint, studentID uint) (*models.TeacherStudent, error) {
+			if studentID == 3 {
+				return &models.TeacherStudent{ID: 1, TeacherID: teacherID, StudentID: studentID}, nil
+			}
+			return nil, nil
+		}
+		teacherStudentRepo.CreateTeacherStudentFn = func(context.Context, *models.TeacherStudent) error {
+			return nil
+		}
+		defer func() {
+			studentRepo.GetStudentByEmailFn = nil
+			studentRepo.CreateStudentFn = nil
+			teacherStudentRepo.IsStudentRegisteredForTeacherFn = nil
+			teacherStudentRepo.CreateTeacherStudentFn = nil
+		}()
+
+		// Prepare the request payload
+		payload := []byte(`{"teacher": "teacherken@gmail.com", "students": ["newstudent@gmail.com", "linkedstudent@gmail.com", "oldstudent@gmail.com", "not-an-email", "brokenstudent@gmail.com"]}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/register", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.RegisterStudents)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+
+		// Check the result of every email
+		var response dto.RegistrationResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		expected := []dto.RegistrationStatus{
+			dto.RegistrationCreated,
+			dto.RegistrationLinked,
+			dto.RegistrationAlreadyRegistered,
+			dto.RegistrationInvalid,
+			dto.RegistrationFailed,
+		}
+		if len(response.Results) != len(expected) {
+			t.Fatalf("Expected %d results, but got %d", len(expected), len(response.Results))
+		}
+		for i, status := range expected {
+			if response.Results[i].Status != status {
+				t.Errorf("Expected %s to be %s, but got %s", response.Results[i].Email, status, response.Results[i].Status)
+			}
+		}
+	})
+
+	// Test case: Strict mode rejects the whole batch when an email is invalid
+	t.Run("RegisterStudentsStrict_RejectsInvalidBatch", func(t *testing.T) {
+		created := false
+		studentRepo.CreateStudentFn = func(_ context.Context, student *models.Student) (*models.Student, error) {
+			created = true
+			return student, nil
+		}
+		defer func() { studentRepo.CreateStudentFn = nil }()
+
+		// Prepare the request payload
+		payload := []byte(`{"teacher": "teacherken@gmail.com", "students": ["studentjon@gmail.com", "not-an-email"], "strict": true}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/register", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.RegisterStudents)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
 		if rr.Code != http.StatusUnprocessableEntity {
 			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
 		}
+
+		// Check the invalid email is reported and nothing was registered
+		var response dto.RegistrationRejectedResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Results) != 1 || response.Results[0].Email != "not-an-email" {
+			t.Errorf("Expected only not-an-email to be reported, but got %v", response.Results)
+		}
+		if response.Code != "INVALID_EMAILS_IN_BATCH" || response.Status != http.StatusUnprocessableEntity {
+			t.Errorf("Expected the INVALID_EMAILS_IN_BATCH problem, but got %+v", response.Problem)
+		}
+		if created {
+			t.Error("Expected no student to be created in a rejected strict batch")
+		}
+	})
+
+	// Test case: Every student is registered in its own transaction by default
+	t.Run("RegisterStudents_TransactionPerStudent", func(t *testing.T) {
+		transactions := 0
+		unitOfWork.DoFn = func(_ context.Context, fn func(models.Repositories) error) error {
+			transactions++
+			return fn(unitOfWork.Repos)
+		}
+		defer func() { unitOfWork.DoFn = nil }()
+
+		// Prepare the request payload
+		payload := []byte(`{"teacher": "teacherken@gmail.com", "students": ["studentjon@gmail.com", "studenthon@gmail.com", "not-an-email"]}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/register", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.RegisterStudents)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+		if transactions != 2 {
+			t.Errorf("Expected 2 transactions, but got %d", transactions)
+		}
+	})
+
+	// Test case: Atomic mode registers all students in one transaction and rolls back on failure
+	t.Run("RegisterStudentsAtomic_RollsBackOnFailure", func(t *testing.T) {
+		transactions := 0
+		unitOfWork.DoFn = func(_ context.Context, fn func(models.Repositories) error) error {
+			transactions++
+			return fn(unitOfWork.Repos)
+		}
+		studentRepo.GetStudentByEmailFn = func(_ context.Context, email string) (*models.Student, error) {
+			if email == "brokenstudent@gmail.com" {
+				return nil, errors.New("connection reset")
+			}
+			return &models.Student{ID: 1, Email: email, Status: models.StatusActive}, nil
+		}
+		defer func() {
+			unitOfWork.DoFn = nil
+			studentRepo.GetStudentByEmailFn = nil
+		}()
+
+		// Prepare the request payload
+		payload := []byte(`{"teacher": "teacherken@gmail.com", "students": ["studentjon@gmail.com", "brokenstudent@gmail.com"], "atomic": true}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/register", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.RegisterStudents)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusInternalServerError {
+			t.Errorf("Expected status code %d, but got %d", http.StatusInternalServerError, rr.Code)
+		}
+		if transactions != 1 {
+			t.Errorf("Expected 1 transaction, but got %d", transactions)
+		}
+	})
+
+	// Test case: Profiles are applied to students created by the registration
+	t.Run("RegisterStudents_WithProfiles", func(t *testing.T) {
+		studentRepo.GetStudentByEmailFn = func(_ context.Context, email string) (*models.Student, error) {
+			return nil, nil
+		}
+		var created []models.Student
+		studentRepo.CreateStudentFn = func(_ context.Context, student *models.Student) (*models.Student, error) {
+			student.ID = uint(len(created) + 1)
+			created = append(created, *student)
+			return student, nil
+		}
+		teacherStudentRepo.CreateTeacherStudentFn = func(context.Context, *models.TeacherStudent) error {
+			return nil
+		}
+		defer func() {
+			studentRepo.GetStudentByEmailFn = nil
+			studentRepo.CreateStudentFn = nil
+			teacherStudentRepo.CreateTeacherStudentFn = nil
+		}()
+
+		payload := []byte(`{"teacher": "teacherken@gmail.com", "students": ["studentjon@gmail.com", "studenthon@gmail.com"],
+			"profiles": {"studentjon@gmail.com": {"name": "Jon Snow", "student_number": "S-1001", "grade_level": "Year 10", "metadata": {"house": "Stark"}}}}`)
+		req, err := http.NewRequest("POST", "/api/register", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler := asAdmin(teacherHandler.RegisterStudents)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+		if len(created) != 2 {
+			t.Fatalf("Expected 2 created students, but got %d", len(created))
+		}
+		jon, hon := created[0], created[1]
+		if jon.Name != "Jon Snow" || jon.StudentNumber == nil || *jon.StudentNumber != "S-1001" || jon.GradeLevel != "Year 10" || jon.Metadata["house"] != "Stark" {
+			t.Errorf("Expected the profile to be applied, but got %+v", jon)
+		}
+		if hon.Name != "" || hon.StudentNumber != nil || hon.Metadata != nil {
+			t.Errorf("Expected no profile for studenthon@gmail.com, but got %+v", hon)
+		}
 	})
 }
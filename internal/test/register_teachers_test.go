@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"bytes"
+	"class-management/internal/dto"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/teacher"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestRegisterTeachers(t *testing.T) {
+	// Create a new instance of the teacher service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	teacherStudentRepo := &mocks.MockTeacherStudentsRepo{}
+	classRepo := &mocks.MockClassRepo{}
+	notificationRepo := &mocks.MockNotificationRepo{}
+	teacherService := teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo, classRepo, mocks.NewMockUnitOfWork(teacherRepo, studentRepo, teacherStudentRepo, classRepo, notificationRepo), zap.NewNop())
+	teacherHandler := handler.NewTeacherHandler(teacherService)
+
+	// Test case: Every teacher is reported with its registration outcome
+	t.Run("RegisterTeachers_ReportsResultPerEmail", func(t *testing.T) {
+		teacherRepo.TeacherByEmailFn = func(_ context.Context, email string) (*models.Teacher, error) {
+			if email == "teacherken@gmail.com" {
+				return &models.Teacher{ID: 1, Email: email}, nil
+			}
+			return nil, nil
+		}
+
+		// Prepare the request payload
+		payload := []byte(`{"teachers": ["teacherken@gmail.com", "teacherjoe@gmail.com", "invalid_email"]}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/registerteachers", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.RegisterTeachers)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+
+		// Check the result of every email
+		var response dto.RegistrationResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		expected := []dto.RegistrationStatus{dto.RegistrationAlreadyRegistered, dto.RegistrationCreated, dto.RegistrationInvalid}
+		if len(response.Results) != len(expected) {
+			t.Fatalf("Expected %d results, but got %d", len(expected), len(response.Results))
+		}
+		for i, status := range expected {
+			if response.Results[i].Status != status {
+				t.Errorf("Expected %s to be %s, but got %s", response.Results[i].Email, status, response.Results[i].Status)
+			}
+		}
+	})
+
+	// Test case: Strict mode rejects the whole batch when an email is invalid
+	t.Run("RegisterTeachersStrict_RejectsInvalidBatch", func(t *testing.T) {
+		// Prepare the request payload
+		payload := []byte(`{"teachers": ["teacherjoe@gmail.com", "invalid_email"], "strict": true}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/registerteachers", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.RegisterTeachers)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+
+	// Test case: Empty teacher list
+	t.Run("RegisterNoTeachers_BadRequest", func(t *testing.T) {
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/registerteachers", bytes.NewBuffer([]byte(`{"teachers": []}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.RegisterTeachers)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+}
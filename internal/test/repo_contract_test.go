@@ -0,0 +1,611 @@
+package handler
+
+import (
+	"class-management/internal/memory"
+	"class-management/internal/models"
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+//Repositories under test, backed by the same store
+type contractRepos struct {
+	teachers        models.TeacherRepo
+	students        models.StudentRepo
+	teacherStudents models.TeacherStudentRepo
+	classes         models.ClassRepo
+
+	//Emails match regardless of case, as in MySQL. SQLite and Postgres compare them as written.
+	emailsIgnoreCase bool
+}
+
+func TestMemoryRepoContract(t *testing.T) {
+	runRepoContract(t, func(t *testing.T) contractRepos {
+		store := memory.NewStore()
+		return contractRepos{
+			teachers:         memory.NewTeacherRepo(store),
+			students:         memory.NewStudentRepo(store),
+			teacherStudents:  memory.NewTeacherStudentRepo(store),
+			classes:          memory.NewClassRepo(store),
+			emailsIgnoreCase: true,
+		}
+	})
+}
+
+func TestGormRepoContract(t *testing.T) {
+	runRepoContract(t, func(t *testing.T) contractRepos {
+		gormDB := newSQLiteDb(t)
+		return contractRepos{
+			teachers:        models.NewTeacherRepo(gormDB),
+			students:        models.NewStudentRepo(gormDB),
+			teacherStudents: models.NewTeacherStudentRepo(gormDB),
+			classes:         models.NewClassRepo(gormDB),
+		}
+	})
+}
+
+//Behaviour every TeacherRepo, StudentRepo, TeacherStudentRepo and ClassRepo implementation must share.
+//newRepos returns repositories over a fresh, empty store.
+func runRepoContract(t *testing.T, newRepos func(t *testing.T) contractRepos) {
+	ctx := context.Background()
+	createTeacher := func(t *testing.T, repos contractRepos, email string) *models.Teacher {
+		t.Helper()
+		teacher, err := repos.teachers.CreateTeacher(ctx, &models.Teacher{Email: email})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return teacher
+	}
+	createStudent := func(t *testing.T, repos contractRepos, student models.Student) *models.Student {
+		t.Helper()
+		if student.Status == "" {
+			student.Status = models.StatusActive
+		}
+		created, err := repos.students.CreateStudent(ctx, &student)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return created
+	}
+	register := func(t *testing.T, repos contractRepos, teacher *models.Teacher, students ...*models.Student) {
+		t.Helper()
+		for _, student := range students {
+			err := repos.teacherStudents.CreateTeacherStudent(ctx, &models.TeacherStudent{TeacherID: teacher.ID, StudentID: student.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	studentNumber := func(number string) *string {
+		return &number
+	}
+
+	// Test case: Teachers are found by email, unknown emails give no teacher and no error
+	t.Run("Teachers_CreateAndGet", func(t *testing.T) {
+		repos := newRepos(t)
+		created := createTeacher(t, repos, "teacherken@gmail.com")
+		if created.ID == 0 {
+			t.Error("Expected the created teacher to get an id")
+		}
+
+		teacher, err := repos.teachers.GetTeacherByEmail(ctx, "teacherken@gmail.com")
+		if err != nil || teacher == nil || teacher.ID != created.ID {
+			t.Errorf("Expected teacher %d, but got %+v, %v", created.ID, teacher, err)
+		}
+
+		teacher, err = repos.teachers.GetTeacherByEmail(ctx, "teacherjoe@gmail.com")
+		if err != nil || teacher != nil {
+			t.Errorf("Expected no teacher and no error, but got %+v, %v", teacher, err)
+		}
+	})
+
+	// Test case: Teacher emails are unique
+	t.Run("Teachers_DuplicateEmail", func(t *testing.T) {
+		repos := newRepos(t)
+		createTeacher(t, repos, "teacherken@gmail.com")
+		if _, err := repos.teachers.CreateTeacher(ctx, &models.Teacher{Email: "teacherken@gmail.com"}); err == nil {
+			t.Error("Expected an error for a duplicate teacher email")
+		}
+	})
+
+	// Test case: Teachers are listed by email a page at a time with the total
+	t.Run("Teachers_List", func(t *testing.T) {
+		repos := newRepos(t)
+		for _, email := range []string{"teacherzed@gmail.com", "teacherken@gmail.com", "teacherjoe@gmail.com"} {
+			createTeacher(t, repos, email)
+		}
+
+		teachers, total, err := repos.teachers.ListTeachers(ctx, models.Page{Limit: 2, Offset: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		emails := []string{}
+		for _, teacher := range teachers {
+			emails = append(emails, teacher.Email)
+		}
+		expected := []string{"teacherken@gmail.com", "teacherzed@gmail.com"}
+		if total != 3 || !reflect.DeepEqual(emails, expected) {
+			t.Errorf("Expected total 3 and %v, but got %d and %v", expected, total, emails)
+		}
+	})
+
+	// Test case: Teacher profiles are saved, metadata included
+	t.Run("Teachers_UpdateProfile", func(t *testing.T) {
+		repos := newRepos(t)
+		teacher := createTeacher(t, repos, "teacherken@gmail.com")
+		teacher.Name = "Ken"
+		teacher.Department = "Science"
+		teacher.Metadata = models.Metadata{"room": "B12", "floor": 2}
+		if err := repos.teachers.UpdateTeacherProfile(ctx, teacher); err != nil {
+			t.Fatal(err)
+		}
+
+		stored, err := repos.teachers.GetTeacherByEmail(ctx, "teacherken@gmail.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := models.Metadata{"room": "B12", "floor": float64(2)}
+		if stored.Name != "Ken" || stored.Department != "Science" || !reflect.DeepEqual(stored.Metadata, expected) {
+			t.Errorf("Expected the updated profile, but got %+v", stored)
+		}
+	})
+
+	// Test case: Students are found by email and by student number
+	t.Run("Students_CreateAndGet", func(t *testing.T) {
+		repos := newRepos(t)
+		created := createStudent(t, repos, models.Student{Email: "studentjon@gmail.com", StudentNumber: studentNumber("S-1")})
+
+		student, err := repos.students.GetStudentByEmail(ctx, "studentjon@gmail.com")
+		if err != nil || student == nil || student.ID != created.ID || student.Status != models.StatusActive {
+			t.Errorf("Expected active student %d, but got %+v, %v", created.ID, student, err)
+		}
+
+		student, err = repos.students.GetStudentByNumber(ctx, "S-1")
+		if err != nil || student == nil || student.ID != created.ID {
+			t.Errorf("Expected student %d, but got %+v, %v", created.ID, student, err)
+		}
+
+		student, err = repos.students.GetStudentByEmail(ctx, "studenthon@gmail.com")
+		if err != nil || student != nil {
+			t.Errorf("Expected no student and no error, but got %+v, %v", student, err)
+		}
+	})
+
+	// Test case: Student emails and student numbers are unique, students without a number don't collide
+	t.Run("Students_Unique", func(t *testing.T) {
+		repos := newRepos(t)
+		createStudent(t, repos, models.Student{Email: "studentjon@gmail.com", StudentNumber: studentNumber("S-1")})
+		createStudent(t, repos, models.Student{Email: "studenthon@gmail.com"})
+		mary := createStudent(t, repos, models.Student{Email: "studentmary@gmail.com"})
+
+		if _, err := repos.students.CreateStudent(ctx, &models.Student{Email: "studentjon@gmail.com", Status: models.StatusActive}); err == nil {
+			t.Error("Expected an error for a duplicate student email")
+		}
+		if _, err := repos.students.CreateStudent(ctx, &models.Student{Email: "studentbob@gmail.com", Status: models.StatusActive, StudentNumber: studentNumber("S-1")}); err == nil {
+			t.Error("Expected an error for a duplicate student number on create")
+		}
+
+		mary.StudentNumber = studentNumber("S-1")
+		if err := repos.students.UpdateStudentProfile(ctx, mary); err == nil {
+			t.Error("Expected an error for a duplicate student number on update")
+		}
+	})
+
+	// Test case: Students are listed by status a page at a time with the total
+	t.Run("Students_List", func(t *testing.T) {
+		repos := newRepos(t)
+		createStudent(t, repos, models.Student{Email: "studentjon@gmail.com"})
+		createStudent(t, repos, models.Student{Email: "studenthon@gmail.com", Status: models.StatusSuspended})
+		createStudent(t, repos, models.Student{Email: "studentmary@gmail.com"})
+		createStudent(t, repos, models.Student{Email: "studentagnes@gmail.com"})
+
+		students, total, err := repos.students.ListStudents(ctx, []models.StatusStudent{models.StatusActive}, models.Page{Limit: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		emails := []string{}
+		for _, student := range students {
+			emails = append(emails, student.Email)
+		}
+		expected := []string{"studentagnes@gmail.com", "studentjon@gmail.com"}
+		if total != 3 || !reflect.DeepEqual(emails, expected) {
+			t.Errorf("Expected total 3 and %v, but got %d and %v", expected, total, emails)
+		}
+
+		_, total, err = repos.students.ListStudents(ctx, nil, models.Page{Limit: 10})
+		if err != nil || total != 4 {
+			t.Errorf("Expected 4 students in total, but got %d, %v", total, err)
+		}
+	})
+
+	// Test case: A status change is saved and graduating archives the student's registrations
+	t.Run("Students_StatusChanges", func(t *testing.T) {
+		repos := newRepos(t)
+		teacher := createTeacher(t, repos, "teacherken@gmail.com")
+		student := createStudent(t, repos, models.Student{Email: "studentjon@gmail.com"})
+		register(t, repos, teacher, student)
+
+		student.Status = models.StatusSuspended
+		change := &models.StudentStatusChange{FromStatus: models.StatusActive, ToStatus: models.StatusSuspended}
+		if err := repos.students.UpdateStudentStatus(ctx, student, change); err != nil {
+			t.Fatal(err)
+		}
+		if change.StudentID != student.ID {
+			t.Errorf("Expected the change to be recorded for student %d, but got %d", student.ID, change.StudentID)
+		}
+		stored, _ := repos.students.GetStudentByEmail(ctx, "studentjon@gmail.com")
+		if stored.Status != models.StatusSuspended {
+			t.Errorf("Expected status %s, but got %s", models.StatusSuspended, stored.Status)
+		}
+
+		student.Status = models.StatusGraduated
+		change = &models.StudentStatusChange{FromStatus: models.StatusSuspended, ToStatus: models.StatusGraduated}
+		if err := repos.students.GraduateStudent(ctx, student, change); err != nil {
+			t.Fatal(err)
+		}
+		registration, err := repos.teacherStudents.IsStudentRegisteredForTeacher(ctx, teacher.ID, student.ID)
+		if err != nil || registration == nil || registration.ArchivedAt == nil {
+			t.Errorf("Expected an archived registration, but got %+v, %v", registration, err)
+		}
+
+		//the archived registration only counts when graduated students are included
+		if _, total, err := repos.students.ListStudentsByTeacher(ctx, teacher.ID, models.Page{Limit: 10}); err != nil || total != 0 {
+			t.Errorf("Expected no current students, but got %d, %v", total, err)
+		}
+		if teachers, err := repos.teachers.GetTeachersByStudent(ctx, student.ID); err != nil || len(teachers) != 0 {
+			t.Errorf("Expected no current teachers, but got %+v, %v", teachers, err)
+		}
+		if students, err := repos.teacherStudents.GetAllStudentsByTeacher(ctx, teacher.Email, false); err != nil || len(students) != 0 {
+			t.Errorf("Expected no students to notify, but got %+v, %v", students, err)
+		}
+		if students, err := repos.teacherStudents.GetAllStudentsByTeacher(ctx, teacher.Email, true); err != nil || len(students) != 1 {
+			t.Errorf("Expected the graduated student, but got %+v, %v", students, err)
+		}
+		filter := models.CommonStudentsFilter{Teachers: []string{teacher.Email}, MinTeachers: 1, Limit: 10}
+		if emails, _, err := repos.teacherStudents.GetCommonStudents(ctx, filter); err != nil || len(emails) != 0 {
+			t.Errorf("Expected no common students, but got %v, %v", emails, err)
+		}
+		filter.Statuses = []models.StatusStudent{models.StatusGraduated}
+		if emails, _, err := repos.teacherStudents.GetCommonStudents(ctx, filter); err != nil || len(emails) != 1 {
+			t.Errorf("Expected the graduated common student, but got %v, %v", emails, err)
+		}
+	})
+
+	// Test case: The students of a grade level are found, graduated ones only when asked for
+	t.Run("Students_ByGradeLevel", func(t *testing.T) {
+		repos := newRepos(t)
+		createStudent(t, repos, models.Student{Email: "studentjon@gmail.com", GradeLevel: "Year 12"})
+		createStudent(t, repos, models.Student{Email: "studentbob@gmail.com", GradeLevel: "Year 12", Status: models.StatusGraduated})
+		createStudent(t, repos, models.Student{Email: "studentagnes@gmail.com", GradeLevel: "Year 11"})
+
+		students, err := repos.students.GetStudentsByGradeLevel(ctx, "Year 12", false)
+		if err != nil || len(students) != 1 || students[0].Email != "studentjon@gmail.com" {
+			t.Errorf("Expected studentjon@gmail.com, but got %+v, %v", students, err)
+		}
+		students, err = repos.students.GetStudentsByGradeLevel(ctx, "Year 12", true)
+		if err != nil || len(students) != 2 || students[0].Email != "studentbob@gmail.com" {
+			t.Errorf("Expected 2 students ordered by email, but got %+v, %v", students, err)
+		}
+	})
+
+	// Test case: A status change made from a stale status is refused, and a change only writes the status
+	t.Run("Students_StaleStatusChange", func(t *testing.T) {
+		repos := newRepos(t)
+		student := createStudent(t, repos, models.Student{Email: "studentjon@gmail.com", Name: "Jon Snow"})
+
+		//two requests read the student while it was active
+		first, second := *student, *student
+		first.Status = models.StatusSuspended
+		if err := repos.students.UpdateStudentStatus(ctx, &first, &models.StudentStatusChange{FromStatus: models.StatusActive, ToStatus: models.StatusSuspended}); err != nil {
+			t.Fatal(err)
+		}
+		second.Status = models.StatusGraduated
+		second.Name = "Stale Name"
+		err := repos.students.GraduateStudent(ctx, &second, &models.StudentStatusChange{FromStatus: models.StatusActive, ToStatus: models.StatusGraduated})
+		if !errors.Is(err, models.ErrStatusChanged) {
+			t.Errorf("Expected %v, but got %v", models.ErrStatusChanged, err)
+		}
+
+		stored, _ := repos.students.GetStudentByEmail(ctx, "studentjon@gmail.com")
+		if stored.Status != models.StatusSuspended || stored.Name != "Jon Snow" {
+			t.Errorf("Expected the suspended student to be unchanged, but got %+v", stored)
+		}
+	})
+
+	// Test case: Deregistering hides the registration everywhere and the student can register again
+	t.Run("Registrations_Deregister", func(t *testing.T) {
+		repos := newRepos(t)
+		teacher := createTeacher(t, repos, "teacherken@gmail.com")
+		student := createStudent(t, repos, models.Student{Email: "studentjon@gmail.com"})
+		register(t, repos, teacher, student)
+
+		registration, err := repos.teacherStudents.IsStudentRegisteredForTeacher(ctx, teacher.ID, student.ID)
+		if err != nil || registration == nil {
+			t.Fatalf("Expected a registration, but got %+v, %v", registration, err)
+		}
+
+		if err := repos.teacherStudents.DeleteTeacherStudent(ctx, teacher.ID, student.ID); err != nil {
+			t.Fatal(err)
+		}
+		registration, err = repos.teacherStudents.IsStudentRegisteredForTeacher(ctx, teacher.ID, student.ID)
+		if err != nil || registration != nil {
+			t.Errorf("Expected no registration, but got %+v, %v", registration, err)
+		}
+		_, total, err := repos.students.ListStudentsByTeacher(ctx, teacher.ID, models.Page{Limit: 10})
+		if err != nil || total != 0 {
+			t.Errorf("Expected no students, but got %d, %v", total, err)
+		}
+		teachers, err := repos.teachers.GetTeachersByStudent(ctx, student.ID)
+		if err != nil || len(teachers) != 0 {
+			t.Errorf("Expected no teachers, but got %+v, %v", teachers, err)
+		}
+
+		register(t, repos, teacher, student)
+		registration, err = repos.teacherStudents.IsStudentRegisteredForTeacher(ctx, teacher.ID, student.ID)
+		if err != nil || registration == nil {
+			t.Errorf("Expected a registration after registering again, but got %+v, %v", registration, err)
+		}
+
+		//the deregistered registration is kept in the history
+		history, err := repos.teacherStudents.GetEnrolmentHistory(ctx, student.ID)
+		if err != nil || len(history) != 2 {
+			t.Fatalf("Expected 2 enrolments, but got %+v, %v", history, err)
+		}
+		if history[0].TeacherEmail != "teacherken@gmail.com" || history[0].DeregisteredAt == nil || history[1].DeregisteredAt != nil {
+			t.Errorf("Expected a deregistered and a current enrolment, but got %+v", history)
+		}
+	})
+
+	// Test case: Registrations are visible from both teachers and students
+	t.Run("Registrations_Lookups", func(t *testing.T) {
+		repos := newRepos(t)
+		ken := createTeacher(t, repos, "teacherken@gmail.com")
+		joe := createTeacher(t, repos, "teacherjoe@gmail.com")
+		jon := createStudent(t, repos, models.Student{Email: "studentjon@gmail.com"})
+		hon := createStudent(t, repos, models.Student{Email: "studenthon@gmail.com"})
+		register(t, repos, ken, jon, hon)
+		register(t, repos, joe, jon)
+
+		teachers, err := repos.teachers.GetTeachersByStudent(ctx, jon.ID)
+		if err != nil || len(teachers) != 2 || teachers[0].Email != "teacherjoe@gmail.com" || teachers[1].Email != "teacherken@gmail.com" {
+			t.Errorf("Expected teacherjoe and teacherken, but got %+v, %v", teachers, err)
+		}
+
+		students, total, err := repos.students.ListStudentsByTeacher(ctx, ken.ID, models.Page{Limit: 1})
+		if err != nil || total != 2 || len(students) != 1 || students[0].Email != "studenthon@gmail.com" {
+			t.Errorf("Expected total 2 and studenthon first, but got %d, %+v, %v", total, students, err)
+		}
+	})
+
+	// Test case: Notification recipients leave out suspended students, and graduated ones unless asked for
+	t.Run("Registrations_AllStudentsByTeacher", func(t *testing.T) {
+		repos := newRepos(t)
+		teacher := createTeacher(t, repos, "teacherken@gmail.com")
+		register(t, repos, teacher,
+			createStudent(t, repos, models.Student{Email: "studentjon@gmail.com"}),
+			createStudent(t, repos, models.Student{Email: "studenthon@gmail.com", Status: models.StatusSuspended}),
+			createStudent(t, repos, models.Student{Email: "studentmary@gmail.com", Status: models.StatusGraduated}),
+		)
+
+		emailsOf := func(students []models.Student) []string {
+			emails := []string{}
+			for _, student := range students {
+				emails = append(emails, student.Email)
+			}
+			sort.Strings(emails)
+			return emails
+		}
+
+		students, err := repos.teacherStudents.GetAllStudentsByTeacher(ctx, "teacherken@gmail.com", false)
+		if err != nil || !reflect.DeepEqual(emailsOf(students), []string{"studentjon@gmail.com"}) {
+			t.Errorf("Expected only studentjon, but got %v, %v", emailsOf(students), err)
+		}
+
+		students, err = repos.teacherStudents.GetAllStudentsByTeacher(ctx, "teacherken@gmail.com", true)
+		expected := []string{"studentjon@gmail.com", "studentmary@gmail.com"}
+		if err != nil || !reflect.DeepEqual(emailsOf(students), expected) {
+			t.Errorf("Expected %v, but got %v, %v", expected, emailsOf(students), err)
+		}
+	})
+
+	// Test case: Common students honour the teacher intersection, exclusions, statuses, sorting and pagination
+	t.Run("Registrations_CommonStudents", func(t *testing.T) {
+		repos := newRepos(t)
+		ken := createTeacher(t, repos, "teacherken@gmail.com")
+		joe := createTeacher(t, repos, "teacherjoe@gmail.com")
+		sam := createTeacher(t, repos, "teachersam@gmail.com")
+
+		base := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		jon := createStudent(t, repos, models.Student{Email: "studentjon@gmail.com", CreatedAt: base.Add(3 * time.Hour)})
+		hon := createStudent(t, repos, models.Student{Email: "studenthon@gmail.com", CreatedAt: base.Add(1 * time.Hour)})
+		mary := createStudent(t, repos, models.Student{Email: "studentmary@gmail.com", CreatedAt: base.Add(2 * time.Hour)})
+		agnes := createStudent(t, repos, models.Student{Email: "studentagnes@gmail.com", Status: models.StatusGraduated, CreatedAt: base})
+		bob := createStudent(t, repos, models.Student{Email: "studentbob@gmail.com", Status: models.StatusSuspended, CreatedAt: base.Add(4 * time.Hour)})
+		register(t, repos, ken, jon, hon, mary, agnes, bob)
+		register(t, repos, joe, jon, hon, agnes, bob)
+		register(t, repos, sam, hon)
+		//a registration that was stored twice still counts as one teacher
+		register(t, repos, ken, mary)
+
+		cases := []struct {
+			name          string
+			filter        models.CommonStudentsFilter
+			expected      []string
+			expectedTotal int64
+		}{
+			{
+				name:          "All",
+				filter:        models.CommonStudentsFilter{Teachers: []string{"teacherken@gmail.com", "teacherjoe@gmail.com"}, MinTeachers: 2, Limit: 10},
+				expected:      []string{"studentbob@gmail.com", "studenthon@gmail.com", "studentjon@gmail.com"},
+				expectedTotal: 3,
+			},
+			{
+				name:          "Any",
+				filter:        models.CommonStudentsFilter{Teachers: []string{"teacherjoe@gmail.com", "teachersam@gmail.com"}, MinTeachers: 1, Limit: 10},
+				expected:      []string{"studentbob@gmail.com", "studenthon@gmail.com", "studentjon@gmail.com"},
+				expectedTotal: 3,
+			},
+			{
+				name:          "Exclude",
+				filter:        models.CommonStudentsFilter{Teachers: []string{"teacherken@gmail.com", "teacherjoe@gmail.com"}, MinTeachers: 2, ExcludeTeachers: []string{"teachersam@gmail.com"}, Limit: 10},
+				expected:      []string{"studentbob@gmail.com", "studentjon@gmail.com"},
+				expectedTotal: 2,
+			},
+			{
+				name:          "IncludeGraduated",
+				filter:        models.CommonStudentsFilter{Teachers: []string{"teacherken@gmail.com", "teacherjoe@gmail.com"}, MinTeachers: 2, IncludeGraduated: true, Limit: 10},
+				expected:      []string{"studentagnes@gmail.com", "studentbob@gmail.com", "studenthon@gmail.com", "studentjon@gmail.com"},
+				expectedTotal: 4,
+			},
+			{
+				name:          "Statuses",
+				filter:        models.CommonStudentsFilter{Teachers: []string{"teacherken@gmail.com"}, MinTeachers: 1, Statuses: []models.StatusStudent{models.StatusActive}, Limit: 10},
+				expected:      []string{"studenthon@gmail.com", "studentjon@gmail.com", "studentmary@gmail.com"},
+				expectedTotal: 3,
+			},
+			{
+				name:          "SortCreatedAtDescending",
+				filter:        models.CommonStudentsFilter{Teachers: []string{"teacherken@gmail.com"}, MinTeachers: 1, Sort: models.CommonStudentsSortCreatedAt, Descending: true, Limit: 10},
+				expected:      []string{"studentbob@gmail.com", "studentjon@gmail.com", "studentmary@gmail.com", "studenthon@gmail.com"},
+				expectedTotal: 4,
+			},
+			{
+				name:          "Page",
+				filter:        models.CommonStudentsFilter{Teachers: []string{"teacherken@gmail.com"}, MinTeachers: 1, Limit: 2, Offset: 1},
+				expected:      []string{"studenthon@gmail.com", "studentjon@gmail.com"},
+				expectedTotal: 4,
+			},
+			{
+				name:          "DuplicateRegistration",
+				filter:        models.CommonStudentsFilter{Teachers: []string{"teacherken@gmail.com", "teachersam@gmail.com"}, MinTeachers: 2, Limit: 10},
+				expected:      []string{"studenthon@gmail.com"},
+				expectedTotal: 1,
+			},
+			{
+				name:          "NoLimit",
+				filter:        models.CommonStudentsFilter{Teachers: []string{"teacherken@gmail.com"}, MinTeachers: 1},
+				expected:      []string{"studentbob@gmail.com", "studenthon@gmail.com", "studentjon@gmail.com", "studentmary@gmail.com"},
+				expectedTotal: 4,
+			},
+		}
+
+		for _, c := range cases {
+			students, total, err := repos.teacherStudents.GetCommonStudents(ctx, c.filter)
+			if err != nil {
+				t.Fatalf("%s: %v", c.name, err)
+			}
+			if total != c.expectedTotal || !reflect.DeepEqual(students, c.expected) {
+				t.Errorf("%s: Expected total %d and %v, but got %d and %v", c.name, c.expectedTotal, c.expected, total, students)
+			}
+		}
+	})
+
+	// Test case: Common students can be limited to the students enrolled in a class
+	t.Run("Registrations_CommonStudentsByClass", func(t *testing.T) {
+		repos := newRepos(t)
+		ken := createTeacher(t, repos, "teacherken@gmail.com")
+		jon := createStudent(t, repos, models.Student{Email: "studentjon@gmail.com"})
+		hon := createStudent(t, repos, models.Student{Email: "studenthon@gmail.com"})
+		mary := createStudent(t, repos, models.Student{Email: "studentmary@gmail.com"})
+		register(t, repos, ken, jon, hon, mary)
+
+		class, err := repos.classes.CreateClass(ctx, &models.Class{Name: "Math 101"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		other, err := repos.classes.CreateClass(ctx, &models.Class{Name: "Art 101"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, enrolment := range []models.ClassStudent{{ClassID: class.ID, StudentID: jon.ID}, {ClassID: class.ID, StudentID: mary.ID}, {ClassID: other.ID, StudentID: hon.ID}} {
+			if err := repos.classes.EnrolStudent(ctx, &enrolment); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		filter := models.CommonStudentsFilter{Teachers: []string{"teacherken@gmail.com"}, MinTeachers: 1, ClassID: class.ID, Limit: 10}
+		students, total, err := repos.teacherStudents.GetCommonStudents(ctx, filter)
+		expected := []string{"studentjon@gmail.com", "studentmary@gmail.com"}
+		if err != nil || total != 2 || !reflect.DeepEqual(students, expected) {
+			t.Errorf("Expected %v, but got %d %v, %v", expected, total, students, err)
+		}
+
+		empty, err := repos.classes.CreateClass(ctx, &models.Class{Name: "Empty 101"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		filter.ClassID = empty.ID
+		if students, total, err := repos.teacherStudents.GetCommonStudents(ctx, filter); err != nil || total != 0 || len(students) != 0 {
+			t.Errorf("Expected no students in an empty class, but got %d %v, %v", total, students, err)
+		}
+	})
+
+	// Test case: Teacher emails match regardless of case only where the database does
+	t.Run("Teachers_EmailCase", func(t *testing.T) {
+		repos := newRepos(t)
+		ken := createTeacher(t, repos, "teacherken@gmail.com")
+		register(t, repos, ken, createStudent(t, repos, models.Student{Email: "studentjon@gmail.com"}))
+
+		teacher, err := repos.teachers.GetTeacherByEmail(ctx, "TeacherKen@gmail.com")
+		if err != nil || (teacher != nil) != repos.emailsIgnoreCase {
+			t.Errorf("Expected the teacher to be found: %v, but got %v, %v", repos.emailsIgnoreCase, teacher, err)
+		}
+
+		students, total, err := repos.teacherStudents.GetCommonStudents(ctx, models.CommonStudentsFilter{Teachers: []string{"TEACHERKEN@gmail.com"}, MinTeachers: 1, Limit: 10})
+		if err != nil || (total == 1) != repos.emailsIgnoreCase {
+			t.Errorf("Expected the student to be found: %v, but got %d %v, %v", repos.emailsIgnoreCase, total, students, err)
+		}
+	})
+
+	// Test case: Concurrent creates of the same email let exactly one through
+	t.Run("Students_ConcurrentCreate", func(t *testing.T) {
+		repos := newRepos(t)
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		created := 0
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repos.students.CreateStudent(ctx, &models.Student{Email: "studentjon@gmail.com", Status: models.StatusActive})
+				if err == nil {
+					mu.Lock()
+					created++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if created != 1 {
+			t.Errorf("Expected exactly 1 student to be created, but got %d", created)
+		}
+	})
+
+	// Test case: Calls made with a cancelled context fail without touching the data
+	t.Run("CancelledContext", func(t *testing.T) {
+		repos := newRepos(t)
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		if _, err := repos.teachers.CreateTeacher(cancelled, &models.Teacher{Email: "teacherken@gmail.com"}); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, but got %v", err)
+		}
+		if _, err := repos.students.GetStudentByEmail(cancelled, "studentjon@gmail.com"); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, but got %v", err)
+		}
+		if _, _, err := repos.teacherStudents.GetCommonStudents(cancelled, models.CommonStudentsFilter{Teachers: []string{"teacherken@gmail.com"}, MinTeachers: 1}); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, but got %v", err)
+		}
+
+		teacher, err := repos.teachers.GetTeacherByEmail(ctx, "teacherken@gmail.com")
+		if err != nil || teacher != nil {
+			t.Errorf("Expected no teacher to be created, but got %v %v", teacher, err)
+		}
+	})
+}
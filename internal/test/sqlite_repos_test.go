@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"class-management/db"
+	"class-management/internal/database"
+	"class-management/internal/migrate"
+	"class-management/internal/models"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+//Opens an in-memory SQLite database with the schema migrated
+func newSQLiteDb(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	gormDB, err := database.Open(database.Config{Driver: database.SQLite, Path: database.SQLiteMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrations, err := migrate.Load(db.Migrations, "migrations/sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrate.NewMigrator(sqlDB, migrations, migrate.Options{Driver: database.SQLite}).Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	//expected constraint violations would otherwise be logged as errors
+	return gormDB.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+}
+
+func TestSQLiteRepos(t *testing.T) {
+	ctx := context.Background()
+	gormDB := newSQLiteDb(t)
+	teacherRepo := models.NewTeacherRepo(gormDB)
+	studentRepo := models.NewStudentRepo(gormDB)
+	teacherStudentRepo := models.NewTeacherStudentRepo(gormDB)
+	notificationRepo := models.NewNotificationRepo(gormDB)
+	deliveryRepo := models.NewDeliveryRepo(gormDB)
+
+	teachers := make(map[string]*models.Teacher)
+	for _, email := range []string{"teacherken@gmail.com", "teacherjoe@gmail.com"} {
+		teacher, err := teacherRepo.CreateTeacher(ctx, &models.Teacher{Email: email})
+		if err != nil {
+			t.Fatal(err)
+		}
+		teachers[email] = teacher
+	}
+
+	registrations := map[string][]string{
+		"studentjon@gmail.com":  {"teacherken@gmail.com", "teacherjoe@gmail.com"},
+		"studenthon@gmail.com":  {"teacherken@gmail.com", "teacherjoe@gmail.com"},
+		"studentmary@gmail.com": {"teacherken@gmail.com"},
+	}
+	for _, email := range []string{"studentjon@gmail.com", "studenthon@gmail.com", "studentmary@gmail.com"} {
+		student, err := studentRepo.CreateStudent(ctx, &models.Student{Email: email, Status: models.StatusActive})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, teacher := range registrations[email] {
+			err := teacherStudentRepo.CreateTeacherStudent(ctx, &models.TeacherStudent{TeacherID: teachers[teacher].ID, StudentID: student.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Test case: Common students are counted, sorted and paginated
+	t.Run("GetCommonStudents_Success", func(t *testing.T) {
+		students, total, err := teacherStudentRepo.GetCommonStudents(ctx, models.CommonStudentsFilter{
+			Teachers:    []string{"teacherken@gmail.com", "teacherjoe@gmail.com"},
+			MinTeachers: 2,
+			Limit:       1,
+			Offset:      0,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 2 || !reflect.DeepEqual(students, []string{"studenthon@gmail.com"}) {
+			t.Errorf("Expected total 2 and [studenthon@gmail.com], but got %d and %v", total, students)
+		}
+	})
+
+	// Test case: Excluded teachers remove their students
+	t.Run("GetCommonStudents_Exclude", func(t *testing.T) {
+		students, total, err := teacherStudentRepo.GetCommonStudents(ctx, models.CommonStudentsFilter{
+			Teachers:        []string{"teacherken@gmail.com"},
+			ExcludeTeachers: []string{"teacherjoe@gmail.com"},
+			MinTeachers:     1,
+			Limit:           10,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 1 || !reflect.DeepEqual(students, []string{"studentmary@gmail.com"}) {
+			t.Errorf("Expected total 1 and [studentmary@gmail.com], but got %d and %v", total, students)
+		}
+	})
+
+	// Test case: Students are listed by status
+	t.Run("ListStudents_Success", func(t *testing.T) {
+		students, total, err := studentRepo.ListStudents(ctx, []models.StatusStudent{models.StatusActive}, models.Page{Limit: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 3 || len(students) != 2 {
+			t.Errorf("Expected total 3 and 2 students, but got %d and %d", total, len(students))
+		}
+	})
+
+	// Test case: Profile metadata round-trips through the JSON column
+	t.Run("UpdateTeacherProfile_Success", func(t *testing.T) {
+		teacher := teachers["teacherken@gmail.com"]
+		teacher.Department = "Science"
+		teacher.Metadata = models.Metadata{"room": "B12"}
+		if err := teacherRepo.UpdateTeacherProfile(ctx, teacher); err != nil {
+			t.Fatal(err)
+		}
+
+		stored, err := teacherRepo.GetTeacherByEmail(ctx, "teacherken@gmail.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.Department != "Science" || stored.Metadata["room"] != "B12" {
+			t.Errorf("Expected the updated profile, but got %+v", stored)
+		}
+	})
+
+	// Test case: Notifications are stored with recipients and their deliveries come due
+	t.Run("Notifications_Success", func(t *testing.T) {
+		notification := &models.Notification{
+			TeacherID: teachers["teacherken@gmail.com"].ID,
+			Text:      "Hello students!",
+			Recipients: []models.NotificationRecipient{
+				{Email: "studentjon@gmail.com", Registered: true},
+				{Email: "studentmary@gmail.com", Registered: true},
+			},
+		}
+		if err := notificationRepo.CreateNotification(ctx, notification); err != nil {
+			t.Fatal(err)
+		}
+
+		now := time.Now()
+		deliveries := make([]models.NotificationDelivery, 0, len(notification.Recipients))
+		for _, recipient := range notification.Recipients {
+			deliveries = append(deliveries, models.NotificationDelivery{
+				NotificationID: notification.ID,
+				RecipientID:    recipient.ID,
+				Email:          recipient.Email,
+				Status:         models.DeliveryPending,
+				NextAttemptAt:  now.Add(-time.Second),
+			})
+		}
+		if err := deliveryRepo.CreateDeliveries(ctx, deliveries); err != nil {
+			t.Fatal(err)
+		}
+
+		summaries, total, err := notificationRepo.GetNotificationsByTeacher(ctx, teachers["teacherken@gmail.com"].ID, models.Page{Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(summaries) != 1 || total != 1 || summaries[0].RecipientCount != 2 {
+			t.Errorf("Expected 1 notification with 2 recipients, but got %d %+v", total, summaries)
+		}
+		if summaries, total, err := notificationRepo.GetNotificationsByTeacher(ctx, teachers["teacherken@gmail.com"].ID, models.Page{Limit: 10, Offset: 1}); err != nil || len(summaries) != 0 || total != 1 {
+			t.Errorf("Expected an empty second page of 1 notification, but got %d %+v, %v", total, summaries, err)
+		}
+
+		due, err := deliveryRepo.ClaimDueDeliveries(ctx, now, 10, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(due) != 2 || due[0].Notification.Teacher.Email != "teacherken@gmail.com" || due[0].ClaimToken == "" {
+			t.Errorf("Expected 2 claimed deliveries from teacherken@gmail.com, but got %+v", due)
+		}
+
+		//claimed deliveries aren't claimed again until their lock has passed
+		if again, err := deliveryRepo.ClaimDueDeliveries(ctx, now, 10, time.Minute); err != nil || len(again) != 0 {
+			t.Errorf("Expected no deliveries to claim, but got %d, %v", len(again), err)
+		}
+
+		//an outcome saved under a claim that passed to another worker is dropped
+		stale := due[1]
+		stale.ClaimToken = "stale"
+		stale.Status = models.DeliverySent
+		if err := deliveryRepo.UpdateDelivery(ctx, &stale); err != nil {
+			t.Fatal(err)
+		}
+
+		due[0].Status = models.DeliverySent
+		due[0].Attempts = 1
+		if err := deliveryRepo.UpdateDelivery(ctx, &due[0]); err != nil {
+			t.Fatal(err)
+		}
+		reclaimed, err := deliveryRepo.ClaimDueDeliveries(ctx, now.Add(2*time.Minute), 10, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(reclaimed) != 1 || reclaimed[0].ID != due[1].ID || reclaimed[0].Status != models.DeliveryPending {
+			t.Errorf("Expected only the unsent delivery to be claimed again, but got %+v", reclaimed)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"class-management/internal/dto"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/directory"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestStudentEnrolments(t *testing.T) {
+	// Create a new instance of the directory service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	teacherStudentRepo := &mocks.MockTeacherStudentsRepo{}
+	directoryService := directory.NewDirectoryService(teacherRepo, studentRepo, teacherStudentRepo, zap.NewNop())
+	directoryHandler := handler.NewDirectoryHandler(directoryService)
+
+	// Test case: Retrieve current and deregistered registrations of a student
+	t.Run("StudentEnrolments_Success", func(t *testing.T) {
+		registeredAt := time.Date(2023, 7, 20, 10, 0, 0, 0, time.UTC)
+		deregisteredAt := registeredAt.Add(24 * time.Hour)
+		studentRepo.GetStudentByEmailFn = func(_ context.Context, email string) (*models.Student, error) {
+			return &models.Student{ID: 7, Email: email, Status: models.StatusActive}, nil
+		}
+		teacherStudentRepo.GetEnrolmentHistoryFn = func(_ context.Context, studentID uint) ([]models.Enrolment, error) {
+			if studentID != 7 {
+				t.Errorf("Expected the history of student 7, but got %d", studentID)
+			}
+			return []models.Enrolment{
+				{TeacherEmail: "teacherjoe@gmail.com", RegisteredAt: registeredAt, DeregisteredAt: &deregisteredAt},
+				{TeacherEmail: "teacherken@gmail.com", RegisteredAt: deregisteredAt},
+			}, nil
+		}
+
+		req, err := http.NewRequest("GET", "/api/students/studentmary@gmail.com/enrolments", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "studentmary@gmail.com"})
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(directoryHandler.StudentEnrolments)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+		var response dto.StudentEnrolmentsResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Student != "studentmary@gmail.com" || len(response.Enrolments) != 2 {
+			t.Fatalf("Unexpected response %+v", response)
+		}
+		if deregistered := response.Enrolments[0]; deregistered.Teacher != "teacherjoe@gmail.com" || deregistered.DeregisteredAt == nil || !deregistered.DeregisteredAt.Equal(deregisteredAt) {
+			t.Errorf("Expected a deregistered enrolment with teacherjoe@gmail.com, but got %+v", deregistered)
+		}
+		if current := response.Enrolments[1]; current.Teacher != "teacherken@gmail.com" || current.DeregisteredAt != nil {
+			t.Errorf("Expected a current enrolment with teacherken@gmail.com, but got %+v", current)
+		}
+	})
+
+	// Test case: Unknown student
+	t.Run("StudentEnrolments_NotExists", func(t *testing.T) {
+		studentRepo.GetStudentByEmailFn = func(_ context.Context, email string) (*models.Student, error) {
+			return nil, nil
+		}
+
+		req, err := http.NewRequest("GET", "/api/students/studentgone@gmail.com/enrolments", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "studentgone@gmail.com"})
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(directoryHandler.StudentEnrolments)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d, but got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+}
@@ -6,12 +6,15 @@ import (
 	"class-management/internal/mocks"
 	"class-management/internal/models"
 	"class-management/internal/service/teacher"
-	"errors"
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"go.uber.org/zap"
 )
 
 func TestSuspendStudents(t *testing.T) {
@@ -20,7 +23,9 @@ func TestSuspendStudents(t *testing.T) {
 	teacherRepo := &mocks.MockTeacherRepo{}
 	studentRepo := &mocks.MockStudentRepo{}
 	teacherStudentRepo := &mocks.MockTeacherStudentsRepo{}
-	teacherService := teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo)
+	classRepo := &mocks.MockClassRepo{}
+	notificationRepo := &mocks.MockNotificationRepo{}
+	teacherService := teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo, classRepo, mocks.NewMockUnitOfWork(teacherRepo, studentRepo, teacherStudentRepo, classRepo, notificationRepo), zap.NewNop())
 	teacherHandler := handler.NewTeacherHandler(teacherService)
 
 	// Test case 1: Suspend an existing student successfully
@@ -35,7 +40,7 @@ func TestSuspendStudents(t *testing.T) {
 		studentRepo.CreateStudentFn = nil
 
 		// Mock the CreateStudent and return the mockStudent
-		createdStudent, err := studentRepo.CreateStudent(mockStudent)
+		createdStudent, err := studentRepo.CreateStudent(context.Background(), mockStudent)
 		if err != nil {
 			log.Println("Failed to register student:", err)
 		} else {
@@ -55,7 +60,7 @@ func TestSuspendStudents(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.SuspendStudent)
+		handler := asAdmin(teacherHandler.SuspendStudent)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
@@ -80,7 +85,7 @@ func TestSuspendStudents(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.SuspendStudent)
+		handler := asAdmin(teacherHandler.SuspendStudent)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
@@ -93,8 +98,8 @@ func TestSuspendStudents(t *testing.T) {
 	t.Run("SuspendNonExistingStudent_NotFound", func(t *testing.T) {
 		studentEmail := "nonexistingstudent@gmail.com"
 
-		studentRepo.GetStudentByEmailFn = func(email string) (*models.Student, error) {
-			return nil, errors.New("student not found")
+		studentRepo.GetStudentByEmailFn = func(_ context.Context, email string) (*models.Student, error) {
+			return nil, nil
 		}
 
 		// Prepare the request payload
@@ -110,12 +115,12 @@ func TestSuspendStudents(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.SuspendStudent)
+		handler := asAdmin(teacherHandler.SuspendStudent)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
-		if rr.Code != http.StatusUnprocessableEntity {
-			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d, but got %d", http.StatusNotFound, rr.Code)
 		}
 	})
 
@@ -136,7 +141,7 @@ func TestSuspendStudents(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.SuspendStudent)
+		handler := asAdmin(teacherHandler.SuspendStudent)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
@@ -162,7 +167,7 @@ func TestSuspendStudents(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.SuspendStudent)
+		handler := asAdmin(teacherHandler.SuspendStudent)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
@@ -185,7 +190,7 @@ func TestSuspendStudents(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Handle the request
-		handler := http.HandlerFunc(teacherHandler.SuspendStudent)
+		handler := asAdmin(teacherHandler.SuspendStudent)
 		handler.ServeHTTP(rr, req)
 
 		// Check the response status code
@@ -193,4 +198,27 @@ func TestSuspendStudents(t *testing.T) {
 			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
 		}
 	})
+
+	// Test case 6: Suspend a student with a reason longer than the status history keeps
+	t.Run("SuspendWithLongReason_InvalidRequest", func(t *testing.T) {
+		payload := []byte(fmt.Sprintf(`{"student": "studentjon@gmail.com", "reason": "%s"}`, strings.Repeat("x", 1025)))
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/suspend", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.SuspendStudent)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code and error code
+		if rr.Code != http.StatusUnprocessableEntity || !strings.Contains(rr.Body.String(), "STATUS_REASON_TOO_LONG") {
+			t.Errorf("Expected a STATUS_REASON_TOO_LONG error, but got %d %s", rr.Code, rr.Body.String())
+		}
+	})
 }
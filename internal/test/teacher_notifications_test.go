@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"class-management/internal/auth"
+	"class-management/internal/dto"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/notification"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func TestTeacherNotifications(t *testing.T) {
+	// Create a new instance of the notification service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	notificationRepo := &mocks.MockNotificationRepo{}
+	notificationService := notification.NewNotificationService(teacherRepo, notificationRepo)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+
+	// Test case: List the notifications sent by a teacher
+	t.Run("TeacherNotifications_Success", func(t *testing.T) {
+		teacherRepo.TeacherByEmailFn = func(_ context.Context, email string) (*models.Teacher, error) {
+			return &models.Teacher{ID: 1, Email: email}, nil
+		}
+		var received models.Page
+		notificationRepo.GetNotificationsByTeacherFn = func(_ context.Context, teacherID uint, page models.Page) ([]models.NotificationSummary, int64, error) {
+			received = page
+			return []models.NotificationSummary{
+				{ID: 2, Text: "Hey everybody", RecipientCount: 3, CreatedAt: time.Now()},
+				{ID: 1, Text: "Hello @studentagnes@gmail.com", RecipientCount: 1, CreatedAt: time.Now()},
+			}, 12, nil
+		}
+
+		req, err := http.NewRequest("GET", "/api/teachers/teacherken@gmail.com/notifications?limit=2&offset=4", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "teacherken@gmail.com"})
+
+		rr := httptest.NewRecorder()
+		handler := asIdentity(notificationHandler.TeacherNotifications, auth.Identity{Email: "teacherken@gmail.com", Role: auth.RoleTeacher})
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+
+		var response dto.TeacherNotificationsResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Notifications) != 2 || response.Notifications[0].RecipientCount != 3 || response.Total != 12 || response.Limit != 2 || response.Offset != 4 {
+			t.Errorf("Expected a page of 2 of 12 notifications, but got %+v", response)
+		}
+		if received.Limit != 2 || received.Offset != 4 {
+			t.Errorf("Unexpected page %+v", received)
+		}
+	})
+
+	// Test case: A page of the default size is returned without pagination params
+	t.Run("TeacherNotifications_DefaultPage", func(t *testing.T) {
+		var received models.Page
+		notificationRepo.GetNotificationsByTeacherFn = func(_ context.Context, teacherID uint, page models.Page) ([]models.NotificationSummary, int64, error) {
+			received = page
+			return []models.NotificationSummary{}, 0, nil
+		}
+
+		req, err := http.NewRequest("GET", "/api/teachers/teacherken@gmail.com/notifications", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "teacherken@gmail.com"})
+
+		rr := httptest.NewRecorder()
+		handler := asAdmin(notificationHandler.TeacherNotifications)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+		if received.Limit != 100 || received.Offset != 0 {
+			t.Errorf("Unexpected page %+v", received)
+		}
+	})
+
+	// Test case: Invalid pagination
+	t.Run("TeacherNotifications_InvalidPage", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/api/teachers/teacherken@gmail.com/notifications?limit=0", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "teacherken@gmail.com"})
+
+		rr := httptest.NewRecorder()
+		handler := asAdmin(notificationHandler.TeacherNotifications)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+
+	// Test case: Teacher doesn't exist
+	t.Run("TeacherNotifications_TeacherNotExists", func(t *testing.T) {
+		teacherRepo.TeacherByEmailFn = func(_ context.Context, email string) (*models.Teacher, error) {
+			return nil, nil
+		}
+
+		req, err := http.NewRequest("GET", "/api/teachers/teacherken@gmail.com/notifications", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "teacherken@gmail.com"})
+
+		rr := httptest.NewRecorder()
+		handler := asAdmin(notificationHandler.TeacherNotifications)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d, but got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+
+	// Test case: Another teacher's notifications are forbidden
+	t.Run("TeacherNotifications_Forbidden", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/api/teachers/teacherjoe@gmail.com/notifications", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "teacherjoe@gmail.com"})
+
+		rr := httptest.NewRecorder()
+		handler := asIdentity(notificationHandler.TeacherNotifications, auth.Identity{Email: "teacherken@gmail.com", Role: auth.RoleTeacher})
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d, but got %d", http.StatusForbidden, rr.Code)
+		}
+	})
+}
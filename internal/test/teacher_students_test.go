@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"class-management/internal/dto"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/directory"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestTeacherStudents(t *testing.T) {
+	// Create a new instance of the directory service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	directoryService := directory.NewDirectoryService(teacherRepo, studentRepo, &mocks.MockTeacherStudentsRepo{}, zap.NewNop())
+	directoryHandler := handler.NewDirectoryHandler(directoryService)
+
+	// Test case: List the students of a teacher
+	t.Run("TeacherStudents_Success", func(t *testing.T) {
+		studentRepo.ListStudentsByTeacherFn = func(_ context.Context, teacherID uint, page models.Page) ([]models.Student, int64, error) {
+			if page.Limit != 10 || page.Offset != 0 {
+				t.Errorf("Expected page limit 10 offset 0, but got %+v", page)
+			}
+			return []models.Student{{ID: 1, Email: "studentjon@gmail.com", Status: models.StatusActive}}, 1, nil
+		}
+
+		req, err := http.NewRequest("GET", "/api/teachers/teacherken@gmail.com/students?limit=10", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "teacherken@gmail.com"})
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(directoryHandler.TeacherStudents)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+		var response dto.TeacherStudentsResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Teacher != "teacherken@gmail.com" || len(response.Students) != 1 || response.Total != 1 {
+			t.Errorf("Unexpected response %+v", response)
+		}
+	})
+
+	// Test case: Unknown teacher
+	t.Run("TeacherStudents_TeacherNotExists", func(t *testing.T) {
+		teacherRepo.TeacherByEmailFn = func(_ context.Context, email string) (*models.Teacher, error) {
+			return nil, nil
+		}
+
+		req, err := http.NewRequest("GET", "/api/teachers/teachergone@gmail.com/students", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "teachergone@gmail.com"})
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(directoryHandler.TeacherStudents)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d, but got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+}
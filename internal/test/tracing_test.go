@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"bytes"
+	"class-management/internal/dto"
+	"class-management/internal/logging"
+	"class-management/internal/models"
+	"class-management/internal/service/teacher"
+	"class-management/internal/tracing"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+//Provider recording every span in memory
+func newRecordingProvider() (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), recorder
+}
+
+//The ended span called name
+func endedSpan(t *testing.T, recorder *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	var names []string
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			return span
+		}
+		names = append(names, span.Name())
+	}
+	t.Fatalf("Expected a span named %q, but got %v", name, names)
+	return nil
+}
+
+//Value of the attribute key of span
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracing(t *testing.T) {
+	// Test case: The middleware starts a server span named by route, continuing the caller's trace
+	t.Run("Middleware_Span", func(t *testing.T) {
+		provider, recorder := newRecordingProvider()
+		core, logs := observer.New(zapcore.DebugLevel)
+
+		router := mux.NewRouter()
+		router.Use(tracing.Middleware(provider))
+		router.HandleFunc("/api/students/{email}", func(writer http.ResponseWriter, request *http.Request) {
+			logging.FromContext(request.Context()).Info("handled")
+			writer.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		request := httptest.NewRequest(http.MethodGet, "/api/students/studentbob@gmail.com", nil)
+		request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		logging.RequestID(zap.New(core))(router).ServeHTTP(httptest.NewRecorder(), request)
+
+		span := endedSpan(t, recorder, "GET /api/students/{email}")
+		if span.SpanKind() != trace.SpanKindServer {
+			t.Errorf("Expected a server span, but got %v", span.SpanKind())
+		}
+		if span.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || span.Parent().SpanID().String() != "00f067aa0ba902b7" {
+			t.Errorf("Expected the span to continue the caller's trace, but got %v with parent %v", span.SpanContext().TraceID(), span.Parent().SpanID())
+		}
+		if span.Status().Code != codes.Error || spanAttribute(span, "http.status_code").AsInt64() != 503 {
+			t.Errorf("Expected a failed span with status 503, but got %+v %v", span.Status(), span.Attributes())
+		}
+
+		entries := logs.FilterMessage("handled").AllUntimed()
+		if len(entries) != 1 || entries[0].ContextMap()["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("Expected the handler log line to carry the trace ID, but got %+v", logs.AllUntimed())
+		}
+	})
+
+	// Test case: Queries get a client span under the span of their context, lookups finding nothing don't fail
+	t.Run("GormPlugin_Span", func(t *testing.T) {
+		provider, recorder := newRecordingProvider()
+		db := newSQLiteDb(t)
+		if err := db.Use(tracing.GormPlugin(provider)); err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+		found, err := models.NewTeacherRepo(db).GetTeacherByEmail(ctx, "teacherken@gmail.com")
+		if err != nil || found != nil {
+			t.Fatalf("Expected no teacher, but got %v %v", found, err)
+		}
+		if err := db.WithContext(ctx).Exec("SELECT * FROM missing_table").Error; err == nil {
+			t.Fatal("Expected an error for a missing table")
+		}
+		parent.End()
+
+		query := endedSpan(t, recorder, "query teachers")
+		if query.Parent().SpanID() != parent.SpanContext().SpanID() || query.SpanKind() != trace.SpanKindClient {
+			t.Errorf("Expected a client span under the parent, but got parent %v and kind %v", query.Parent().SpanID(), query.SpanKind())
+		}
+		if query.Status().Code != codes.Unset || spanAttribute(query, "db.system").AsString() != "sqlite" {
+			t.Errorf("Expected an unfailed sqlite span, but got %+v %v", query.Status(), query.Attributes())
+		}
+		if statement := spanAttribute(query, "db.statement").AsString(); !strings.Contains(statement, "teachers") || strings.Contains(statement, "teacherken") {
+			t.Errorf("Expected the statement without its values, but got %q", statement)
+		}
+
+		if raw := endedSpan(t, recorder, "raw"); raw.Status().Code != codes.Error {
+			t.Errorf("Expected the failed query to fail its span, but got %+v", raw.Status())
+		}
+	})
+
+	// Test case: A notification call is traced from the service down to every query, with the mention parsing on its own
+	t.Run("TeacherService_Spans", func(t *testing.T) {
+		provider, recorder := newRecordingProvider()
+		db := newSQLiteDb(t)
+		teacherRepo := models.NewTeacherRepo(db)
+		if _, err := teacherRepo.CreateTeacher(context.Background(), &models.Teacher{Email: "teacherken@gmail.com"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Use(tracing.GormPlugin(provider)); err != nil {
+			t.Fatal(err)
+		}
+
+		service := tracing.NewTeacherService(teacher.NewTeacherService(teacherRepo, models.NewStudentRepo(db), models.NewTeacherStudentRepo(db),
+			models.NewClassRepo(db), models.NewUnitOfWork(db), zap.NewNop()), provider)
+		_, err := service.FetchStudentsForNotification(context.Background(), dto.FetchStudentsForNotificationRequest{
+			Teacher:      "teacherken@gmail.com",
+			Notification: "Hello students! @studentagnes@gmail.com @studentmiche@gmail.com",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		root := endedSpan(t, recorder, "TeacherService.FetchStudentsForNotification")
+		parse := endedSpan(t, recorder, "parse mentions")
+		if spanAttribute(parse, "mentions").AsInt64() != 2 {
+			t.Errorf("Expected 2 mentions on the parse span, but got %v", parse.Attributes())
+		}
+		endedSpan(t, recorder, "query teachers")
+		endedSpan(t, recorder, "create notifications")
+		for _, span := range recorder.Ended() {
+			if span.Name() != root.Name() && span.Parent().SpanID() != root.SpanContext().SpanID() {
+				t.Errorf("Expected %q under the service span, but got parent %v", span.Name(), span.Parent().SpanID())
+			}
+		}
+	})
+
+	// Test case: The stdout exporter writes the spans when the provider shuts down
+	t.Run("New_Stdout", func(t *testing.T) {
+		var buffer bytes.Buffer
+		provider, shutdown, err := tracing.New(context.Background(), tracing.Config{Exporter: "stdout", ServiceName: "class-api", SampleRatio: 1}, &buffer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, span := provider.Tracer("test").Start(context.Background(), "exported")
+		span.End()
+		if err := shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(buffer.String(), `"Name":"exported"`) || !strings.Contains(buffer.String(), "class-api") {
+			t.Errorf("Expected the span with its service name, but got %s", buffer.String())
+		}
+	})
+
+	// Test case: No exporter records nothing, an unknown one is rejected
+	t.Run("New_NoneAndUnknown", func(t *testing.T) {
+		provider, _, err := tracing.New(context.Background(), tracing.Config{Exporter: "none"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, span := provider.Tracer("test").Start(context.Background(), "dropped"); span.IsRecording() {
+			t.Error("Expected spans not to be recorded")
+		}
+
+		if _, _, err := tracing.New(context.Background(), tracing.Config{Exporter: "jaeger"}, nil); err == nil {
+			t.Error("Expected an error for an unknown exporter")
+		}
+	})
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"bytes"
+	"class-management/internal/auth"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/teacher"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestUnsuspendStudent(t *testing.T) {
+
+	// Create a new instance of the teacher service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	teacherStudentRepo := &mocks.MockTeacherStudentsRepo{}
+	classRepo := &mocks.MockClassRepo{}
+	notificationRepo := &mocks.MockNotificationRepo{}
+	teacherService := teacher.NewTeacherService(teacherRepo, studentRepo, teacherStudentRepo, classRepo, mocks.NewMockUnitOfWork(teacherRepo, studentRepo, teacherStudentRepo, classRepo, notificationRepo), zap.NewNop())
+	teacherHandler := handler.NewTeacherHandler(teacherService)
+
+	// Test case: Reinstate a suspended student and record the change with the caller, not a name from the body
+	t.Run("UnsuspendSuspendedStudent_Success", func(t *testing.T) {
+		studentEmail := "student1@example.com"
+		studentRepo.GetStudentByEmailFn = func(_ context.Context, email string) (*models.Student, error) {
+			return &models.Student{ID: 1, Email: email, Status: models.StatusSuspended}, nil
+		}
+
+		var recorded *models.StudentStatusChange
+		studentRepo.UpdateStudentStatusFn = func(_ context.Context, student *models.Student, change *models.StudentStatusChange) error {
+			recorded = change
+			return nil
+		}
+
+		// Prepare the request payload
+		payload := []byte(fmt.Sprintf(`{"student": "%s", "changed_by": "principal@gmail.com", "reason": "appeal accepted"}`, studentEmail))
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/unsuspend", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asIdentity(teacherHandler.UnsuspendStudent, auth.Identity{Email: "teacherken@gmail.com", Role: auth.RoleTeacher})
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusNoContent {
+			t.Errorf("Expected status code %d, but got %d", http.StatusNoContent, rr.Code)
+		}
+
+		// Check the recorded status change
+		if recorded == nil {
+			t.Fatal("Expected the status change to be recorded")
+		}
+		if recorded.FromStatus != models.StatusSuspended || recorded.ToStatus != models.StatusActive {
+			t.Errorf("Expected change from %s to %s, but got %s to %s", models.StatusSuspended, models.StatusActive, recorded.FromStatus, recorded.ToStatus)
+		}
+		if recorded.ChangedBy != "teacherken@gmail.com" || recorded.Reason != "appeal accepted" {
+			t.Errorf("Expected the caller and reason to be recorded, but got %q and %q", recorded.ChangedBy, recorded.Reason)
+		}
+	})
+
+	// Test case: Reinstating an active student is not allowed
+	t.Run("UnsuspendActiveStudent_IllegalTransition", func(t *testing.T) {
+		studentRepo.GetStudentByEmailFn = func(_ context.Context, email string) (*models.Student, error) {
+			return &models.Student{ID: 1, Email: email, Status: models.StatusActive}, nil
+		}
+
+		// Prepare the request payload
+		payload := []byte(`{"student": "student1@example.com"}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/unsuspend", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.UnsuspendStudent)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusConflict {
+			t.Errorf("Expected status code %d, but got %d", http.StatusConflict, rr.Code)
+		}
+	})
+
+	// Test case: Unsuspend a student with an invalid email
+	t.Run("UnsuspendInvalidStudent_InvalidRequest", func(t *testing.T) {
+		// Prepare the request payload
+		payload := []byte(`{"student": "invalid_email"}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/unsuspend", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.UnsuspendStudent)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+
+	// Test case: Unsuspend a student with a reason longer than the status history keeps
+	t.Run("UnsuspendWithLongReason_InvalidRequest", func(t *testing.T) {
+		// Prepare the request payload
+		payload := []byte(`{"student": "studentjon@gmail.com", "reason": "` + strings.Repeat("x", 1025) + `"}`)
+
+		// Create a new HTTP request
+		req, err := http.NewRequest("POST", "/api/unsuspend", bytes.NewBuffer(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a new HTTP test recorder
+		rr := httptest.NewRecorder()
+
+		// Handle the request
+		handler := asAdmin(teacherHandler.UnsuspendStudent)
+		handler.ServeHTTP(rr, req)
+
+		// Check the response status code
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"bytes"
+	"class-management/internal/auth"
+	"class-management/internal/dto"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/directory"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestUpdateStudent(t *testing.T) {
+	// Create a new instance of the directory service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	teacherStudentRepo := &mocks.MockTeacherStudentsRepo{}
+	directoryService := directory.NewDirectoryService(teacherRepo, studentRepo, teacherStudentRepo, zap.NewNop())
+	directoryHandler := handler.NewDirectoryHandler(directoryService)
+
+	student := &models.Student{ID: 1, Email: "studentjon@gmail.com", Status: models.StatusActive}
+	studentRepo.GetStudentByEmailFn = func(_ context.Context, email string) (*models.Student, error) {
+		return student, nil
+	}
+
+	// Test case: Change the profile fields of a student
+	t.Run("UpdateStudent_Success", func(t *testing.T) {
+		reqBody := []byte(`{"name": "Jon Snow", "student_number": "S-1001", "grade_level": "Year 10"}`)
+		req, err := http.NewRequest("PATCH", "/api/students/studentjon@gmail.com", bytes.NewBuffer(reqBody))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "studentjon@gmail.com"})
+		rr := httptest.NewRecorder()
+		handler := asAdmin(directoryHandler.UpdateStudent)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+
+		var response dto.StudentResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Name != "Jon Snow" || response.StudentNumber == nil || *response.StudentNumber != "S-1001" || response.GradeLevel != "Year 10" || response.Status != "ACTIVE" {
+			t.Errorf("Unexpected response %+v", response)
+		}
+	})
+
+	// Test case: Student number of another student
+	t.Run("UpdateStudent_StudentNumberTaken", func(t *testing.T) {
+		studentRepo.GetStudentByNumberFn = func(_ context.Context, studentNumber string) (*models.Student, error) {
+			return &models.Student{ID: 2, Email: "studentmary@gmail.com"}, nil
+		}
+		defer func() { studentRepo.GetStudentByNumberFn = nil }()
+		studentRepo.UpdateStudentProfileFn = func(_ context.Context, student *models.Student) error {
+			t.Error("Expected the profile not to be saved")
+			return nil
+		}
+		defer func() { studentRepo.UpdateStudentProfileFn = nil }()
+
+		req, err := http.NewRequest("PATCH", "/api/students/studentjon@gmail.com", bytes.NewBuffer([]byte(`{"student_number": "S-1002"}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "studentjon@gmail.com"})
+		rr := httptest.NewRecorder()
+		handler := asAdmin(directoryHandler.UpdateStudent)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusConflict {
+			t.Errorf("Expected status code %d, but got %d", http.StatusConflict, rr.Code)
+		}
+	})
+
+	// Test case: A teacher can change the profile of their own student
+	t.Run("UpdateStudent_StudentsTeacher", func(t *testing.T) {
+		req, err := http.NewRequest("PATCH", "/api/students/studentjon@gmail.com", bytes.NewBuffer([]byte(`{"preferred_name": "Jon"}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "studentjon@gmail.com"})
+		rr := httptest.NewRecorder()
+		handler := asIdentity(directoryHandler.UpdateStudent, auth.Identity{Email: "teacherken@gmail.com", Role: auth.RoleTeacher})
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+	})
+
+	// Test case: A teacher the student isn't registered with is forbidden
+	t.Run("UpdateStudent_Forbidden", func(t *testing.T) {
+		teacherStudentRepo.IsStudentRegisteredForTeacherFn = func(_ context.Context, teacherID uint, studentID uint) (*models.TeacherStudent, error) {
+			return nil, nil
+		}
+		defer func() { teacherStudentRepo.IsStudentRegisteredForTeacherFn = nil }()
+		studentRepo.UpdateStudentProfileFn = func(_ context.Context, student *models.Student) error {
+			t.Error("Expected the profile not to be saved")
+			return nil
+		}
+		defer func() { studentRepo.UpdateStudentProfileFn = nil }()
+
+		req, err := http.NewRequest("PATCH", "/api/students/studentjon@gmail.com", bytes.NewBuffer([]byte(`{"grade_level": "Year 12"}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "studentjon@gmail.com"})
+		rr := httptest.NewRecorder()
+		handler := asIdentity(directoryHandler.UpdateStudent, auth.Identity{Email: "teacherjoe@gmail.com", Role: auth.RoleTeacher})
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d, but got %d", http.StatusForbidden, rr.Code)
+		}
+	})
+
+	// Test case: Invalid body
+	t.Run("UpdateStudent_InvalidBody", func(t *testing.T) {
+		req, err := http.NewRequest("PATCH", "/api/students/studentjon@gmail.com", bytes.NewBuffer([]byte(`{"grade_level": 10}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "studentjon@gmail.com"})
+		rr := httptest.NewRecorder()
+		handler := asAdmin(directoryHandler.UpdateStudent)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d, but got %d", http.StatusBadRequest, rr.Code)
+		}
+	})
+}
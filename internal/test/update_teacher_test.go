@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"bytes"
+	"class-management/internal/auth"
+	"class-management/internal/dto"
+	"class-management/internal/handler"
+	"class-management/internal/mocks"
+	"class-management/internal/models"
+	"class-management/internal/service/directory"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestUpdateTeacher(t *testing.T) {
+	// Create a new instance of the directory service and mock the dependencies
+	teacherRepo := &mocks.MockTeacherRepo{}
+	studentRepo := &mocks.MockStudentRepo{}
+	directoryService := directory.NewDirectoryService(teacherRepo, studentRepo, &mocks.MockTeacherStudentsRepo{}, zap.NewNop())
+	directoryHandler := handler.NewDirectoryHandler(directoryService)
+	ken := auth.Identity{Email: "teacherken@gmail.com", Role: auth.RoleTeacher}
+
+	// Test case: A teacher changes some profile fields
+	t.Run("UpdateTeacher_Success", func(t *testing.T) {
+		teacherRepo.TeacherByEmailFn = func(_ context.Context, email string) (*models.Teacher, error) {
+			return &models.Teacher{ID: 1, Email: email, Name: "Ken Adams", Department: "Science"}, nil
+		}
+		defer func() { teacherRepo.TeacherByEmailFn = nil }()
+		var saved *models.Teacher
+		teacherRepo.UpdateTeacherProfileFn = func(_ context.Context, teacher *models.Teacher) error {
+			saved = teacher
+			return nil
+		}
+
+		reqBody := []byte(`{"preferred_name": "Ken", "metadata": {"room": "B12"}}`)
+		req, err := http.NewRequest("PATCH", "/api/teachers/teacherken@gmail.com", bytes.NewBuffer(reqBody))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "teacherken@gmail.com"})
+		rr := httptest.NewRecorder()
+		handler := asIdentity(directoryHandler.UpdateTeacher, ken)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, but got %d", http.StatusOK, rr.Code)
+		}
+		if saved == nil || saved.Name != "Ken Adams" || saved.PreferredName != "Ken" || saved.Department != "Science" || saved.Metadata["room"] != "B12" {
+			t.Errorf("Expected only preferred_name and metadata to change, but got %+v", saved)
+		}
+
+		var response dto.TeacherResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if response.PreferredName != "Ken" || response.Department != "Science" {
+			t.Errorf("Unexpected response %+v", response)
+		}
+	})
+
+	// Test case: Another teacher's profile is forbidden
+	t.Run("UpdateTeacher_Forbidden", func(t *testing.T) {
+		req, err := http.NewRequest("PATCH", "/api/teachers/teacherjoe@gmail.com", bytes.NewBuffer([]byte(`{"name": "Joe"}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "teacherjoe@gmail.com"})
+		rr := httptest.NewRecorder()
+		handler := asIdentity(directoryHandler.UpdateTeacher, ken)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d, but got %d", http.StatusForbidden, rr.Code)
+		}
+	})
+
+	// Test case: Too long profile field
+	t.Run("UpdateTeacher_InvalidProfile", func(t *testing.T) {
+		reqBody, _ := json.Marshal(map[string]string{"department": strings.Repeat("x", 256)})
+		req, err := http.NewRequest("PATCH", "/api/teachers/teacherken@gmail.com", bytes.NewBuffer(reqBody))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"email": "teacherken@gmail.com"})
+		rr := httptest.NewRecorder()
+		handler := asIdentity(directoryHandler.UpdateTeacher, ken)
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, but got %d", http.StatusUnprocessableEntity, rr.Code)
+		}
+	})
+}
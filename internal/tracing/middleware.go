@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"class-management/internal/logging"
+	"class-management/internal/utils"
+	"net/http"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+//Middleware starts a server span for every routed request, named by method and route template, continuing the
+//trace of the caller when it sends one. The request context carries the span down to the service and the
+//repositories, and its logger adds the trace ID. It must run inside logging.RequestID to keep the request ID.
+func Middleware(provider trace.TracerProvider) func(http.Handler) http.Handler {
+	tracer := provider.Tracer(instrumentationName)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			route := utils.RouteTemplate(request)
+			ctx := propagator.Extract(request.Context(), propagation.HeaderCarrier(request.Header))
+			ctx, span := tracer.Start(ctx, request.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPMethodKey.String(request.Method),
+					semconv.HTTPRouteKey.String(route),
+					semconv.HTTPTargetKey.String(request.URL.Path),
+					semconv.HTTPUserAgentKey.String(request.UserAgent()),
+				),
+			)
+			defer span.End()
+
+			if spanContext := span.SpanContext(); spanContext.HasTraceID() {
+				ctx = logging.WithLogger(ctx, logging.FromContext(ctx).With(zap.String("trace_id", spanContext.TraceID().String())))
+			}
+
+			recorder := utils.NewStatusRecorder(writer)
+			next.ServeHTTP(recorder, request.WithContext(ctx))
+
+			span.SetAttributes(semconv.HTTPStatusCodeKey.Int(recorder.Status))
+			//client errors are the caller's fault, only server errors fail the span
+			if recorder.Status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(recorder.Status))
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"class-management/internal/dto"
+	"class-management/internal/service/teacher"
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//teacherService records a span for every call to the wrapped service. The span is passed on in the context,
+//so the queries of the call are its children.
+type teacherService struct {
+	next   teacher.TeacherService
+	tracer trace.Tracer
+}
+
+//NewTeacherService wraps a TeacherService with tracing
+func NewTeacherService(next teacher.TeacherService, provider trace.TracerProvider) teacher.TeacherService {
+	return &teacherService{
+		next:   next,
+		tracer: provider.Tracer(instrumentationName),
+	}
+}
+
+//Start the span of a call to method
+func (s *teacherService) start(ctx context.Context, method string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, "TeacherService."+method, trace.WithAttributes(attributes...))
+}
+
+//End the span of a call, failing it when the call returned an error
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (s *teacherService) RegisterStudents(ctx context.Context, req dto.RegisterStudentsRequest) (dto.RegistrationResponse, error) {
+	ctx, span := s.start(ctx, "RegisterStudents", attribute.Int("students", len(req.Students)), attribute.Bool("atomic", req.Atomic))
+	response, err := s.next.RegisterStudents(ctx, req)
+	end(span, err)
+	return response, err
+}
+
+func (s *teacherService) DeregisterStudents(ctx context.Context, req dto.DeregisterStudentsRequest) error {
+	ctx, span := s.start(ctx, "DeregisterStudents", attribute.Int("students", len(req.Students)))
+	err := s.next.DeregisterStudents(ctx, req)
+	end(span, err)
+	return err
+}
+
+func (s *teacherService) SuspendStudent(ctx context.Context, req dto.SuspendRequest) error {
+	ctx, span := s.start(ctx, "SuspendStudent")
+	err := s.next.SuspendStudent(ctx, req)
+	end(span, err)
+	return err
+}
+
+func (s *teacherService) UnsuspendStudent(ctx context.Context, req dto.UnsuspendRequest) error {
+	ctx, span := s.start(ctx, "UnsuspendStudent")
+	err := s.next.UnsuspendStudent(ctx, req)
+	end(span, err)
+	return err
+}
+
+func (s *teacherService) ChangeStudentStatus(ctx context.Context, req dto.ChangeStudentStatusRequest) error {
+	ctx, span := s.start(ctx, "ChangeStudentStatus", attribute.String("status", req.Status))
+	err := s.next.ChangeStudentStatus(ctx, req)
+	end(span, err)
+	return err
+}
+
+func (s *teacherService) GraduateStudents(ctx context.Context, req dto.GraduateStudentsRequest) (dto.GraduateStudentsResponse, error) {
+	ctx, span := s.start(ctx, "GraduateStudents", attribute.Int("students", len(req.Students)))
+	response, err := s.next.GraduateStudents(ctx, req)
+	end(span, err)
+	return response, err
+}
+
+func (s *teacherService) CommonStudentsOfTeachers(ctx context.Context, req dto.CommonStudentsRequest) (dto.CommonStudentsResponse, error) {
+	ctx, span := s.start(ctx, "CommonStudentsOfTeachers", attribute.Int("teachers", len(req.Teachers)))
+	response, err := s.next.CommonStudentsOfTeachers(ctx, req)
+	span.SetAttributes(attribute.Int64("total", response.Total))
+	end(span, err)
+	return response, err
+}
+
+func (s *teacherService) FetchStudentsForNotification(ctx context.Context, req dto.FetchStudentsForNotificationRequest) (dto.FetchStudentsForNotificationResponse, error) {
+	ctx, span := s.start(ctx, "FetchStudentsForNotification", attribute.Bool("include_graduated", req.IncludeGraduated))
+	response, err := s.next.FetchStudentsForNotification(ctx, req)
+	span.SetAttributes(
+		attribute.Int("recipients", len(response.Recipients)),
+		attribute.Int("dropped_mentions", len(response.DroppedMentions)),
+	)
+	end(span, err)
+	return response, err
+}
+
+func (s *teacherService) RegisterTeachers(ctx context.Context, req dto.RegisterTeachersRequest) (dto.RegistrationResponse, error) {
+	ctx, span := s.start(ctx, "RegisterTeachers", attribute.Int("teachers", len(req.Teachers)))
+	response, err := s.next.RegisterTeachers(ctx, req)
+	end(span, err)
+	return response, err
+}
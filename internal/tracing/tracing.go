@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//Name of the tracer the middleware, the GORM plugin and the service decorator create spans with
+const instrumentationName = "class-management/internal/tracing"
+
+//Trace context and baggage are read from the W3C traceparent, tracestate and baggage headers
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+//Config selects and configures the exporter spans are sent to
+type Config struct {
+	//Exporter is one of none, stdout or otlp. Defaults to none.
+	Exporter string `json:"exporter"`
+
+	//service.name of the spans
+	ServiceName string `json:"service_name"`
+
+	//Share of new traces that are recorded, from 0 to 1. Requests that are part of a trace started by the
+	//caller follow the caller's decision.
+	SampleRatio float64 `json:"sample_ratio"`
+
+	//host:port of the OTLP/HTTP collector. Defaults to the OTEL_EXPORTER_OTLP_ENDPOINT variable, or localhost:4318.
+	OTLPEndpoint string `json:"otlp_endpoint"`
+	//Send to the collector over plain http rather than https
+	OTLPInsecure bool `json:"otlp_insecure"`
+}
+
+//New builds the tracer provider selected by the config. With the none exporter no span is recorded, but a trace
+//started by the caller is still followed so its ID shows up in the logs. The returned shutdown flushes the spans
+//that have not been exported yet.
+func New(ctx context.Context, config Config, out io.Writer) (trace.TracerProvider, func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch strings.ToLower(config.Exporter) {
+	case "", "none":
+		return trace.NewNoopTracerProvider(), func(context.Context) error { return nil }, nil
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(out))
+	case "otlp":
+		var options []otlptracehttp.Option
+		if config.OTLPEndpoint != "" {
+			options = append(options, otlptracehttp.WithEndpoint(config.OTLPEndpoint))
+		}
+		if config.OTLPInsecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, options...)
+	default:
+		return nil, nil, fmt.Errorf("unknown trace exporter %q", config.Exporter)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serviceResource, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(config.ServiceName)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(serviceResource),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	return provider, provider.Shutdown, nil
+}
@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+//RouteTemplate returns the path template of the route that matched the request, such as /api/students/{email},
+//or "unmatched" when no route did. Metrics and traces use it rather than the path so emails and ids in the path
+//don't make every request look different.
+func RouteTemplate(request *http.Request) string {
+	if current := mux.CurrentRoute(request); current != nil {
+		if template, err := current.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return "unmatched"
+}
@@ -0,0 +1,34 @@
+package utils
+
+import "net/http"
+
+//StatusRecorder wraps a ResponseWriter to remember the status code and the number of body bytes written
+type StatusRecorder struct {
+	http.ResponseWriter
+	Status int
+	Bytes  int
+
+	wroteHeader bool
+}
+
+func NewStatusRecorder(writer http.ResponseWriter) *StatusRecorder {
+	return &StatusRecorder{
+		ResponseWriter: writer,
+		Status:         http.StatusOK,
+	}
+}
+
+func (r *StatusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.Status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *StatusRecorder) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	written, err := r.ResponseWriter.Write(data)
+	r.Bytes += written
+	return written, err
+}